@@ -3,10 +3,15 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"time"
 
 	"github.com/firebase/genkit/go/genkit"
 	"github.com/firebase/genkit/go/plugins/googlegenai"
 	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/sessions"
 )
 
 func main() {
@@ -19,12 +24,30 @@ func main() {
 		log.Fatal("error during genkit.Init")
 	}
 
+	promptDir := os.Getenv("PROMPTS_DIR")
+	if promptDir == "" {
+		promptDir = prompts.DefaultDir
+	}
+	promptStore, err := prompts.Load(promptDir)
+	if err != nil {
+		log.Fatalf("error loading prompts: %v", err)
+	}
+	flows.SetPromptStore(promptStore)
+	flows.SetSessionStore(sessions.NewMemoryStore(30*time.Minute, 5*time.Minute))
+
+	// Held notes and Safe flows paused for approval wait in memory, to be decided or
+	// resumed from the Developer UI
+	flows.SetReviewQueue(review.NewMemoryStore(nil))
+	flows.SetApprovalMode(os.Getenv("SAFE_APPROVAL") == "true")
+
 	// Register flows
 	flows.RegisterWelcomeNoteFlowV1(g, "welcomeNoteFlowV1")
 	flows.RegisterWelcomeNoteFlowV2(g, "welcomeNoteFlowV2")
 	flows.RegisterWelcomeNoteFlowV3(g, "welcomeNoteFlowV3")
 	flows.RegisterWelcomeNoteFlowSafe(g, "welcomeNoteFlowSafe")
+	flows.RegisterWelcomeNoteFlowSafeResume(g, "welcomeNoteFlowSafeResume")
 	flows.RegisterWelcomeNoteFlowSmart(g, "welcomeNoteFlowSmart")
+	flows.RegisterWelcomeNoteFlowRefine(g, "welcomeNoteFlowRefine")
 
 	// block indefinitely
 	select {}
@@ -0,0 +1,137 @@
+// Command moderation-eval runs the Safe flow's moderation over a set of labeled fixture
+// notes and reports recall per language: how many of the unsafe notes were sanitized,
+// held for review or blocked, and how many safe notes were flagged anyway.
+//
+//	GEMINI_API_KEY=... go run ./cmd/moderation-eval -fixtures fixtures/moderation/multilingual.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/firebase/genkit/go/plugins/googlegenai"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// fixture is one labeled note
+type fixture struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+	Note     string `json:"note"`
+	Unsafe   bool   `json:"unsafe"`             // moderation should sanitize, hold or block it
+	Category string `json:"category,omitempty"` // the category that makes it unsafe
+}
+
+// tally counts the outcomes for one language
+type tally struct {
+	unsafe, caught, safe, flagged int
+	missed                        []string
+}
+
+func (t *tally) recall() float64 {
+	if t.unsafe == 0 {
+		return 1
+	}
+	return float64(t.caught) / float64(t.unsafe)
+}
+
+func main() {
+	fixturesPath := flag.String("fixtures", "fixtures/moderation/multilingual.json", "JSON file of labeled notes")
+	minRecall := flag.Float64("min-recall", 0, "exit with status 1 if any language's recall is below this")
+	pivot := flag.Bool("pivot", true, "also moderate an English translation of notes in other languages")
+	policy := flag.String("policy", "", "moderation policy preset: strict, standard or lenient; empty leaves decisions to the moderator")
+	flag.Parse()
+
+	data, err := os.ReadFile(*fixturesPath)
+	if err != nil {
+		log.Fatalf("error reading fixtures: %v", err)
+	}
+	var fixtures []fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		log.Fatalf("error reading fixtures: %v", err)
+	}
+
+	ctx := context.Background()
+	g := genkit.Init(ctx,
+		genkit.WithPlugins(&googlegenai.GoogleAI{}),
+		genkit.WithDefaultModel("googleai/gemini-2.5-flash"),
+	)
+	if g == nil {
+		log.Fatal("error during genkit.Init")
+	}
+
+	promptDir := os.Getenv("PROMPTS_DIR")
+	if promptDir == "" {
+		promptDir = prompts.DefaultDir
+	}
+	promptStore, err := prompts.Load(promptDir)
+	if err != nil {
+		log.Fatalf("error loading prompts: %v", err)
+	}
+	flows.SetPromptStore(promptStore)
+	flows.SetModerationPivot(*pivot)
+	if *policy != "" {
+		p, err := moderation.Preset(*policy)
+		if err != nil {
+			log.Fatalf("error loading moderation policy: %v", err)
+		}
+		flows.SetModerationPolicy(p)
+	}
+
+	tallies := map[string]*tally{}
+	var languages []string
+	for _, f := range fixtures {
+		t, ok := tallies[f.Language]
+		if !ok {
+			t = &tally{}
+			tallies[f.Language] = t
+			languages = append(languages, f.Language)
+		}
+
+		result, err := flows.ModerateNote(ctx, g, f.Note, &types.WelcomeNoteInput{Language: f.Language})
+		if err != nil {
+			log.Fatalf("error moderating %s: %v", f.ID, err)
+		}
+		flagged := result.Decision != moderation.DecisionAllow
+		switch {
+		case f.Unsafe && flagged:
+			t.unsafe++
+			t.caught++
+		case f.Unsafe:
+			t.unsafe++
+			t.missed = append(t.missed, f.ID)
+		case flagged:
+			t.safe++
+			t.flagged++
+			t.missed = append(t.missed, f.ID+" (safe, "+result.Decision+")")
+		default:
+			t.safe++
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tUNSAFE\tCAUGHT\tRECALL\tSAFE\tFLAGGED\tMISSES")
+	failed := false
+	for _, language := range languages {
+		t := tallies[language]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%d\t%d\t%v\n", language, t.unsafe, t.caught, t.recall(), t.safe, t.flagged, t.missed)
+		failed = failed || t.recall() < *minRecall
+	}
+	w.Flush()
+
+	if failed {
+		below := slices.DeleteFunc(slices.Clone(languages), func(l string) bool { return tallies[l].recall() >= *minRecall })
+		fmt.Fprintf(os.Stderr, "recall below %.2f for %v\n", *minRecall, below)
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
@@ -14,7 +15,16 @@ import (
 	"github.com/firebase/genkit/go/plugins/server"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/csrf"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
 	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/guard"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/locales"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/routing"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/sessions"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/tones"
 	"github.com/vnaveen-mh/welcome-note-generator/logging"
 	"github.com/vnaveen-mh/welcome-note-generator/web/config"
 	"github.com/vnaveen-mh/welcome-note-generator/web/handlers"
@@ -27,6 +37,17 @@ var (
 	appVersion = "wng-0.1"
 )
 
+// Names the flows are registered under, which model routes refer to them by
+var flowNames = []string{
+	"welcomeNoteFlowV1",
+	"welcomeNoteFlowV2",
+	"welcomeNoteFlowV3",
+	"welcomeNoteFlowSafe",
+	"welcomeNoteFlowSafeResume",
+	"welcomeNoteFlowSmart",
+	"welcomeNoteFlowRefine",
+}
+
 func RegisterOllamaGptOss(g *genkit.Genkit, ollamaPlugin *ollama.Ollama) {
 	model := ollamaPlugin.DefineModel(
 		g,
@@ -56,7 +77,7 @@ func main() {
 	// Initialize Genkit
 	g := genkit.Init(ctx,
 		genkit.WithPlugins(&googlegenai.GoogleAI{}, ollamaPlugin),
-		genkit.WithDefaultModel("googleai/gemini-2.5-flash"),
+		genkit.WithDefaultModel(cfg.Models.Default),
 	)
 	if g == nil {
 		log.Fatal("error during genkit.Init")
@@ -64,12 +85,236 @@ func main() {
 
 	RegisterOllamaGptOss(g, ollamaPlugin)
 
+	// Load versioned prompt templates
+	promptStore, err := prompts.Load(cfg.Prompts.Dir)
+	if err != nil {
+		log.Fatalf("error loading prompts: %v", err)
+	}
+	flows.SetPromptStore(promptStore)
+	slog.Info("loaded prompts",
+		slog.String("dir", promptStore.Dir()),
+		slog.Any("names", promptStore.Names()),
+	)
+
+	// Model each flow and step runs on: the routes file when configured, otherwise the
+	// default model for everything
+	modelRoutes := &routing.Routes{}
+	if cfg.Models.RoutesFile != "" {
+		modelRoutes, err = routing.LoadFile(cfg.Models.RoutesFile)
+		if err != nil {
+			log.Fatalf("error loading model routes: %v", err)
+		}
+	}
+	modelRoutes.Default = cmp.Or(modelRoutes.Default, cfg.Models.Default)
+	if err := modelRoutes.Validate(flowNames, promptStore.Names()); err != nil {
+		log.Fatalf("error loading model routes: %v", err)
+	}
+	for _, model := range modelRoutes.Models() {
+		if genkit.LookupModel(g, model) == nil {
+			log.Fatalf("error loading model routes: unknown model %q", model)
+		}
+	}
+	flows.SetModelRoutes(modelRoutes)
+	slog.Info("loaded model routes",
+		slog.String("file", cfg.Models.RoutesFile),
+		slog.String("default", modelRoutes.Default),
+		slog.Any("models", modelRoutes.Models()),
+	)
+
+	// Regenerate notes whose measured length misses the requested range
+	flows.SetLengthRetries(cfg.Quality.LengthRetries)
+	flows.SetLanguageRetries(cfg.Quality.LanguageRetries)
+
+	// Locales notes can be requested in
+	supportedLocales := cfg.Locales.Supported
+	if len(supportedLocales) == 0 {
+		supportedLocales = locales.DefaultTags
+	}
+	localeRegistry, err := locales.New(supportedLocales, cfg.Locales.Default)
+	if err != nil {
+		log.Fatalf("error loading locales: %v", err)
+	}
+	flows.SetLocales(localeRegistry)
+	slog.Info("loaded locales",
+		slog.String("default", localeRegistry.Default().Tag),
+		slog.Int("count", len(localeRegistry.List())),
+	)
+
+	// Tone registry: a JSON file when configured, otherwise the built-in tones in memory
+	if cfg.Tones.File != "" {
+		toneStore, err := tones.NewFileStore(cfg.Tones.File)
+		if err != nil {
+			log.Fatalf("error loading tones: %v", err)
+		}
+		flows.SetToneStore(toneStore)
+		slog.Info("loaded tones", slog.String("file", cfg.Tones.File))
+	}
+
+	// Deployment policy: allowed flows and tones, and the audience level
+	if err := cfg.Policy.Validate(); err != nil {
+		log.Fatalf("error loading policy: %v", err)
+	}
+	for _, model := range cfg.Policy.AllowedModels {
+		if genkit.LookupModel(g, model) == nil {
+			log.Fatalf("error loading policy: unknown model %q", model)
+		}
+	}
+	flows.SetTonePolicy(cfg.Policy.CheckTone)
+	flows.SetModelPolicy(cfg.Policy.CheckModel)
+	slog.Info("loaded policy",
+		slog.String("audience", cfg.Policy.Audience),
+		slog.Any("flows", cfg.Policy.EnabledFlows()),
+		slog.Any("models", cfg.Policy.EnabledModels()),
+	)
+
+	// Local moderation rules, run before the LLM moderator
+	switch {
+	case !cfg.Moderation.Rules:
+		flows.SetModerationRules(nil)
+		slog.Info("moderation rules disabled")
+	case cfg.Moderation.RulesFile != "":
+		rules, err := moderation.LoadFile(cfg.Moderation.RulesFile)
+		if err != nil {
+			log.Fatalf("error loading moderation rules: %v", err)
+		}
+		engine, err := moderation.New(rules)
+		if err != nil {
+			log.Fatalf("error loading moderation rules: %v", err)
+		}
+		flows.SetModerationRules(engine)
+		slog.Info("loaded moderation rules", slog.String("file", cfg.Moderation.RulesFile))
+	}
+
+	// Per-deployment thresholds deciding allow, sanitize, review or block
+	var moderationPolicy *moderation.Policy
+	switch {
+	case cfg.Moderation.PolicyFile != "":
+		moderationPolicy, err = moderation.LoadPolicyFile(cfg.Moderation.PolicyFile)
+	case cfg.Moderation.Policy != "":
+		moderationPolicy, err = moderation.Preset(cfg.Moderation.Policy)
+	}
+	if err != nil {
+		log.Fatalf("error loading moderation policy: %v", err)
+	}
+	if moderationPolicy != nil {
+		flows.SetModerationPolicy(moderationPolicy)
+		slog.Info("loaded moderation policy", slog.String("policy", moderationPolicy.Name))
+	}
+	flows.SetRemediationRetries(cfg.Moderation.RemediationRetries)
+	flows.SetModerationPivot(cfg.Moderation.Pivot)
+
+	// Several moderators voting on each note instead of one
+	moderationEnsemble, err := moderation.ParseEnsemble(cfg.Moderation.Ensemble, cfg.Moderation.Voting, cfg.Moderation.Quorum)
+	if err != nil {
+		log.Fatalf("error loading moderation ensemble: %v", err)
+	}
+	if moderationEnsemble != nil {
+		for _, m := range moderationEnsemble.Moderators {
+			switch {
+			case m.Name == moderation.ModeratorRules && !cfg.Moderation.Rules:
+				log.Fatalf("error loading moderation ensemble: %q votes but MODERATION_RULES is off", m.Name)
+			case m.Name != moderation.ModeratorRules && genkit.LookupModel(g, m.Name) == nil:
+				log.Fatalf("error loading moderation ensemble: unknown model %q", m.Name)
+			}
+		}
+		flows.SetModerationEnsemble(moderationEnsemble)
+		slog.Info("loaded moderation ensemble",
+			slog.String("voting", moderationEnsemble.Voting),
+			slog.Int("quorum", moderationEnsemble.Quorum),
+			slog.Int("moderators", len(moderationEnsemble.Moderators)),
+		)
+	}
+
+	// Prompt injection screening of user text, before any prompt is rendered
+	if cfg.InputGuard.Mode == "off" {
+		flows.SetInputGuard(nil, false)
+		slog.Info("input guard disabled")
+	} else {
+		inputGuard, err := guard.New(cfg.InputGuard.Mode)
+		if err != nil {
+			log.Fatalf("error configuring input guard: %v", err)
+		}
+		flows.SetInputGuard(inputGuard, cfg.InputGuard.Classifier)
+		slog.Info("input guard enabled",
+			slog.String("mode", inputGuard.Mode()),
+			slog.Bool("classifier", cfg.InputGuard.Classifier),
+		)
+	}
+
+	// Personal data is swapped for placeholders around every model call
+	flows.SetPseudonymization(cfg.Privacy.Pseudonymize)
+	slog.Info("pseudonymization", slog.Bool("enabled", cfg.Privacy.Pseudonymize))
+
+	// Append-only log of sanitized, held and blocked notes
+	var auditStore audit.Store
+	switch cfg.Audit.Store {
+	case "jsonl":
+		auditStore, err = audit.NewJSONLStore(cmp.Or(cfg.Audit.Path, "data/moderation-audit.jsonl"))
+	case "sqlite":
+		auditStore, err = audit.NewSQLiteStore(cmp.Or(cfg.Audit.Path, "data/moderation-audit.db"))
+	case "off":
+	default:
+		err = fmt.Errorf("unknown store %q, want jsonl, sqlite or off", cfg.Audit.Store)
+	}
+	if err != nil {
+		log.Fatalf("error opening moderation audit log: %v", err)
+	}
+	flows.SetAuditStore(auditStore)
+	flows.SetAuditRawNotes(cfg.Audit.Raw)
+	slog.Info("moderation audit", slog.String("store", cfg.Audit.Store), slog.Bool("raw", cfg.Audit.Raw))
+
+	// Reviewers sign what they decide with their own token
+	reviewers, err := middleware.ParseReviewers(cfg.Review.Token)
+	if err != nil {
+		log.Fatalf("error loading reviewers: %v", err)
+	}
+	if cfg.Review.Approval && len(reviewers) == 0 {
+		log.Fatal("error enabling approval mode: SAFE_APPROVAL needs REVIEW_TOKEN, or anyone could approve notes")
+	}
+
+	// Notes that need a person to check them wait in the review queue
+	var reviewQueue review.Store
+	switch cfg.Review.Queue {
+	case "memory":
+		reviewQueue = review.NewMemoryStore(nil)
+	case "file":
+		reviewQueue, err = review.NewFileStore(cfg.Review.Path)
+	case "off":
+	default:
+		err = fmt.Errorf("unknown queue %q, want memory, file or off", cfg.Review.Queue)
+	}
+	if err != nil {
+		log.Fatalf("error opening review queue: %v", err)
+	}
+	if reviewQueue != nil && len(reviewers) == 0 {
+		log.Fatalf("error opening review queue: REVIEW_QUEUE=%s needs REVIEW_TOKEN, or anyone could decide what notes say", cfg.Review.Queue)
+	}
+	flows.SetReviewQueue(reviewQueue)
+	slog.Info("review queue", slog.String("queue", cfg.Review.Queue), slog.Int("reviewers", len(reviewers)))
+
+	// Approval mode pauses every Safe flow note in the review queue until an approver decides
+	if cfg.Review.Approval {
+		if reviewQueue == nil {
+			log.Fatal("error enabling approval mode: SAFE_APPROVAL needs a review queue, but REVIEW_QUEUE is off")
+		}
+		if cfg.Review.Queue == "memory" {
+			slog.Warn("approval mode keeps paused flows in memory; they are lost on restart unless REVIEW_QUEUE=file")
+		}
+	}
+	flows.SetApprovalMode(cfg.Review.Approval)
+	slog.Info("approval mode", slog.Bool("enabled", cfg.Review.Approval))
+
+	// Keep Smart flow conversations in memory
+	flows.SetSessionStore(sessions.NewMemoryStore(cfg.Sessions.TTL, cfg.Sessions.CleanupInterval))
+
 	// Register all flows
 	flows.RegisterWelcomeNoteFlowV1(g, "welcomeNoteFlowV1")
 	flows.RegisterWelcomeNoteFlowV2(g, "welcomeNoteFlowV2")
 	flows.RegisterWelcomeNoteFlowV3(g, "welcomeNoteFlowV3")
 	flows.RegisterWelcomeNoteFlowSafe(g, "welcomeNoteFlowSafe")
+	flows.RegisterWelcomeNoteFlowSafeResume(g, "welcomeNoteFlowSafeResume")
 	flows.RegisterWelcomeNoteFlowSmart(g, "welcomeNoteFlowSmart")
+	flows.RegisterWelcomeNoteFlowRefine(g, "welcomeNoteFlowRefine")
 
 	// Set up Gin router
 	gin.SetMode(gin.ReleaseMode)
@@ -90,19 +335,70 @@ func main() {
 	// Serve the main page
 	router.GET("/", func(c *gin.Context) {
 		csrfToken := c.GetString("csrf_token")
-		component := templates.Index(csrfToken)
+		component := templates.Index(csrfToken, cfg.Policy.EnabledFlows(), reviewQueue != nil)
 		templ.Handler(component).ServeHTTP(c.Writer, c.Request)
 	})
 
+	// Supported locales and tones for the dropdowns; cheap, so not rate limited
+	router.GET("/api/locales", handlers.LocalesHandler)
+	router.GET("/api/tones", handlers.ListTonesHandler)
+	router.GET("/api/tones/:name", handlers.GetToneHandler)
+
 	// API endpoints with IP-based rate limiting
 	api := router.Group("/api")
 	api.Use(middleware.RateLimit(&cfg.RateLimit))
+	api.Use(middleware.Policy(&cfg.Policy))
 	{
 		api.POST("/v1/generate", handlers.V1Handler)
 		api.POST("/v2/generate", handlers.V2Handler)
 		api.POST("/v3/generate", handlers.V3Handler)
 		api.POST("/safe/generate", handlers.SafeHandler)
 		api.POST("/smart/generate", handlers.SmartHandler)
+		api.POST("/refine/generate", handlers.RefineHandler)
+	}
+
+	// Tone registry writes, only for the admin: a saved tone's text reaches every user's
+	// prompts, and its safety class decides which audiences the policy offers it to
+	if cfg.Admin.Token != "" {
+		toneAdmin := router.Group("/api/tones")
+		toneAdmin.Use(middleware.RateLimit(&cfg.RateLimit))
+		toneAdmin.Use(middleware.AdminAuth(cfg.Admin.Token))
+		{
+			toneAdmin.PUT("/:name", handlers.SaveToneHandler)
+			toneAdmin.DELETE("/:name", handlers.DeleteToneHandler)
+		}
+	}
+
+	// Review queue: the requester follows a note by its review ID, reviewers list and decide
+	// them with the reviewer token
+	if reviewQueue != nil {
+		reviews := router.Group("/api/review")
+		reviews.Use(middleware.RateLimit(&cfg.RateLimit))
+		reviews.GET("/:id", handlers.ReviewStatusHandler)
+
+		decisions := reviews.Group("")
+		decisions.Use(middleware.ReviewerAuth(reviewers))
+		decisions.GET("", handlers.ListReviewsHandler)
+		decisions.POST("/:id/:action", handlers.DecideReviewHandler)
+	}
+
+	// Resuming a Safe flow paused for approval, only in approval mode
+	if cfg.Review.Approval {
+		resume := router.Group("/api/safe/resume")
+		resume.Use(middleware.RateLimit(&cfg.RateLimit))
+		resume.Use(middleware.Policy(&cfg.Policy))
+		resume.Use(middleware.ReviewerAuth(reviewers))
+		resume.POST("/:id", handlers.ResumeSafeHandler)
+	}
+
+	// Read-only admin endpoints, only when an admin token is configured
+	if cfg.Admin.Token != "" {
+		admin := router.Group("/api/admin")
+		admin.Use(middleware.RateLimit(&cfg.RateLimit))
+		admin.Use(middleware.AdminAuth(cfg.Admin.Token))
+		{
+			admin.GET("/moderation/events", handlers.ModerationEventsHandler)
+		}
 	}
 
 	// Static files (if needed)
@@ -0,0 +1,124 @@
+// Package audit keeps an append-only record of moderation events: every note the
+// moderator sanitized, held for review or blocked, with what it changed and why.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// ErrInvalid is returned for a query that can't be run, such as one whose time range ends
+// before it starts
+var ErrInvalid = errors.New("invalid audit query")
+
+// Default and maximum number of events a query returns
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Event is one moderation decision
+type Event struct {
+	ID            string                `json:"id"`
+	Time          time.Time             `json:"time"`
+	RequestID     string                `json:"requestId,omitempty"`
+	Flow          string                `json:"flow,omitempty"`
+	InputHash     string                `json:"inputHash,omitempty"`     // SHA-256 of the request input; the input itself isn't kept
+	OriginalNote  string                `json:"originalNote"`            // personal data replaced by placeholders unless raw notes are audited
+	SanitizedNote string                `json:"sanitizedNote,omitempty"` // pseudonymized like OriginalNote
+	Categories    []types.CategoryScore `json:"categories,omitempty"`
+	Decision      string                `json:"decision"` // sanitize | review | block
+	Reason        string                `json:"reason,omitempty"`
+}
+
+// NewEvent returns an event with a fresh ID, stamped now
+func NewEvent() Event {
+	return Event{ID: uuid.New().String(), Time: time.Now().UTC()}
+}
+
+// Query selects events. Zero values don't filter.
+type Query struct {
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Category string    // only events that scored this category above zero
+	Limit    int       // at most this many events, newest first; DefaultLimit when zero
+}
+
+// Normalize checks the query and fills in its limit
+func (q *Query) Normalize() error {
+	if !q.From.IsZero() && !q.To.IsZero() && !q.To.After(q.From) {
+		return fmt.Errorf("%w: to must be after from", ErrInvalid)
+	}
+	if q.Category != "" && !slices.Contains(types.ModerationCategories, q.Category) {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalid, q.Category)
+	}
+	switch {
+	case q.Limit < 0:
+		return fmt.Errorf("%w: limit must not be negative", ErrInvalid)
+	case q.Limit == 0:
+		q.Limit = DefaultLimit
+	case q.Limit > MaxLimit:
+		q.Limit = MaxLimit
+	}
+	return nil
+}
+
+// Match reports whether e is selected by the query's time range and category
+func (q Query) Match(e Event) bool {
+	if !q.From.IsZero() && e.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.Time.Before(q.To) {
+		return false
+	}
+	if q.Category == "" {
+		return true
+	}
+	return slices.ContainsFunc(e.Categories, func(c types.CategoryScore) bool {
+		return c.Category == q.Category && c.Severity > 0
+	})
+}
+
+// Store persists moderation events. There is no way to change or remove an event once
+// appended. Implementations must be safe for concurrent use.
+type Store interface {
+	// Append adds an event to the log
+	Append(ctx context.Context, e Event) error
+	// Query returns the events matching q, newest first
+	Query(ctx context.Context, q Query) ([]Event, error)
+	// Close releases the underlying file or database
+	Close() error
+}
+
+// HashInput returns the hex SHA-256 of the JSON encoding of input, so events from the
+// same request can be correlated without keeping what the user typed
+func HashInput(input any) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the HTTP request it serves
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // pure Go driver, so the binary still builds with CGO_ENABLED=0
+)
+
+// sqliteSchema creates the events table. The triggers make it append-only: SQLite
+// refuses to change or delete a row once it is written.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS moderation_events (
+	id             TEXT PRIMARY KEY,
+	time           INTEGER NOT NULL, -- Unix nanoseconds, UTC
+	request_id     TEXT NOT NULL DEFAULT '',
+	flow           TEXT NOT NULL DEFAULT '',
+	input_hash     TEXT NOT NULL DEFAULT '',
+	original_note  TEXT NOT NULL,
+	sanitized_note TEXT NOT NULL DEFAULT '',
+	categories     TEXT NOT NULL DEFAULT '[]', -- JSON array of {category, severity}
+	decision       TEXT NOT NULL,
+	reason         TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS moderation_events_time ON moderation_events (time);
+CREATE TRIGGER IF NOT EXISTS moderation_events_no_update BEFORE UPDATE ON moderation_events
+BEGIN SELECT RAISE(ABORT, 'moderation events are append-only'); END;
+CREATE TRIGGER IF NOT EXISTS moderation_events_no_delete BEFORE DELETE ON moderation_events
+BEGIN SELECT RAISE(ABORT, 'moderation events are append-only'); END;
+`
+
+// SQLiteStore keeps events in a SQLite database, indexed by time
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens the database at path, creating it and its schema if needed
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("opening audit database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("opening audit database: %w", err)
+	}
+	// one writer at a time; SQLite serializes them anyway
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating audit schema in %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Append(ctx context.Context, e Event) error {
+	categories, err := json.Marshal(e.Categories)
+	if err != nil {
+		return fmt.Errorf("appending audit event: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO moderation_events
+			(id, time, request_id, flow, input_hash, original_note, sanitized_note, categories, decision, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.Time.UnixNano(), e.RequestID, e.Flow, e.InputHash, e.OriginalNote, e.SanitizedNote,
+		string(categories), e.Decision, e.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("appending audit event: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Query(ctx context.Context, q Query) ([]Event, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+
+	var where []string
+	var args []any
+	if !q.From.IsZero() {
+		where = append(where, "time >= ?")
+		args = append(args, q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "time < ?")
+		args = append(args, q.To.UnixNano())
+	}
+	if q.Category != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM json_each(categories)
+			WHERE json_extract(value, '$.category') = ? AND json_extract(value, '$.severity') > 0)`)
+		args = append(args, q.Category)
+	}
+	query := `SELECT id, time, request_id, flow, input_hash, original_note, sanitized_note, categories, decision, reason
+		FROM moderation_events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY time DESC LIMIT ?"
+	args = append(args, q.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit database: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var nanos int64
+		var categories string
+		if err := rows.Scan(&e.ID, &nanos, &e.RequestID, &e.Flow, &e.InputHash, &e.OriginalNote,
+			&e.SanitizedNote, &categories, &e.Decision, &e.Reason); err != nil {
+			return nil, fmt.Errorf("querying audit database: %w", err)
+		}
+		e.Time = time.Unix(0, nanos).UTC()
+		if err := json.Unmarshal([]byte(categories), &e.Categories); err != nil {
+			return nil, fmt.Errorf("querying audit database: event %s: %w", e.ID, err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying audit database: %w", err)
+	}
+	return events, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// stores opens each kind of store at path
+var stores = map[string]func(path string) (Store, error){
+	"jsonl":  func(path string) (Store, error) { return NewJSONLStore(path) },
+	"sqlite": func(path string) (Store, error) { return NewSQLiteStore(path) },
+}
+
+func TestStores(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	event := func(i int, category, decision string) Event {
+		e := NewEvent()
+		e.Time = base.Add(time.Duration(i) * time.Hour)
+		e.Flow = "welcomeNoteFlowSafe"
+		e.OriginalNote = "Welcome, [PERSON_1]!"
+		e.Decision = decision
+		e.Categories = []types.CategoryScore{{Category: category, Severity: 0.7}}
+		return e
+	}
+	events := []Event{
+		event(0, types.CategoryToxicity, "sanitize"),
+		event(1, types.CategoryHate, "block"),
+		event(2, types.CategoryToxicity, "review"),
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "audit", "events."+name)
+			s, err := open(path)
+			if err != nil {
+				t.Fatalf("opening the store = %v", err)
+			}
+			for _, e := range events {
+				if err := s.Append(ctx, e); err != nil {
+					t.Fatalf("Append = %v", err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close = %v", err)
+			}
+
+			// reopened, so the events come from the file
+			s, err = open(path)
+			if err != nil {
+				t.Fatalf("reopening the store = %v", err)
+			}
+			defer s.Close()
+
+			tests := []struct {
+				name string
+				q    Query
+				want []Event // in order
+			}{
+				{"all, newest first", Query{}, []Event{events[2], events[1], events[0]}},
+				{"from is inclusive, to exclusive", Query{From: events[1].Time, To: events[2].Time}, []Event{events[1]}},
+				{"category", Query{Category: types.CategoryToxicity}, []Event{events[2], events[0]}},
+				{"limit", Query{Limit: 1}, []Event{events[2]}},
+			}
+			for _, tt := range tests {
+				got, err := s.Query(ctx, tt.q)
+				if err != nil {
+					t.Fatalf("%s: Query = %v", tt.name, err)
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("%s: %d events, want %d", tt.name, len(got), len(tt.want))
+				}
+				for i := range got {
+					if got[i].ID != tt.want[i].ID || got[i].Decision != tt.want[i].Decision || !got[i].Time.Equal(tt.want[i].Time) {
+						t.Errorf("%s: event %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+					}
+				}
+			}
+
+			if _, err := s.Query(ctx, Query{From: events[2].Time, To: events[0].Time}); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Query with to before from = %v, want %v", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestJSONLStoreSkipsTornLine(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	s, err := NewJSONLStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONLStore = %v", err)
+	}
+	defer s.Close()
+	first := NewEvent()
+	first.Decision = "block"
+	if err := s.Append(ctx, first); err != nil {
+		t.Fatalf("Append = %v", err)
+	}
+
+	// a crash mid-write leaves a partial line
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"torn","time":`)
+	f.Close()
+
+	got, err := s.Query(ctx, Query{})
+	if err != nil {
+		t.Fatalf("Query = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != first.ID {
+		t.Errorf("events = %+v, want only the complete one", got)
+	}
+}
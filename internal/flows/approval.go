@@ -0,0 +1,261 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Whether every Safe flow note waits for an approver; see SetApprovalMode
+var approvalMode bool
+
+// SetApprovalMode makes the Safe flow pause after moderation until an approver approves,
+// edits or rejects the note. The paused flow's state is kept in the review queue, which
+// must be set, and the flow registered with RegisterWelcomeNoteFlowSafeResume picks it up
+// again. The pause is a Genkit interrupt of the approval gate's tool call. It must be called at startup, before any flow runs.
+func SetApprovalMode(on bool) {
+	approvalMode = on
+}
+
+// RegisterWelcomeNoteFlowSafeResume registers the flow that resumes a Safe flow paused for
+// approval with the approver's decision and returns the Safe flow's final output.
+func RegisterWelcomeNoteFlowSafeResume(g *genkit.Genkit, name string) {
+	tool := defineApprovalGate(g)
+	f := genkit.DefineFlow(g, name, func(ctx context.Context, input *types.ApprovalInput) (*types.SafeWelcomeNoteOutput, error) {
+		return resumeApproval(ctx, g, tool, input)
+	})
+
+	SetFlow(name, f)
+}
+
+// The approval gate: a Genkit tool that asks for approval by interrupting its call, and the
+// in-process model that calls it. The Safe flow pauses on the interrupt, and resumes by
+// generating again with the approver's answer as the tool's response.
+const (
+	approvalToolName  = "requestApproval"
+	approvalGateModel = "welcome/approval-gate"
+)
+
+// approvalRequest is the input of the requestApproval tool
+type approvalRequest struct {
+	ReviewID string `json:"reviewId"`
+}
+
+// approvalResponse is the approver's answer to an interrupted requestApproval call
+type approvalResponse struct {
+	types.Approval
+	FinalNote string `json:"finalNote,omitempty"`
+}
+
+// approvalState is the paused Safe flow kept as the review item's state: its input and
+// output, and the generation interrupted for approval
+type approvalState struct {
+	Input    *types.WelcomeNoteInput      `json:"input,omitempty"` // moderates an approver's edit
+	Output   *types.SafeWelcomeNoteOutput `json:"output"`
+	Messages []*ai.Message                `json:"messages"`
+}
+
+// defineApprovalGate defines the approval gate's tool and model in g, once, and returns the tool
+func defineApprovalGate(g *genkit.Genkit) ai.Tool {
+	if tool := genkit.LookupTool(g, approvalToolName); tool != nil {
+		return tool
+	}
+	genkit.DefineModel(g, approvalGateModel, &ai.ModelOptions{
+		Label:    "Approval gate",
+		Supports: &ai.ModelSupports{Multiturn: true, Tools: true},
+	}, approvalGate)
+	return genkit.DefineTool(g, approvalToolName, "Asks a person to approve the welcome note held in the review queue",
+		func(tc *ai.ToolContext, in approvalRequest) (approvalResponse, error) {
+			return approvalResponse{}, tc.Interrupt(&ai.InterruptOptions{Metadata: map[string]any{"reviewId": in.ReviewID}})
+		})
+}
+
+// approvalGate is the approval gate's model. It asks for approval of the review item in
+// the prompt, and once answered replies with the answer.
+func approvalGate(_ context.Context, req *ai.ModelRequest, _ ai.ModelStreamCallback) (*ai.ModelResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("approval gate: no prompt")
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role == ai.RoleTool {
+		for _, p := range last.Content {
+			if p.IsToolResponse() && p.ToolResponse.Name == approvalToolName {
+				answer, err := json.Marshal(p.ToolResponse.Output)
+				if err != nil {
+					return nil, fmt.Errorf("approval gate: %w", err)
+				}
+				return &ai.ModelResponse{Request: req, Message: ai.NewModelTextMessage(string(answer)), FinishReason: ai.FinishReasonStop}, nil
+			}
+		}
+		return nil, fmt.Errorf("approval gate: no answer from %s", approvalToolName)
+	}
+
+	var in approvalRequest
+	if err := json.Unmarshal([]byte(last.Text()), &in); err != nil || in.ReviewID == "" {
+		return nil, fmt.Errorf("approval gate: want a review ID, got %q", last.Text())
+	}
+	call := ai.NewToolRequestPart(&ai.ToolRequest{Name: approvalToolName, Ref: in.ReviewID, Input: in})
+	return &ai.ModelResponse{Request: req, Message: ai.NewModelMessage(call), FinishReason: ai.FinishReasonStop}, nil
+}
+
+// awaitApproval pauses the Safe flow on out, written for input: the gate's tool call is
+// interrupted, the flow and the interrupted generation are persisted in the review queue,
+// and the requester gets the output back with the note withheld
+func awaitApproval(ctx context.Context, g *genkit.Genkit, tool ai.Tool, input *types.WelcomeNoteInput, out *types.SafeWelcomeNoteOutput) (*types.SafeWelcomeNoteOutput, error) {
+	if reviewQueue == nil {
+		return nil, fmt.Errorf("pausing for approval: no review queue")
+	}
+	it := newReviewItem(ctx, review.KindApproval, out)
+	it.Reasons = append([]string{"approval mode: every note needs an approver"}, reviewReasons(out)...)
+
+	prompt, err := json.Marshal(approvalRequest{ReviewID: it.ID})
+	if err != nil {
+		return nil, fmt.Errorf("pausing for approval: %w", err)
+	}
+	resp, err := genkit.Generate(ctx, g,
+		ai.WithModelName(approvalGateModel),
+		ai.WithTools(tool),
+		ai.WithPrompt(string(prompt)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("pausing for approval: %w", err)
+	}
+	if resp.FinishReason != ai.FinishReasonInterrupted || len(resp.Interrupts()) != 1 {
+		return nil, fmt.Errorf("pausing for approval: %s wasn't interrupted", approvalToolName)
+	}
+	it.State, err = json.Marshal(approvalState{Input: input, Output: out, Messages: resp.History()})
+	if err != nil {
+		return nil, fmt.Errorf("pausing for approval: %w", err)
+	}
+	if err := reviewQueue.Add(ctx, it); err != nil {
+		return nil, fmt.Errorf("pausing for approval: %w", err)
+	}
+
+	paused := withheld(out)
+	paused.ReviewID = it.ID
+	paused.ReviewStatus = it.Status
+	paused.Approval = &types.Approval{Status: it.Status}
+	return paused, nil
+}
+
+// resumeApproval answers a paused Safe flow's interrupted tool call with an approver's
+// decision, records the decision and returns the flow's output. The decision is recorded
+// only once the flow has resumed, so a failed resume can be retried. An edited note is
+// moderated like a generated one and refused if blocked.
+func resumeApproval(ctx context.Context, g *genkit.Genkit, tool ai.Tool, input *types.ApprovalInput) (*types.SafeWelcomeNoteOutput, error) {
+	if reviewQueue == nil {
+		return nil, fmt.Errorf("resuming approval: no review queue")
+	}
+	it, err := reviewQueue.Get(ctx, input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("resuming approval: %w", err)
+	}
+	if it.Kind != review.KindApproval {
+		return nil, fmt.Errorf("resuming approval: %w: %s isn't waiting for approval", review.ErrInvalid, it.ID)
+	}
+	var state approvalState
+	if err := json.Unmarshal(it.State, &state); err != nil {
+		return nil, fmt.Errorf("resuming approval: reading the paused flow: %w", err)
+	}
+	interrupt := pausedCall(state.Messages)
+	if state.Output == nil || interrupt == nil {
+		return nil, fmt.Errorf("resuming approval: %s has no paused flow", it.ID)
+	}
+
+	// check the decision applies without recording it yet
+	d := review.Decision{
+		Action:   input.Action,
+		Note:     input.Note,
+		Reviewer: input.Approver,
+		Comment:  input.Comment,
+	}
+	decided := it
+	if err := decided.Apply(d, time.Now()); err != nil {
+		return nil, fmt.Errorf("resuming approval: %w", err)
+	}
+	edit := decided.FinalNote
+	var edited *types.ModerationResult
+	if decided.Status == review.StatusEdited {
+		edited, err = moderateWelcomeNote(ctx, g, edit, state.Input)
+		if err != nil {
+			return nil, fmt.Errorf("resuming approval: moderating the edit: %w", err)
+		}
+		if edited.Blocked {
+			return nil, fmt.Errorf("resuming approval: %w: the edited note is blocked: %s", review.ErrInvalid, edited.DecisionReason)
+		}
+		if edited.SanitizedNote != "" {
+			decided.FinalNote = edited.SanitizedNote
+			d.Note = edited.SanitizedNote
+		}
+	}
+
+	resp, err := genkit.Generate(ctx, g,
+		ai.WithModelName(approvalGateModel),
+		ai.WithTools(tool),
+		ai.WithMessages(state.Messages...),
+		ai.WithToolResponses(tool.Respond(interrupt, approvalResponse{
+			Approval: types.Approval{
+				Status:    decided.Status,
+				Approver:  decided.Reviewer,
+				Comment:   decided.Comment,
+				DecidedAt: decided.ReviewedAt,
+			},
+			FinalNote: decided.FinalNote,
+		}, nil)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("resuming approval: %w", err)
+	}
+	var answer approvalResponse
+	if err := json.Unmarshal([]byte(resp.Text()), &answer); err != nil {
+		return nil, fmt.Errorf("resuming approval: reading the answer: %w", err)
+	}
+
+	if _, err := reviewQueue.Decide(ctx, it.ID, d); err != nil {
+		return nil, fmt.Errorf("resuming approval: %w", err)
+	}
+	return approvedOutput(state.Output, it.ID, answer, edit, edited), nil
+}
+
+// pausedCall returns the interrupted requestApproval call the paused generation ends with
+func pausedCall(messages []*ai.Message) *ai.Part {
+	if len(messages) == 0 {
+		return nil
+	}
+	for _, p := range messages[len(messages)-1].Content {
+		if p.IsInterrupt() && p.ToolRequest.Name == approvalToolName {
+			return p
+		}
+	}
+	return nil
+}
+
+// approvedOutput returns the paused output with the approver's answer applied. For an
+// edit, edited is the moderation of the approver's text, edit.
+func approvedOutput(out *types.SafeWelcomeNoteOutput, reviewID string, answer approvalResponse, edit string, edited *types.ModerationResult) *types.SafeWelcomeNoteOutput {
+	switch answer.Status {
+	case review.StatusEdited:
+		// the moderation report and the other candidates describe the note before the edit
+		out = safeOutput(&types.WelcomeNoteV3Output{
+			Note:     edit,
+			Occasion: out.Occasion,
+			Language: out.Language,
+			Length:   out.Length,
+			Tone:     out.Tone,
+			Metadata: out.Metadata,
+		}, edited)
+	case review.StatusRejected:
+		out = withheld(out)
+	}
+	out.ReviewID = reviewID
+	out.ReviewStatus = answer.Status
+	approval := answer.Approval
+	out.Approval = &approval
+	return out
+}
@@ -0,0 +1,130 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestApprovalPauseAndResume(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  types.ApprovalInput
+		status string
+		note   string // the released note
+	}{
+		{"approve", types.ApprovalInput{Action: "approve", Approver: "priya"}, review.StatusApproved, "Welcome aboard, Sam!"},
+		{"edit", types.ApprovalInput{Action: "edit", Note: "Welcome, Sam!", Approver: "priya", Comment: "shorter"}, review.StatusEdited, "Welcome, Sam!"},
+		{"reject", types.ApprovalInput{Action: "reject", Approver: "priya"}, review.StatusRejected, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			// a file queue, so the paused generation goes through JSON as it does across a restart
+			queue, err := review.NewFileStore(filepath.Join(t.TempDir(), "queue.json"))
+			if err != nil {
+				t.Fatalf("NewFileStore = %v", err)
+			}
+			SetReviewQueue(queue)
+			t.Cleanup(func() { SetReviewQueue(nil) })
+
+			g := newStubGenkit(t, &stubModerator{verdicts: map[string]types.ModerationResult{
+				"Welcome, Sam!": {ModerationNote: "ok", Categories: scores(nil)},
+			}})
+			tool := defineApprovalGate(g)
+			if defineApprovalGate(g) == nil {
+				t.Fatalf("defining the approval gate twice failed")
+			}
+
+			paused, err := awaitApproval(ctx, g, tool, &types.WelcomeNoteInput{Occasion: "first day"}, &types.SafeWelcomeNoteOutput{Note: "Welcome aboard, Sam!", Decision: "allow"})
+			if err != nil {
+				t.Fatalf("awaitApproval = %v", err)
+			}
+			if paused.Note != "" || paused.ReviewStatus != review.StatusPending || paused.Approval.Status != review.StatusPending {
+				t.Fatalf("paused = %+v, want the note withheld pending approval", paused)
+			}
+			it, err := queue.Get(ctx, paused.ReviewID)
+			if err != nil {
+				t.Fatalf("Get = %v", err)
+			}
+			var state approvalState
+			if err := json.Unmarshal(it.State, &state); err != nil || pausedCall(state.Messages) == nil || state.Input == nil {
+				t.Fatalf("state = %s (%v), want the interrupted %s call", it.State, err, approvalToolName)
+			}
+
+			input := tt.input
+			input.ID = paused.ReviewID
+			got, err := resumeApproval(ctx, g, tool, &input)
+			if err != nil {
+				t.Fatalf("resumeApproval = %v", err)
+			}
+			if got.Note != tt.note || got.ReviewStatus != tt.status || got.ReviewID != paused.ReviewID {
+				t.Errorf("resumed = %+v, want note %q and status %q", got, tt.note, tt.status)
+			}
+			if a := got.Approval; a == nil || a.Status != tt.status || a.Approver != "priya" || a.Comment != tt.input.Comment || a.DecidedAt == nil {
+				t.Errorf("approval = %+v, want %s by priya", a, tt.status)
+			}
+
+			if _, err := resumeApproval(ctx, g, tool, &input); err == nil {
+				t.Errorf("resuming twice succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestApprovalResumeFailures(t *testing.T) {
+	const note, slur = "Welcome aboard, Sam!", "Welcome aboard, faggot!"
+	ctx := context.Background()
+	queue := review.NewMemoryStore(nil)
+	SetReviewQueue(queue)
+	t.Cleanup(func() { SetReviewQueue(nil) })
+
+	g := newStubGenkit(t, &stubModerator{})
+	tool := defineApprovalGate(g)
+	paused, err := awaitApproval(ctx, g, tool, &types.WelcomeNoteInput{Occasion: "first day"}, &types.SafeWelcomeNoteOutput{Note: note, Decision: "allow"})
+	if err != nil {
+		t.Fatalf("awaitApproval = %v", err)
+	}
+	pending := func() {
+		t.Helper()
+		if it, err := queue.Get(ctx, paused.ReviewID); err != nil || !it.Pending() {
+			t.Fatalf("item = %+v (%v), want it still pending", it, err)
+		}
+	}
+
+	// a blocked edit is refused
+	_, err = resumeApproval(ctx, g, tool, &types.ApprovalInput{ID: paused.ReviewID, Action: "edit", Note: slur, Approver: "priya"})
+	if !errors.Is(err, review.ErrInvalid) {
+		t.Errorf("blocked edit = %v, want %v", err, review.ErrInvalid)
+	}
+	pending()
+
+	// a resume that fails, here without the approval gate's model, leaves the item to retry
+	other := genkit.Init(ctx)
+	if _, err := resumeApproval(ctx, other, defineTestTool(other), &types.ApprovalInput{ID: paused.ReviewID, Action: "approve", Approver: "priya"}); err == nil {
+		t.Fatalf("resuming without the gate succeeded, want an error")
+	}
+	pending()
+
+	got, err := resumeApproval(ctx, g, tool, &types.ApprovalInput{ID: paused.ReviewID, Action: "approve", Approver: "priya"})
+	if err != nil {
+		t.Fatalf("retried resume = %v", err)
+	}
+	if got.Note != note || got.ReviewStatus != review.StatusApproved {
+		t.Errorf("resumed = %+v, want %q approved", got, note)
+	}
+}
+
+// defineTestTool defines the approval gate's tool in g without its model
+func defineTestTool(g *genkit.Genkit) ai.Tool {
+	return genkit.DefineTool(g, approvalToolName, "test", func(tc *ai.ToolContext, in approvalRequest) (approvalResponse, error) {
+		return approvalResponse{}, nil
+	})
+}
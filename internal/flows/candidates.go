@@ -0,0 +1,214 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Weight of the LLM judge in each blended score; local heuristics get the rest
+const judgeWeight = 0.6
+
+// normalizeCandidates clamps a requested candidate count to 1..types.MaxCandidates
+func normalizeCandidates(n int) int {
+	return min(max(n, 1), types.MaxCandidates)
+}
+
+// candidateJudgement is the judge prompt's structured output
+type candidateJudgement struct {
+	Scores []judgeScore `json:"scores"`
+}
+
+type judgeScore struct {
+	Index    int     `json:"index" jsonschema:"description=candidate index as given in the prompt"`
+	Tone     float64 `json:"tone" jsonschema:"description=0 to 1 match with the requested tone"`
+	Length   float64 `json:"length" jsonschema:"description=0 to 1 match with the requested length"`
+	Language float64 `json:"language" jsonschema:"description=0 to 1 match with the requested language"`
+	Comments string  `json:"comments,omitempty"`
+}
+
+// generateRankedWelcomeNotes calls generate for input.Candidates notes concurrently, scores
+// them and returns the best one with all candidates attached, best first.
+// input must already be normalized and guarded.
+func generateRankedWelcomeNotes(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, generate func() (*types.WelcomeNoteV3Output, error)) (*types.WelcomeNoteV3Output, error) {
+	n := normalizeCandidates(input.Candidates)
+
+	notes := make([]*types.WelcomeNoteV3Output, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			notes[i], errs[i] = generate()
+		})
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("generating candidate %d: %w", i+1, err)
+		}
+	}
+
+	candidates, err := rankCandidates(ctx, g, input, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	best := *notes[candidates[0].Rank-1]
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+	}
+	best.Metadata = candidates[0].Metadata
+	best.Candidates = candidates
+	return &best, nil
+}
+
+// rankCandidates scores each note and returns them sorted by score, best first.
+// Rank is left as the note's 1-based position in notes, for the caller to resolve.
+func rankCandidates(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, notes []*types.WelcomeNoteV3Output) ([]types.WelcomeNoteCandidate, error) {
+	judged, err := judgeCandidates(ctx, g, input, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]types.WelcomeNoteCandidate, len(notes))
+	for i, note := range notes {
+		heuristic := judgeScore{
+			Tone:     toneHeuristic(input.Tone, input.ToneMix, note.Metadata.EffectiveTone),
+			Length:   lengthHeuristic(input.Length, baseLanguage(input), note.Note),
+			Language: languageHeuristic(baseLanguage(input), note.Metadata.EffectiveLanguage, note.Note),
+		}
+		judge := judged[i]
+
+		scores := types.CandidateScores{
+			Tone:      blendScore(judge.Tone, heuristic.Tone),
+			Length:    blendScore(judge.Length, heuristic.Length),
+			Language:  blendScore(judge.Language, heuristic.Language),
+			Judge:     roundScore((judge.Tone + judge.Length + judge.Language) / 3),
+			Heuristic: roundScore((heuristic.Tone + heuristic.Length + heuristic.Language) / 3),
+		}
+
+		metadata := note.Metadata
+		recordPromptVersion(&metadata, promptJudge)
+
+		candidates[i] = types.WelcomeNoteCandidate{
+			Rank:          i + 1,
+			Note:          note.Note,
+			Metadata:      metadata,
+			Score:         roundScore((scores.Tone + scores.Length + scores.Language) / 3),
+			Scores:        scores,
+			JudgeComments: judge.Comments,
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// judgeCandidates asks the model to score all notes in one call.
+// The result is indexed like notes; candidates the judge skipped score 0.
+func judgeCandidates(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, notes []*types.WelcomeNoteV3Output) ([]judgeScore, error) {
+	items := make([]map[string]any, len(notes))
+	for i, note := range notes {
+		items[i] = map[string]any{"index": i + 1, "note": note.Note}
+	}
+
+	rendered, err := renderPrompt(promptJudge, map[string]any{
+		"occasion":   input.Occasion,
+		"language":   input.Language,
+		"length":     input.Length,
+		"tone":       toneLabel(ctx, input),
+		"candidates": items,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("judging candidates: %w", err)
+	}
+
+	result, _, err := genkit.GenerateData[candidateJudgement](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return nil, fmt.Errorf("judging candidates: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, fmt.Errorf("judging candidates: %w", err)
+	}
+
+	scores := make([]judgeScore, len(notes))
+	for _, s := range result.Scores {
+		if s.Index < 1 || s.Index > len(notes) {
+			continue
+		}
+		scores[s.Index-1] = judgeScore{
+			Index:    s.Index,
+			Tone:     clampScore(s.Tone),
+			Length:   clampScore(s.Length),
+			Language: clampScore(s.Language),
+			Comments: s.Comments,
+		}
+	}
+	return scores, nil
+}
+
+// toneHeuristic checks the tone the model reports against the requested one
+func toneHeuristic(requested string, mix map[string]float64, effective string) float64 {
+	effective = strings.ToLower(strings.TrimSpace(effective))
+	if len(mix) > 1 {
+		// credit each blended tone the model reports by its weight
+		score := 0.0
+		for tone, weight := range mix {
+			if strings.Contains(effective, tone) {
+				score += weight
+			}
+		}
+		return min(score, 1)
+	}
+	if effective == strings.ToLower(requested) {
+		return 1
+	}
+	return 0
+}
+
+// lengthHeuristic scores the note's measured sentence count against the requested length range
+func lengthHeuristic(length, language, note string) float64 {
+	m := measureLength(note, language, length)
+	switch {
+	case m.Sentences < m.MinSentences:
+		return float64(m.Sentences) / float64(m.MinSentences)
+	case m.MaxSentences != 0 && m.Sentences > m.MaxSentences:
+		return float64(m.MaxSentences) / float64(m.Sentences)
+	}
+	return 1
+}
+
+// languageHeuristic scores the note's locally detected language against the requested
+// one, falling back to the language the model reports when detection can't tell
+func languageHeuristic(requested, effective, note string) float64 {
+	switch checkLanguage(note, requested).Status {
+	case types.LanguageMatch:
+		return 1
+	case types.LanguageMismatch:
+		return 0
+	}
+	if strings.EqualFold(strings.TrimSpace(effective), strings.TrimSpace(requested)) {
+		return 1
+	}
+	return 0
+}
+
+func blendScore(judge, heuristic float64) float64 {
+	return roundScore(judgeWeight*judge + (1-judgeWeight)*heuristic)
+}
+
+func clampScore(s float64) float64 {
+	return min(max(s, 0), 1)
+}
+
+func roundScore(s float64) float64 {
+	return math.Round(s*100) / 100
+}
@@ -0,0 +1,128 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/guard"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// countingWriter answers the input guard, V2, V3 and judge prompts, counting the calls
+// each prompt gets. V2 gets v2Notes in turn, and its prompts are kept.
+type countingWriter struct {
+	mu        sync.Mutex
+	calls     map[string]int // prompt -> calls
+	v2Notes   []string
+	v2Prompts []string
+}
+
+func newCountingGenkit(t *testing.T, m *countingWriter) *genkit.Genkit {
+	t.Helper()
+	store, err := prompts.Load("../../prompts")
+	if err != nil {
+		t.Fatalf("loading prompts: %v", err)
+	}
+	SetPromptStore(store)
+	t.Cleanup(func() { SetPromptStore(nil) })
+
+	g := genkit.Init(context.Background(), genkit.WithDefaultModel("test/writer"))
+	genkit.DefineModel(g, "test/writer", &ai.ModelOptions{
+		Supports: &ai.ModelSupports{Constrained: ai.ConstrainedSupportAll, Multiturn: true, SystemRole: true},
+	}, m.generate)
+	return g
+}
+
+func (m *countingWriter) generate(_ context.Context, req *ai.ModelRequest, _ ai.ModelStreamCallback) (*ai.ModelResponse, error) {
+	var system, user string
+	for _, msg := range req.Messages {
+		if msg.Role == ai.RoleSystem {
+			system += msg.Text()
+		} else {
+			user += msg.Text()
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var prompt string
+	var reply any
+	switch {
+	case strings.Contains(system, "writes personalized welcome notes using structured inputs."):
+		m.calls[promptWelcomeV2]++
+		m.v2Prompts = append(m.v2Prompts, user)
+		note := m.v2Notes[min(len(m.v2Prompts), len(m.v2Notes))-1]
+		return &ai.ModelResponse{Request: req, Message: ai.NewModelTextMessage(note)}, nil
+	case strings.Contains(system, "You are a security classifier"):
+		prompt = promptInputGuard
+		reply = types.InjectionClassification{Reason: "plain welcome details"}
+	case strings.Contains(system, "You are a strict reviewer"):
+		prompt = promptJudge
+		reply = candidateJudgement{Scores: []judgeScore{{Index: 1, Tone: 1, Length: 1, Language: 1}}}
+	default:
+		prompt = promptWelcomeV3
+		reply = welcomeNoteV3Generation{
+			Note:     "Welcome to the team, Sam. We are glad you are here. See you on Monday.",
+			Language: "english",
+			Length:   "short",
+			Tone:     "warm",
+			Metadata: welcomeNoteV3ModelMetadata{EffectiveLanguage: "english", EffectiveLength: "short", EffectiveTone: "warm"},
+		}
+	}
+
+	m.calls[prompt]++
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &ai.ModelResponse{Request: req, Message: ai.NewModelTextMessage(string(data))}, nil
+}
+
+func TestCandidatesGuardInputOnce(t *testing.T) {
+	m := &countingWriter{calls: map[string]int{}}
+	g := newCountingGenkit(t, m)
+
+	ig, err := guard.New(guard.ModeNeutralize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetInputGuard(ig, true)
+	t.Cleanup(func() { SetInputGuard(nil, false) })
+
+	out, err := generateWelcomeNote3(context.Background(), g, &types.WelcomeNoteInput{
+		Occasion:   "first day on the team",
+		Language:   "english",
+		Length:     "short",
+		Tone:       "warm",
+		Candidates: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := m.calls[promptInputGuard]; got != 1 {
+		t.Errorf("classifier calls = %d, want 1", got)
+	}
+	if got := m.calls[promptWelcomeV3]; got != 3 {
+		t.Errorf("generations = %d, want 3", got)
+	}
+	if len(out.Candidates) != 3 {
+		t.Fatalf("candidates = %d, want 3", len(out.Candidates))
+	}
+	for i, c := range out.Candidates {
+		if c.Metadata.InputGuard == nil || c.Metadata.InputGuard.Classifier == nil {
+			t.Errorf("candidate %d has no input guard verdict", i+1)
+		}
+		if c.Metadata.MeasuredLength == nil || !c.Metadata.MeasuredLength.InRange {
+			t.Errorf("candidate %d length not measured in range: %+v", i+1, c.Metadata.MeasuredLength)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package flows
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Diff segment operations
+const (
+	diffEqual  = "equal"
+	diffInsert = "insert"
+	diffDelete = "delete"
+)
+
+// words and the whitespace between them are separate tokens
+var diffTokenPattern = regexp.MustCompile(`\S+|\s+`)
+
+// diffWords returns a word-level diff turning before into after, with adjacent
+// segments of the same kind merged. Notes are short, so a plain LCS table is fine.
+func diffWords(before, after string) []types.DiffSegment {
+	a := diffTokenPattern.FindAllString(before, -1)
+	b := diffTokenPattern.FindAllString(after, -1)
+
+	// lcs[i][j] = length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var segments []types.DiffSegment
+	add := func(op, text string) {
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, types.DiffSegment{Op: op, Text: text})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(diffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(diffDelete, a[i])
+			i++
+		default:
+			add(diffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(diffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(diffInsert, b[j])
+	}
+	return segments
+}
+
+// changedSpans lists the parts of before that after replaced or removed, with character
+// offsets into before. Text after only inserted shows up as an empty span where it went.
+func changedSpans(before, after string) []types.ChangedSpan {
+	var spans []types.ChangedSpan
+	var span *types.ChangedSpan
+	pos := 0
+	for _, seg := range diffWords(before, after) {
+		n := utf8.RuneCountInString(seg.Text)
+		if seg.Op == diffEqual {
+			if span != nil {
+				spans = append(spans, *span)
+				span = nil
+			}
+			pos += n
+			continue
+		}
+		if span == nil {
+			span = &types.ChangedSpan{Start: pos, End: pos}
+		}
+		if seg.Op == diffDelete {
+			span.Original += seg.Text
+			span.End += n
+			pos += n
+		} else {
+			span.Replacement += seg.Text
+		}
+	}
+	if span != nil {
+		spans = append(spans, *span)
+	}
+	return spans
+}
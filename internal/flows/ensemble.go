@@ -0,0 +1,119 @@
+package flows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Optional moderators that vote on every note; see SetModerationEnsemble
+var moderationEnsemble *moderation.Ensemble
+
+// SetModerationEnsemble makes e's moderators vote on every moderated note in place of
+// the single LLM moderator on the routed model. When e includes the rule engine, a
+// blocking rule is one vote rather than the final word. nil keeps one moderator.
+// It must be called at startup, before any flow runs.
+func SetModerationEnsemble(e *moderation.Ensemble) {
+	moderationEnsemble = e
+}
+
+// runModerationEnsemble has every ensemble moderator vote on a note. The model moderators
+// run concurrently and only see the rule-sanitized note, and its English pivot if it has one. The result is scored by the side
+// that won the vote, and an allowed note is sanitized by the heaviest model that allowed it.
+func runModerationEnsemble(ctx context.Context, g *genkit.Genkit, rules moderation.Report, pivot *types.ModerationPivot) (*types.ModerationResult, error) {
+	e := moderationEnsemble
+	votes := make([]types.ModerationVote, len(e.Moderators))
+	results := make([]*types.ModerationResult, len(e.Moderators))
+	var wg sync.WaitGroup
+	for i, m := range e.Moderators {
+		votes[i] = types.ModerationVote{Moderator: m.Name, Weight: m.Weight}
+		if m.Name == moderation.ModeratorRules {
+			categories := moderationCategories(nil, rules.Findings)
+			votes[i].Blocked = rules.Blocked || policyBlocks(categories)
+			votes[i].ModerationNote = moderation.Summary(rules.Findings)
+			votes[i].Categories = categories
+			continue
+		}
+		wg.Go(func() {
+			start := time.Now()
+			result, err := moderateWithPivot(withPinnedModel(ctx, m.Name), g, rules.Sanitized, pivot, nil)
+			votes[i].DurationMs = time.Since(start).Milliseconds()
+			if err != nil {
+				votes[i].Error = err.Error()
+				slog.WarnContext(ctx, "moderator abstained",
+					slog.String("moderator", m.Name),
+					slog.String("error", err.Error()),
+				)
+				return
+			}
+			result.Categories = moderationCategories(result.Categories, nil)
+			results[i] = result
+			votes[i].Blocked = result.Blocked
+			if moderationPolicy != nil {
+				votes[i].Blocked = policyBlocks(result.Categories)
+			}
+			votes[i].ModerationNote = result.ModerationNote
+			votes[i].Categories = result.Categories
+		})
+	}
+	wg.Wait()
+
+	blocked, reason, ok := e.Tally(votes)
+	if !ok {
+		var errs []error
+		for _, v := range votes {
+			if v.Error != "" {
+				errs = append(errs, fmt.Errorf("%s: %s", v.Moderator, v.Error))
+			}
+		}
+		return nil, fmt.Errorf("moderating welcome note: fewer than %d model moderators voted: %w", max(e.Quorum, 1), errors.Join(errs...))
+	}
+
+	var scored []types.CategoryScore
+	for _, v := range votes {
+		if v.Error == "" && v.Blocked == blocked {
+			scored = append(scored, v.Categories...)
+		}
+	}
+	result := &types.ModerationResult{
+		Blocked:        blocked,
+		ModerationNote: reason,
+		DecisionReason: reason,
+		Categories:     moderationCategories(scored, nil),
+		Votes:          votes,
+		Pivot:          pivot,
+	}
+	if blocked {
+		return result, nil
+	}
+
+	// the sanitized note of the heaviest model that allowed the note and changed it
+	chosen := -1
+	for i, r := range results {
+		if r == nil || votes[i].Blocked || r.SanitizedNote == "" || r.SanitizedNote == rules.Sanitized {
+			continue
+		}
+		if chosen < 0 || votes[i].Weight > votes[chosen].Weight {
+			chosen = i
+		}
+	}
+	if chosen >= 0 {
+		result.SanitizedNote = results[chosen].SanitizedNote
+		result.Pivot = results[chosen].Pivot
+		result.ModerationNote += "; " + votes[chosen].Moderator + ": " + votes[chosen].ModerationNote
+	}
+	return result, nil
+}
+
+// policyBlocks reports whether the moderation policy, if there is one, blocks a note
+// scored with categories
+func policyBlocks(categories []types.CategoryScore) bool {
+	return moderationPolicy != nil && moderationPolicy.Decide(categories).Action == moderation.DecisionBlock
+}
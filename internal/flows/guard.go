@@ -0,0 +1,132 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/guard"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+var inputGuard = mustInputGuard(guard.New(guard.ModeNeutralize))
+
+func mustInputGuard(g *guard.Guard, err error) *guard.Guard {
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Whether the LLM classifier runs after the heuristics; see SetInputGuard
+var inputGuardClassifier bool
+
+// SetInputGuard sets the guard that screens user text for prompt injection before any
+// prompt is rendered; nil turns screening off. With classifier, an LLM classifier also
+// reads every request the heuristics don't reject. It must be called at startup,
+// before any flow runs.
+func SetInputGuard(g *guard.Guard, classifier bool) {
+	inputGuard = g
+	inputGuardClassifier = classifier
+}
+
+// inputField is user text that reaches a prompt. Neutralizing rewrites it in place.
+type inputField struct {
+	name  string
+	value *string
+}
+
+// welcomeNoteFields lists the free-text fields of input
+func welcomeNoteFields(input *types.WelcomeNoteInput) []inputField {
+	return []inputField{
+		{"occasion", &input.Occasion},
+		{fieldRecipients, &input.Recipients},
+		{fieldSender, &input.Sender},
+		{fieldRelationship, &input.Relationship},
+		{fieldOrganization, &input.Organization},
+		{fieldSignature, &input.Signature},
+	}
+}
+
+type inputGuardKey struct{}
+
+// guardInput screens fields with the input guard. A rejected request returns a
+// *guard.RejectedError; a neutralized one has its fields rewritten. The verdict is logged
+// and carried in the returned context, so flows called from a screened flow, such as the
+// V3 generator inside Safe, reuse it instead of screening again. The verdict is nil when
+// the guard is off.
+func guardInput(ctx context.Context, g *genkit.Genkit, fields ...inputField) (context.Context, *types.InputGuardVerdict, error) {
+	if verdict, ok := ctx.Value(inputGuardKey{}).(*types.InputGuardVerdict); ok {
+		return ctx, verdict, nil
+	}
+	if inputGuard == nil {
+		return ctx, nil, nil
+	}
+
+	checked := make([]guard.Field, len(fields))
+	for i, f := range fields {
+		checked[i] = guard.Field{Name: f.name, Text: *f.value}
+	}
+	verdict, neutralized := inputGuard.Check(checked)
+
+	if inputGuardClassifier && verdict.Action != types.GuardReject {
+		classification, err := classifyInput(ctx, g, checked)
+		if err != nil {
+			return ctx, nil, err
+		}
+		inputGuard.Classify(&verdict, classification)
+	}
+
+	level := slog.LevelInfo
+	if verdict.Action != types.GuardAllow {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "input guard",
+		slog.String("action", verdict.Action),
+		slog.Float64("score", verdict.Score),
+		slog.Any("fields", guard.Fields(verdict)),
+		slog.Any("signals", guard.Signals(verdict)),
+		slog.Bool("classifier", verdict.Classifier != nil),
+	)
+
+	switch verdict.Action {
+	case types.GuardReject:
+		return ctx, &verdict, &guard.RejectedError{Verdict: verdict}
+	case types.GuardNeutralize:
+		for i, f := range fields {
+			*f.value = neutralized[i].Text
+		}
+	}
+	return context.WithValue(ctx, inputGuardKey{}, &verdict), &verdict, nil
+}
+
+// classifyInput asks the model whether the non-empty fields attempt a prompt injection
+func classifyInput(ctx context.Context, g *genkit.Genkit, fields []guard.Field) (*types.InjectionClassification, error) {
+	var items []map[string]any
+	for _, f := range fields {
+		if f.Text != "" {
+			items = append(items, map[string]any{"name": f.Name, "text": f.Text})
+		}
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	rendered, err := renderPrompt(promptInputGuard, map[string]any{
+		"fields": items,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("classifying input: %w", err)
+	}
+
+	result, _, err := genkit.GenerateData[types.InjectionClassification](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return nil, fmt.Errorf("classifying input: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, fmt.Errorf("classifying input: %w", err)
+	}
+	result.Confidence = roundScore(clampScore(result.Confidence))
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package flows
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/langdetect"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Minimum detection confidence for a verdict; below it the check is unverified
+const minLanguageConfidence = 0.5
+
+// How many times a note detected in the wrong language is regenerated with corrective feedback
+var languageRetries = 1
+
+// SetLanguageRetries sets how many times a note detected in a language other than the
+// requested one is regenerated. 0 disables regeneration; notes are still checked.
+func SetLanguageRetries(n int) {
+	languageRetries = max(n, 0)
+}
+
+// checkLanguage detects the note's language and compares it with the requested one
+func checkLanguage(note, language string) *types.LanguageCheck {
+	requested := langdetect.Normalize(language)
+	detected := langdetect.Detect(note)
+
+	c := &types.LanguageCheck{
+		Requested:  requested,
+		Detected:   detected.Language,
+		Confidence: detected.Confidence,
+		Status:     types.LanguageUnverified,
+	}
+	if !langdetect.Supported(requested) || detected.Language == "" || detected.Confidence < minLanguageConfidence {
+		return c
+	}
+	c.Status = types.LanguageMismatch
+	if detected.Language == requested {
+		c.Status = types.LanguageMatch
+	}
+	return c
+}
+
+// languageFeedback tells the model its previous note was in the wrong language
+func languageFeedback(c *types.LanguageCheck) string {
+	return fmt.Sprintf("Your previous note was written in %s, but it must be written in %s. Write the whole note again in %s only.",
+		titleCase(c.Detected), titleCase(c.Requested), titleCase(c.Requested))
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
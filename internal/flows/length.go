@@ -0,0 +1,110 @@
+package flows
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/textstats"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Sentence ranges for each length, matching the welcome prompts
+var lengthSentenceRange = map[string][2]int{
+	"short":  {2, 5},
+	"medium": {5, 10},
+	"long":   {10, math.MaxInt},
+}
+
+// How many times an out-of-range note is regenerated with corrective feedback
+var lengthRetries = 2
+
+// SetLengthRetries sets how many times a note whose measured length is outside
+// the requested range is regenerated. 0 disables regeneration; notes are still measured.
+func SetLengthRetries(n int) {
+	lengthRetries = max(n, 0)
+}
+
+// measureLength counts the note's sentences and words and checks them against the length category
+func measureLength(note, language, length string) *types.LengthMeasurement {
+	counts := textstats.Count(note, language)
+	r, ok := lengthSentenceRange[length]
+	if !ok {
+		r = lengthSentenceRange["short"]
+	}
+
+	m := &types.LengthMeasurement{
+		Sentences:    counts.Sentences,
+		Words:        counts.Words,
+		MinSentences: r[0],
+		InRange:      counts.Sentences >= r[0] && counts.Sentences <= r[1],
+	}
+	if r[1] != math.MaxInt {
+		m.MaxSentences = r[1]
+	}
+	return m
+}
+
+// lengthCategory returns the length category a sentence count falls into,
+// preferring the requested one where the ranges overlap
+func lengthCategory(sentences int, requested string) string {
+	if r, ok := lengthSentenceRange[requested]; ok && sentences >= r[0] && sentences <= r[1] {
+		return requested
+	}
+	switch {
+	case sentences <= 5:
+		return "short"
+	case sentences <= 10:
+		return "medium"
+	}
+	return "long"
+}
+
+// lengthFeedback tells the model how its previous note missed the requested length
+func lengthFeedback(m *types.LengthMeasurement, length string) string {
+	want := fmt.Sprintf("%d–%d sentences", m.MinSentences, m.MaxSentences)
+	if m.MaxSentences == 0 {
+		want = fmt.Sprintf("at least %d sentences", m.MinSentences)
+	}
+	direction := "longer"
+	if m.Sentences > m.MaxSentences && m.MaxSentences != 0 {
+		direction = "shorter"
+	}
+	return fmt.Sprintf("Your previous note had %d sentences, but a %s note must have %s. Write the note again, %s, with %s. Count the sentences before answering.",
+		m.Sentences, length, want, direction, want)
+}
+
+// generateChecked calls generate until the note it returns has the requested length and
+// language, or the retries run out. Each retry gets feedback on what the previous note
+// missed. It returns the last note's measurements.
+func generateChecked(language, length string, generate func(feedback string, attempt int) (string, error)) (*types.LengthMeasurement, *types.LanguageCheck, error) {
+	lengthLeft, languageLeft := lengthRetries, languageRetries
+	feedback := ""
+	for attempt := 1; ; attempt++ {
+		note, err := generate(feedback, attempt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// the model's own effective length and language aren't checked, so measure
+		// and detect locally
+		measured := measureLength(note, language, length)
+		measured.Attempts = attempt
+		checked := checkLanguage(note, language)
+		checked.Attempts = attempt
+
+		var corrections []string
+		if checked.Status == types.LanguageMismatch && languageLeft > 0 {
+			languageLeft--
+			corrections = append(corrections, languageFeedback(checked))
+		}
+		if !measured.InRange && lengthLeft > 0 {
+			lengthLeft--
+			corrections = append(corrections, lengthFeedback(measured, length))
+		}
+		if len(corrections) == 0 {
+			return measured, checked, nil
+		}
+		feedback = strings.Join(corrections, " ")
+	}
+}
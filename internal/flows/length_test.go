@@ -0,0 +1,55 @@
+package flows
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateChecked(t *testing.T) {
+	const (
+		short   = "Welcome to the team, Sam. We are glad you are here. See you on Monday."
+		tooLong = "Welcome to the team, Sam. We are glad you are here. Your desk is ready. " +
+			"Lunch is on us. The team meets at ten. Ask anyone for help. See you on Monday."
+		spanish = "Bienvenido al equipo, Sam. Estamos muy contentos de que estés aquí. Nos vemos el lunes."
+	)
+	tests := []struct {
+		name     string
+		notes    []string // returned by each attempt in turn
+		attempts int
+		feedback []string // the retries' feedback contains it
+		inRange  bool
+	}{
+		{name: "first note fits", notes: []string{short}, attempts: 1, inRange: true},
+		{name: "too long, then fits", notes: []string{tooLong, short}, attempts: 2, feedback: []string{"shorter"}, inRange: true},
+		{name: "wrong language, then fits", notes: []string{spanish, short}, attempts: 2, feedback: []string{"english"}, inRange: true},
+		{name: "retries run out", notes: []string{tooLong, tooLong, tooLong, tooLong}, attempts: 3, feedback: []string{"shorter", "shorter"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var feedback []string
+			measured, checked, err := generateChecked("english", "short", func(fb string, attempt int) (string, error) {
+				if attempt > 1 {
+					feedback = append(feedback, fb)
+				}
+				return tt.notes[attempt-1], nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if measured.Attempts != tt.attempts || checked.Attempts != tt.attempts {
+				t.Errorf("attempts = %d/%d, want %d", measured.Attempts, checked.Attempts, tt.attempts)
+			}
+			if measured.InRange != tt.inRange {
+				t.Errorf("in range = %v, want %v", measured.InRange, tt.inRange)
+			}
+			if len(feedback) != len(tt.feedback) {
+				t.Fatalf("feedback = %q, want %d retries", feedback, len(tt.feedback))
+			}
+			for i, want := range tt.feedback {
+				if !strings.Contains(strings.ToLower(feedback[i]), want) {
+					t.Errorf("feedback %d = %q, want it to mention %q", i+1, feedback[i], want)
+				}
+			}
+		})
+	}
+}
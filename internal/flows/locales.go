@@ -0,0 +1,83 @@
+package flows
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/locales"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+var localeRegistry = mustLocales(locales.New(locales.DefaultTags, ""))
+
+func mustLocales(r *locales.Registry, err error) *locales.Registry {
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// SetLocales sets the locales flows accept. It must be called at startup, before any flow runs.
+func SetLocales(r *locales.Registry) {
+	localeRegistry = r
+}
+
+// Locales returns the locales flows accept
+func Locales() *locales.Registry {
+	return localeRegistry
+}
+
+// ValidateLocale reports whether a request's locale or language resolves to a supported locale
+func ValidateLocale(input *types.WelcomeNoteInput) error {
+	_, err := localeRegistry.Resolve(localeInput(input))
+	return err
+}
+
+// normalizeLocale resolves input.Locale, or input.Language when no locale is given,
+// and rewrites both: Locale to the BCP-47 tag, Language to its English name,
+// which includes the regional variant the prompts should write in
+func normalizeLocale(input *types.WelcomeNoteInput) (locales.Locale, error) {
+	l, err := localeRegistry.Resolve(localeInput(input))
+	if err != nil {
+		return locales.Locale{}, fmt.Errorf("normalizing language: %w", err)
+	}
+	input.Locale = l.Tag
+	input.Language = l.Name
+	return l, nil
+}
+
+// normalizeLocaleOrDefault is normalizeLocale for model-inferred languages,
+// where an unsupported language falls back to the default locale
+func normalizeLocaleOrDefault(input *types.WelcomeNoteInput) locales.Locale {
+	l, err := normalizeLocale(input)
+	if err != nil {
+		l = localeRegistry.Default()
+		input.Locale = l.Tag
+		input.Language = l.Name
+	}
+	return l
+}
+
+func localeInput(input *types.WelcomeNoteInput) string {
+	if strings.TrimSpace(input.Locale) != "" {
+		return input.Locale
+	}
+	return input.Language
+}
+
+// baseLanguage returns the lowercase English name of the input's base language, e.g. "portuguese"
+// for pt-BR, as used for local sentence counting and language detection
+func baseLanguage(input *types.WelcomeNoteInput) string {
+	if l, err := localeRegistry.Resolve(localeInput(input)); err == nil {
+		return l.Language
+	}
+	return strings.ToLower(strings.TrimSpace(input.Language))
+}
+
+// withLocale adds the input's locale tag to prompt variables
+func withLocale(vars map[string]any, input *types.WelcomeNoteInput) map[string]any {
+	if input.Locale != "" {
+		vars["locale"] = input.Locale
+	}
+	return vars
+}
@@ -0,0 +1,121 @@
+package flows
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/routing"
+)
+
+// Model routes of each flow and step; see SetModelRoutes
+var modelRoutes *routing.Routes
+
+// SetModelRoutes sets the model each flow and step runs on. Steps routes don't cover run
+// on its Default, and on Genkit's default model when that is empty too.
+// It must be called at startup, before any flow runs.
+func SetModelRoutes(r *routing.Routes) {
+	modelRoutes = r
+}
+
+// Optional check applied to the model a request picks; see SetModelPolicy
+var modelPolicy func(model string) error
+
+// SetModelPolicy restricts the models requests may pick. check returns an error for
+// models the deployment doesn't allow; nil lets requests pick any model.
+// It must be called at startup, before any flow runs.
+func SetModelPolicy(check func(model string) error) {
+	modelPolicy = check
+}
+
+// Steps that write the note, the only ones a request's model replaces. Moderation, the
+// input guard, judging and translation stay on their routes so a request can't weaken them.
+var noteSteps = []string{promptWelcomeV1, promptWelcomeV2, promptWelcomeV3, promptRefine}
+
+// ValidateModel reports whether a request may pick model; "" keeps the routed models
+func ValidateModel(model string) error {
+	if model == "" || modelPolicy == nil {
+		return nil
+	}
+	return modelPolicy(model)
+}
+
+type requestModelKey struct{}
+type pinnedModelKey struct{}
+type modelReportKey struct{}
+
+// WithModel returns a context whose note writing steps run on model, the model a request
+// picked, or an error if the deployment doesn't allow it. "" keeps the routed models.
+func WithModel(ctx context.Context, model string) (context.Context, error) {
+	if model == "" {
+		return ctx, nil
+	}
+	if err := ValidateModel(model); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, requestModelKey{}, model), nil
+}
+
+// withPinnedModel returns a context whose every step runs on model, whatever the routes
+// or the request say; an ensemble moderator's calls are pinned to its model
+func withPinnedModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, pinnedModelKey{}, model)
+}
+
+// modelFor returns the model a step of the running flow calls, "" for Genkit's default
+func modelFor(ctx context.Context, step string) string {
+	if m, _ := ctx.Value(pinnedModelKey{}).(string); m != "" {
+		return m
+	}
+	if m, _ := ctx.Value(requestModelKey{}).(string); m != "" && slices.Contains(noteSteps, step) {
+		return m
+	}
+	return modelRoutes.Model(core.FlowNameFromContext(ctx), step)
+}
+
+// modelReport collects the models a request's steps called
+type modelReport struct {
+	mu   sync.Mutex
+	note string              // model of the first note writing step
+	used map[string][]string // step -> models, sorted
+}
+
+// WithModelReport returns a context that records the model of every step a flow run with
+// it calls; read them back with ModelsUsed
+func WithModelReport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, modelReportKey{}, &modelReport{used: map[string][]string{}})
+}
+
+// recordModel adds the model a step called to the request's report, if it has one
+func recordModel(ctx context.Context, step, model string) {
+	r, _ := ctx.Value(modelReportKey{}).(*modelReport)
+	if r == nil || model == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.note == "" && slices.Contains(noteSteps, step) {
+		r.note = model
+	}
+	if i, found := slices.BinarySearch(r.used[step], model); !found {
+		r.used[step] = slices.Insert(r.used[step], i, model)
+	}
+}
+
+// ModelsUsed returns the model that wrote the note and, by step, the models each step
+// called, comma-separated when several did, from a context made by WithModelReport
+func ModelsUsed(ctx context.Context) (note string, steps map[string]string) {
+	r, _ := ctx.Value(modelReportKey{}).(*modelReport)
+	if r == nil {
+		return "", nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	steps = make(map[string]string, len(r.used))
+	for step, models := range r.used {
+		steps[step] = strings.Join(models, ", ")
+	}
+	return r.note, steps
+}
@@ -0,0 +1,68 @@
+package flows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/routing"
+)
+
+func TestModelFor(t *testing.T) {
+	SetModelRoutes(&routing.Routes{
+		Default: "default-model",
+		Steps:   map[string]string{promptModeration: "moderation-model"},
+		Flows: map[string]routing.FlowRoutes{
+			"welcomeNoteFlowSafe": {Steps: map[string]string{promptWelcomeV3: "safe-model", promptModeration: "safe-moderation-model"}},
+		},
+	})
+	t.Cleanup(func() { SetModelRoutes(nil) })
+
+	ctx := context.Background()
+	g := genkit.Init(ctx)
+	// modelFor reads the flow's routes from the running flow's name
+	type stepIn struct {
+		Step, Model, Pinned string
+	}
+	flow := genkit.DefineFlow(g, "welcomeNoteFlowSafe", func(ctx context.Context, in stepIn) (string, error) {
+		if in.Model != "" {
+			ctx = context.WithValue(ctx, requestModelKey{}, in.Model)
+		}
+		if in.Pinned != "" {
+			ctx = withPinnedModel(ctx, in.Pinned)
+		}
+		return modelFor(ctx, in.Step), nil
+	})
+
+	tests := []struct {
+		name  string
+		input stepIn
+		want  string
+	}{
+		{"flow step", stepIn{Step: promptWelcomeV3}, "safe-model"},
+		{"flow moderation step", stepIn{Step: promptModeration}, "safe-moderation-model"},
+		{"default", stepIn{Step: promptJudge}, "default-model"},
+		{"request model writes the note", stepIn{Step: promptWelcomeV3, Model: "picked-model"}, "picked-model"},
+		{"request model writes refinements", stepIn{Step: promptRefine, Model: "picked-model"}, "picked-model"},
+		{"request model doesn't moderate", stepIn{Step: promptModeration, Model: "picked-model"}, "safe-moderation-model"},
+		{"request model doesn't judge", stepIn{Step: promptJudge, Model: "picked-model"}, "default-model"},
+		{"pinned model wins over the request", stepIn{Step: promptWelcomeV3, Model: "picked-model", Pinned: "pinned-model"}, "pinned-model"},
+		{"pinned model moderates", stepIn{Step: promptModeration, Pinned: "pinned-model"}, "pinned-model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := flow.Run(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("running the flow = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("modelFor(%+v) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	// outside a flow only the global routes apply
+	if got := modelFor(ctx, promptModeration); got != "moderation-model" {
+		t.Errorf("modelFor outside a flow = %q, want moderation-model", got)
+	}
+}
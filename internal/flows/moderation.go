@@ -0,0 +1,205 @@
+package flows
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/pseudonym"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+var moderationRules = mustModerationRules(moderation.New(moderation.Defaults()))
+
+func mustModerationRules(e *moderation.Engine, err error) *moderation.Engine {
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// SetModerationRules sets the rule engine that runs before the LLM moderator; nil
+// leaves moderation to the LLM alone. It must be called at startup, before any flow runs.
+func SetModerationRules(e *moderation.Engine) {
+	moderationRules = e
+}
+
+// Optional thresholds that decide allow, sanitize, review or block; see SetModerationPolicy
+var moderationPolicy *moderation.Policy
+
+// SetModerationPolicy makes p decide what happens to moderated notes from their category
+// severities, instead of the LLM moderator's own blocked flag and sanitized note. nil
+// leaves the decision to the moderator. It must be called at startup, before any flow runs.
+func SetModerationPolicy(p *moderation.Policy) {
+	moderationPolicy = p
+}
+
+// Optional append-only log of moderation decisions; see SetAuditStore
+var auditStore audit.Store
+
+// SetAuditStore makes every moderated note that isn't allowed as written, whether
+// sanitized, held for review or blocked, leave an event in s; nil records nothing.
+// It must be called at startup, before any flow runs.
+func SetAuditStore(s audit.Store) {
+	auditStore = s
+}
+
+// AuditStore returns the moderation audit log, nil when moderation isn't audited
+func AuditStore() audit.Store {
+	return auditStore
+}
+
+// Whether audit events keep notes as written instead of pseudonymized; see SetAuditRawNotes
+var auditRawNotes bool
+
+// SetAuditRawNotes makes audit events keep the notes as written, personal data included.
+// By default names, emails, phone numbers, card numbers and national IDs are replaced by
+// placeholders first. It must be called at startup, before any flow runs.
+func SetAuditRawNotes(enabled bool) {
+	auditRawNotes = enabled
+}
+
+// ModerateNote moderates a note the way the Safe flow's moderate_and_sanitize step does,
+// outside of any flow. It is meant for evaluating moderation against fixture notes.
+func ModerateNote(ctx context.Context, g *genkit.Genkit, note string, input *types.WelcomeNoteInput) (*types.ModerationResult, error) {
+	return moderateWelcomeNote(ctx, g, note, input)
+}
+
+// recordModeration appends the moderation of note to the audit log, pseudonymized unless
+// raw notes are audited. A failed write is logged rather than failing the request, which
+// has already been moderated.
+func recordModeration(ctx context.Context, note string, input *types.WelcomeNoteInput, result *types.ModerationResult) {
+	if auditStore == nil || result == nil || result.Decision == "" || result.Decision == moderation.DecisionAllow {
+		return
+	}
+	e := audit.NewEvent()
+	e.RequestID = audit.RequestID(ctx)
+	e.Flow = core.FlowNameFromContext(ctx)
+	if input != nil {
+		e.InputHash = audit.HashInput(input)
+	}
+	e.OriginalNote, e.SanitizedNote = note, result.SanitizedNote
+	if !auditRawNotes {
+		v := auditVault(input)
+		e.OriginalNote, e.SanitizedNote = v.Mask(e.OriginalNote), v.Mask(e.SanitizedNote)
+	}
+	e.Categories = result.Categories
+	e.Decision = result.Decision
+	e.Reason = result.DecisionReason
+
+	if err := auditStore.Append(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "moderation audit failed",
+			slog.String("event", e.ID),
+			slog.String("decision", e.Decision),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// auditVault returns a vault knowing the people named in input, for masking the notes of
+// one audit event. It is separate from the request's vault, so an event's placeholders
+// don't depend on what pseudonymization was on or which prompts ran first.
+func auditVault(input *types.WelcomeNoteInput) *pseudonym.Vault {
+	v := pseudonym.NewVault()
+	if input != nil {
+		v.AddNameList(input.Recipients)
+		v.AddNameList(input.Sender)
+		v.AddNameList(input.Signature)
+		v.AddNames(input.Occasion)
+	}
+	return v
+}
+
+// checkModerationRules runs the moderation rules over a note written for input.
+// PII the requester gave as a recipient, sender or signature is exempt, so a signature's
+// email address isn't masked; nothing exempts a word list match.
+func checkModerationRules(note string, input *types.WelcomeNoteInput) moderation.Report {
+	if moderationRules == nil {
+		return moderation.Report{Sanitized: note}
+	}
+	if input == nil {
+		return moderationRules.Check(note, "")
+	}
+	var allow []string
+	for _, field := range []string{input.Recipients, input.Sender, input.Signature} {
+		allow = append(allow, field)
+		for _, f := range moderationRules.Check(field, "").Findings {
+			if f.Kind == moderation.KindPII {
+				allow = append(allow, f.Match)
+			}
+		}
+	}
+	return moderationRules.Check(note, input.Locale, allow...)
+}
+
+// moderationCategories reports every moderation category once, in order, with the highest
+// severity the LLM moderator or a rule finding gave it
+func moderationCategories(scored []types.CategoryScore, findings []types.RuleFinding) []types.CategoryScore {
+	severity := map[string]float64{}
+	for _, c := range scored {
+		// the model may write "self-harm" or "Hate"
+		category := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(c.Category)))
+		severity[category] = max(severity[category], clampScore(c.Severity))
+	}
+	for _, f := range findings {
+		severity[f.Category] = max(severity[f.Category], clampScore(f.Severity))
+	}
+
+	categories := make([]types.CategoryScore, len(types.ModerationCategories))
+	for i, category := range types.ModerationCategories {
+		categories[i] = types.CategoryScore{Category: category, Severity: roundScore(severity[category])}
+	}
+	return categories
+}
+
+// applyModerationPolicy replaces the LLM moderator's decision with the moderation policy's.
+// Rule masking (PII in particular) is kept whatever the policy decides. When the policy
+// wants a note sanitized that the moderator left alone, the moderator is asked again with
+// the categories to rewrite; if that changes nothing either, the note goes to review.
+func applyModerationPolicy(ctx context.Context, g *genkit.Genkit, rules moderation.Report, result *types.ModerationResult) error {
+	decision := moderationPolicy.Decide(result.Categories)
+	result.Decision, result.DecisionReason = decision.Action, decision.Reason()
+
+	switch decision.Action {
+	case moderation.DecisionAllow:
+		if result.Blocked || (result.SanitizedNote != "" && result.SanitizedNote != rules.Sanitized) {
+			result.ModerationNote = "allowed by the " + moderationPolicy.Name + " policy; the moderator flagged: " + result.ModerationNote
+		}
+		result.Blocked = false
+		result.SanitizedNote = rules.Sanitized
+
+	case moderation.DecisionSanitize:
+		result.Blocked = false
+		if result.SanitizedNote != "" && result.SanitizedNote != rules.Sanitized {
+			break
+		}
+		retry, err := moderateWithPivot(ctx, g, rules.Sanitized, result.Pivot, moderationPolicy.SanitizeCategories(result.Categories))
+		if err != nil {
+			return err
+		}
+		if retry.SanitizedNote != "" && retry.SanitizedNote != rules.Sanitized {
+			result.SanitizedNote = retry.SanitizedNote
+			result.Pivot = retry.Pivot
+			break
+		}
+		result.SanitizedNote = rules.Sanitized
+		result.Decision = moderation.DecisionReview
+		result.DecisionReason += "; the moderator made no changes"
+
+	case moderation.DecisionReview:
+		// keep whatever the moderator sanitized for the reviewer
+		result.Blocked = false
+		if result.SanitizedNote == "" {
+			result.SanitizedNote = rules.Sanitized
+		}
+
+	case moderation.DecisionBlock:
+		result.Blocked = true
+		result.SanitizedNote = ""
+	}
+	return nil
+}
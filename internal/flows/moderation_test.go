@@ -0,0 +1,115 @@
+package flows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// memoryAudit is an audit.Store that keeps events in a slice
+type memoryAudit struct{ events []audit.Event }
+
+func (m *memoryAudit) Append(_ context.Context, e audit.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func (m *memoryAudit) Query(context.Context, audit.Query) ([]audit.Event, error) {
+	return m.events, nil
+}
+
+func (m *memoryAudit) Close() error { return nil }
+
+func TestRecordModeration(t *testing.T) {
+	input := &types.WelcomeNoteInput{Occasion: "welcome Jane Doe", Signature: "Raj"}
+	note := "Welcome Jane Doe, damn glad you're here! Mail jane@example.com. Raj"
+	result := &types.ModerationResult{
+		SanitizedNote: "Welcome Jane Doe, glad you're here! Mail jane@example.com. Raj",
+		Decision:      moderation.DecisionSanitize,
+	}
+	tests := []struct {
+		name          string
+		raw           bool
+		original      string
+		sanitizedNote string
+	}{
+		{
+			name:          "pseudonymized",
+			original:      "Welcome [PERSON_2], damn glad you're here! Mail [EMAIL_1]. [PERSON_1]",
+			sanitizedNote: "Welcome [PERSON_2], glad you're here! Mail [EMAIL_1]. [PERSON_1]",
+		},
+		{
+			name:          "raw",
+			raw:           true,
+			original:      note,
+			sanitizedNote: result.SanitizedNote,
+		},
+	}
+	defer SetAuditStore(nil)
+	defer SetAuditRawNotes(false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryAudit{}
+			SetAuditStore(store)
+			SetAuditRawNotes(tt.raw)
+
+			recordModeration(context.Background(), note, input, result)
+			if len(store.events) != 1 {
+				t.Fatalf("recorded %d events, want 1", len(store.events))
+			}
+			e := store.events[0]
+			if e.OriginalNote != tt.original {
+				t.Errorf("original note = %q, want %q", e.OriginalNote, tt.original)
+			}
+			if e.SanitizedNote != tt.sanitizedNote {
+				t.Errorf("sanitized note = %q, want %q", e.SanitizedNote, tt.sanitizedNote)
+			}
+		})
+	}
+}
+
+func TestRecordModerationSkipsAllowed(t *testing.T) {
+	store := &memoryAudit{}
+	SetAuditStore(store)
+	defer SetAuditStore(nil)
+
+	recordModeration(context.Background(), "Welcome!", nil, &types.ModerationResult{Decision: moderation.DecisionAllow})
+	if len(store.events) != 0 {
+		t.Errorf("recorded %+v, want nothing for an allowed note", store.events)
+	}
+}
+
+func TestCheckModerationRules(t *testing.T) {
+	input := &types.WelcomeNoteInput{
+		Recipients:   "Sam",
+		Signature:    "Priya, priya@example.com",
+		Organization: "Faggot Inc",
+		Relationship: "friend at hr@example.com",
+		Locale:       "en",
+	}
+	tests := []struct {
+		name      string
+		note      string
+		blocked   bool
+		sanitized string
+	}{
+		{"signature email is kept", "Welcome, Sam! Write to priya@example.com.", false, "Welcome, Sam! Write to priya@example.com."},
+		{"slur in the organization still blocks", "Welcome to Faggot Inc, Sam!", true, "Welcome to Faggot Inc, Sam!"},
+		{"slur elsewhere still blocks", "Welcome, faggot.", true, "Welcome, faggot."},
+		{"email in free text is masked", "Ask hr@example.com.", false, "Ask [email]."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkModerationRules(tt.note, input)
+			if got.Blocked != tt.blocked {
+				t.Errorf("blocked = %v, want %v (%+v)", got.Blocked, tt.blocked, got.Findings)
+			}
+			if got.Sanitized != tt.sanitized {
+				t.Errorf("sanitized = %q, want %q", got.Sanitized, tt.sanitized)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package flows
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Personalization field names, as used in prompts and in metadata.personalizationUsed
+const (
+	fieldRecipients   = "recipients"
+	fieldSender       = "sender"
+	fieldRelationship = "relationship"
+	fieldOrganization = "organization"
+	fieldSignature    = "signature"
+)
+
+// normalizePersonalization trims the optional personalization fields in place
+func normalizePersonalization(input *types.WelcomeNoteInput) {
+	input.Recipients = strings.TrimSpace(input.Recipients)
+	input.Sender = strings.TrimSpace(input.Sender)
+	input.Relationship = strings.TrimSpace(input.Relationship)
+	input.Organization = strings.TrimSpace(input.Organization)
+	input.Signature = strings.TrimSpace(input.Signature)
+}
+
+// personalizationFields returns the personalization fields that were provided, keyed by field name
+func personalizationFields(input *types.WelcomeNoteInput) map[string]string {
+	fields := map[string]string{}
+	for name, value := range map[string]string{
+		fieldRecipients:   input.Recipients,
+		fieldSender:       input.Sender,
+		fieldRelationship: input.Relationship,
+		fieldOrganization: input.Organization,
+		fieldSignature:    input.Signature,
+	} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// withPersonalization adds the provided personalization fields to prompt template input
+func withPersonalization(vars map[string]any, input *types.WelcomeNoteInput) map[string]any {
+	for name, value := range personalizationFields(input) {
+		vars[name] = value
+	}
+	return vars
+}
+
+// personalizationUsed reports which provided personalization fields the note reflects.
+// Names, organization and signature are checked against the note text; relationship
+// can't be checked literally, so the model's own claim is trusted for it.
+func personalizationUsed(input *types.WelcomeNoteInput, note string, claimed []string) []string {
+	normalizedNote := strings.ToLower(collapseSpaces(note))
+	contains := func(s string) bool {
+		return strings.Contains(normalizedNote, strings.ToLower(collapseSpaces(s)))
+	}
+
+	var used []string
+	if input.Recipients != "" {
+		for _, name := range strings.Split(input.Recipients, ",") {
+			if name = strings.TrimSpace(name); name != "" && contains(name) {
+				used = append(used, fieldRecipients)
+				break
+			}
+		}
+	}
+	if input.Sender != "" && contains(input.Sender) {
+		used = append(used, fieldSender)
+	}
+	if input.Relationship != "" && slices.Contains(claimed, fieldRelationship) {
+		used = append(used, fieldRelationship)
+	}
+	if input.Organization != "" && contains(input.Organization) {
+		used = append(used, fieldOrganization)
+	}
+	if input.Signature != "" && contains(input.Signature) {
+		used = append(used, fieldSignature)
+	}
+	return used
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
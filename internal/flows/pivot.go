@@ -0,0 +1,151 @@
+package flows
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/langdetect"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Whether notes that aren't in English are also moderated in English; see SetModerationPivot
+var moderationPivot = true
+
+// SetModerationPivot sets whether a note in another language is also moderated through
+// an English translation. The moderator's instructions are in English, and it lets
+// sarcasm and insults in other languages through. It must be called at startup, before
+// any flow runs.
+func SetModerationPivot(on bool) {
+	moderationPivot = on
+}
+
+// pivotTranslation is what the translate prompt returns
+type pivotTranslation struct {
+	Translation string `json:"translation"`
+}
+
+// pivotSanitization is what the pivot_sanitize prompt returns
+type pivotSanitization struct {
+	SanitizedNote string `json:"sanitizedNote"`
+}
+
+// noteLanguage returns the language a note is written in: the detected one when the
+// detector is confident, otherwise the one requested, if known
+func noteLanguage(note string, input *types.WelcomeNoteInput) string {
+	if detected := langdetect.Detect(note); detected.Language != "" && detected.Confidence >= minLanguageConfidence {
+		return detected.Language
+	}
+	if input == nil {
+		return ""
+	}
+	return langdetect.Normalize(input.Language)
+}
+
+// translatePivot translates a note into English for moderation. It returns nil for a
+// note in English or in a language that isn't known.
+func translatePivot(ctx context.Context, g *genkit.Genkit, note, language string) (*types.ModerationPivot, error) {
+	if !moderationPivot || language == "" || language == "english" {
+		return nil, nil
+	}
+	rendered, err := renderPrompt(promptTranslate, map[string]any{
+		"note":     note,
+		"language": titleCase(language),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("translating note for moderation: %w", err)
+	}
+	result, _, err := genkit.GenerateData[pivotTranslation](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return nil, fmt.Errorf("translating note for moderation: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, fmt.Errorf("translating note for moderation: %w", err)
+	}
+	if strings.TrimSpace(result.Translation) == "" {
+		return nil, fmt.Errorf("translating note for moderation: empty translation")
+	}
+	return &types.ModerationPivot{Language: language, Translation: result.Translation}, nil
+}
+
+// moderateWithPivot runs the LLM moderator over a note and, when pivot is set, over its
+// English translation at the same time. The stricter verdict wins: the note is blocked
+// if either is, and each category takes the higher severity. A rewrite of the translation
+// is mapped back into the note's language, on top of any rewrite of the note itself.
+func moderateWithPivot(ctx context.Context, g *genkit.Genkit, note string, pivot *types.ModerationPivot, mustSanitize []string) (*types.ModerationResult, error) {
+	if pivot == nil {
+		return runModerationPrompt(ctx, g, note, mustSanitize)
+	}
+
+	var result, english *types.ModerationResult
+	var err, englishErr error
+	var wg sync.WaitGroup
+	wg.Go(func() { result, err = runModerationPrompt(ctx, g, note, mustSanitize) })
+	wg.Go(func() { english, englishErr = runModerationPrompt(ctx, g, pivot.Translation, mustSanitize) })
+	wg.Wait()
+	if err := cmp.Or(err, englishErr); err != nil {
+		return nil, err
+	}
+
+	result.Categories = moderationCategories(append(result.Categories, english.Categories...), nil)
+	result.Pivot = &types.ModerationPivot{Language: pivot.Language, Translation: pivot.Translation}
+	switch {
+	case result.Blocked:
+		return result, nil
+	case english.Blocked:
+		result.Blocked = true
+		result.SanitizedNote = ""
+		result.ModerationNote = "blocked in English translation: " + english.ModerationNote
+		return result, nil
+	case english.SanitizedNote == "" || english.SanitizedNote == pivot.Translation:
+		return result, nil
+	}
+
+	result.Pivot.SanitizedTranslation = english.SanitizedNote
+	sanitized, err := mapPivotSanitization(ctx, g, cmp.Or(result.SanitizedNote, note), result.Pivot)
+	if err != nil {
+		return nil, err
+	}
+	result.SanitizedNote = sanitized
+	result.ModerationNote = strings.TrimPrefix(result.ModerationNote+"; in English translation: "+english.ModerationNote, "; ")
+	return result, nil
+}
+
+// mapPivotSanitization makes the changes moderation made to the English translation to
+// the note in its own language
+func mapPivotSanitization(ctx context.Context, g *genkit.Genkit, note string, pivot *types.ModerationPivot) (string, error) {
+	rendered, err := renderPrompt(promptPivot, map[string]any{
+		"note":                 note,
+		"language":             titleCase(pivot.Language),
+		"translation":          pivot.Translation,
+		"sanitizedTranslation": pivot.SanitizedTranslation,
+	})
+	if err != nil {
+		return "", fmt.Errorf("mapping sanitization back from English: %w", err)
+	}
+	result, _, err := genkit.GenerateData[pivotSanitization](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return "", fmt.Errorf("mapping sanitization back from English: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return "", fmt.Errorf("mapping sanitization back from English: %w", err)
+	}
+	if strings.TrimSpace(result.SanitizedNote) == "" {
+		return "", fmt.Errorf("mapping sanitization back from English: empty note")
+	}
+	return result.SanitizedNote, nil
+}
+
+// recordPivotPromptVersions adds the versions of the pivot prompts a moderation used
+func recordPivotPromptVersions(metadata *types.WelcomeNoteV3Metadata, pivot *types.ModerationPivot) {
+	if pivot == nil {
+		return
+	}
+	recordPromptVersion(metadata, promptTranslate)
+	if pivot.SanitizedTranslation != "" {
+		recordPromptVersion(metadata, promptPivot)
+	}
+}
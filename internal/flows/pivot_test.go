@@ -0,0 +1,285 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// stubModerator answers the moderation, translate and pivot_sanitize prompts from fixed
+// tables keyed by the note in the prompt, so moderation runs without a model provider.
+// The refine prompt always gets revision back.
+type stubModerator struct {
+	verdicts     map[string]types.ModerationResult // note -> what the moderator says about it
+	translations map[string]string                 // note -> its English translation
+	revision     string                            // the refine prompt's revised note
+}
+
+// newStubGenkit returns a Genkit whose default model is m, with the repo's prompts loaded
+func newStubGenkit(t *testing.T, m *stubModerator) *genkit.Genkit {
+	t.Helper()
+	store, err := prompts.Load("../../prompts")
+	if err != nil {
+		t.Fatalf("loading prompts: %v", err)
+	}
+	SetPromptStore(store)
+	t.Cleanup(func() { SetPromptStore(nil) })
+
+	ctx := context.Background()
+	g := genkit.Init(ctx, genkit.WithDefaultModel("test/moderator"))
+	genkit.DefineModel(g, "test/moderator", &ai.ModelOptions{
+		Supports: &ai.ModelSupports{Constrained: ai.ConstrainedSupportAll, Multiturn: true, SystemRole: true},
+	}, m.generate)
+	return g
+}
+
+func (m *stubModerator) generate(_ context.Context, req *ai.ModelRequest, _ ai.ModelStreamCallback) (*ai.ModelResponse, error) {
+	var system, user strings.Builder
+	for _, msg := range req.Messages {
+		if msg.Role == ai.RoleSystem {
+			system.WriteString(msg.Text())
+		} else {
+			user.WriteString(msg.Text())
+		}
+	}
+
+	var reply any
+	switch {
+	case strings.Contains(system.String(), "You are a translator"):
+		reply = pivotTranslation{Translation: m.translations[m.note(user.String())]}
+	case strings.Contains(system.String(), "revises welcome notes"):
+		reply = refinement{Note: m.revision, Changes: "revised"}
+	case strings.Contains(system.String(), "You are a careful editor"):
+		reply = pivotSanitization{SanitizedNote: "mapped: " + m.note(user.String())}
+	default:
+		reply = m.verdicts[m.note(user.String())]
+	}
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &ai.ModelResponse{Request: req, Message: ai.NewModelTextMessage(string(data))}, nil
+}
+
+// note returns the longest known note the prompt contains
+func (m *stubModerator) note(prompt string) string {
+	var found string
+	for note := range m.verdicts {
+		if len(note) > len(found) && strings.Contains(prompt, note) {
+			found = note
+		}
+	}
+	for note := range m.translations {
+		if len(note) > len(found) && strings.Contains(prompt, note) {
+			found = note
+		}
+	}
+	return found
+}
+
+func scores(severities map[string]float64) []types.CategoryScore {
+	var categories []types.CategoryScore
+	for _, c := range types.ModerationCategories {
+		categories = append(categories, types.CategoryScore{Category: c, Severity: severities[c]})
+	}
+	return categories
+}
+
+func severity(categories []types.CategoryScore, category string) float64 {
+	for _, c := range categories {
+		if c.Category == category {
+			return c.Severity
+		}
+	}
+	return -1
+}
+
+func TestModerateWithPivot(t *testing.T) {
+	const note, translation = "Bienvenido, genio.", "Welcome, genius."
+	allow := func(severities map[string]float64) types.ModerationResult {
+		return types.ModerationResult{ModerationNote: "ok", Categories: scores(severities)}
+	}
+	block := func(severities map[string]float64) types.ModerationResult {
+		return types.ModerationResult{Blocked: true, ModerationNote: "insult", Categories: scores(severities)}
+	}
+	tests := []struct {
+		name       string
+		native     types.ModerationResult
+		english    types.ModerationResult
+		blocked    bool
+		note       string // the moderation note starts with it
+		severities map[string]float64
+	}{
+		{
+			name:       "both allow",
+			native:     allow(map[string]float64{types.CategoryToxicity: 0.1}),
+			english:    allow(map[string]float64{types.CategoryHarassment: 0.2}),
+			note:       "ok",
+			severities: map[string]float64{types.CategoryToxicity: 0.1, types.CategoryHarassment: 0.2},
+		},
+		{
+			name:       "english blocks",
+			native:     allow(map[string]float64{types.CategoryHarassment: 0.2}),
+			english:    block(map[string]float64{types.CategoryHarassment: 0.8}),
+			blocked:    true,
+			note:       "blocked in English translation: insult",
+			severities: map[string]float64{types.CategoryHarassment: 0.8},
+		},
+		{
+			name:       "native blocks",
+			native:     block(map[string]float64{types.CategoryHate: 0.9}),
+			english:    allow(map[string]float64{types.CategoryHate: 0.3, types.CategoryToxicity: 0.4}),
+			blocked:    true,
+			note:       "insult",
+			severities: map[string]float64{types.CategoryHate: 0.9, types.CategoryToxicity: 0.4},
+		},
+		{
+			name:       "both block",
+			native:     block(map[string]float64{types.CategoryHarassment: 0.7}),
+			english:    block(map[string]float64{types.CategoryHarassment: 1}),
+			blocked:    true,
+			note:       "insult",
+			severities: map[string]float64{types.CategoryHarassment: 1},
+		},
+		{
+			name:       "english rewrite is mapped back",
+			native:     allow(nil),
+			english:    types.ModerationResult{SanitizedNote: "Welcome.", ModerationNote: "removed sarcasm", Categories: scores(map[string]float64{types.CategoryHarassment: 0.4})},
+			note:       "ok; in English translation: removed sarcasm",
+			severities: map[string]float64{types.CategoryHarassment: 0.4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newStubGenkit(t, &stubModerator{
+				verdicts: map[string]types.ModerationResult{note: tt.native, translation: tt.english},
+			})
+			pivot := &types.ModerationPivot{Language: "spanish", Translation: translation}
+
+			got, err := moderateWithPivot(context.Background(), g, note, pivot, nil)
+			if err != nil {
+				t.Fatalf("moderateWithPivot = %v", err)
+			}
+			if got.Blocked != tt.blocked {
+				t.Errorf("blocked = %v, want %v", got.Blocked, tt.blocked)
+			}
+			if !strings.HasPrefix(got.ModerationNote, tt.note) {
+				t.Errorf("moderation note = %q, want it to start with %q", got.ModerationNote, tt.note)
+			}
+			for _, c := range types.ModerationCategories {
+				if s := severity(got.Categories, c); s != tt.severities[c] {
+					t.Errorf("%s severity = %v, want %v", c, s, tt.severities[c])
+				}
+			}
+			if got.Pivot == nil || got.Pivot.Translation != translation {
+				t.Errorf("pivot = %+v, want the translation", got.Pivot)
+			}
+			if tt.blocked && got.SanitizedNote != "" && tt.english.Blocked {
+				t.Errorf("sanitized note = %q, want none for a blocked note", got.SanitizedNote)
+			}
+			if tt.english.SanitizedNote != "" && !tt.blocked {
+				if got.Pivot.SanitizedTranslation != tt.english.SanitizedNote || got.SanitizedNote != "mapped: "+note {
+					t.Errorf("got %q from pivot %+v, want the English rewrite mapped back", got.SanitizedNote, got.Pivot)
+				}
+			}
+		})
+	}
+}
+
+// moderationFixture is one labeled note of fixtures/moderation/multilingual.json
+type moderationFixture struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+	Note     string `json:"note"`
+	Unsafe   bool   `json:"unsafe"`
+	Category string `json:"category,omitempty"`
+}
+
+// TestModerateNoteMultilingualFixtures moderates the evaluation fixtures with a stub
+// moderator that, like the real one, misses insults outside English: it only ever blocks
+// English text. Every unsafe note must still be blocked through its pivot translation.
+func TestModerateNoteMultilingualFixtures(t *testing.T) {
+	data, err := os.ReadFile("../../fixtures/moderation/multilingual.json")
+	if err != nil {
+		t.Fatalf("reading fixtures: %v", err)
+	}
+	var fixtures []moderationFixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("reading fixtures: %v", err)
+	}
+
+	stub := &stubModerator{verdicts: map[string]types.ModerationResult{}, translations: map[string]string{}}
+	for _, f := range fixtures {
+		english := f.Note
+		if f.Language != "english" {
+			english = "English translation of " + f.ID
+			stub.translations[f.Note] = english
+			// mild at most in the note's own language
+			severities := map[string]float64{types.CategoryToxicity: 0.1}
+			if f.Unsafe {
+				severities[f.Category] = max(severities[f.Category], 0.3)
+			}
+			stub.verdicts[f.Note] = types.ModerationResult{ModerationNote: "ok", Categories: scores(severities)}
+		}
+		if f.Unsafe {
+			stub.verdicts[english] = types.ModerationResult{Blocked: true, ModerationNote: f.Category, Categories: scores(map[string]float64{f.Category: 0.9})}
+		} else {
+			stub.verdicts[english] = types.ModerationResult{ModerationNote: "ok", Categories: scores(map[string]float64{types.CategoryHarassment: 0.05})}
+		}
+	}
+	g := newStubGenkit(t, stub)
+
+	// the rules would catch some fixtures before the moderator sees them
+	SetModerationRules(nil)
+	defer SetModerationRules(mustModerationRules(moderation.New(moderation.Defaults())))
+
+	for _, f := range fixtures {
+		t.Run(f.ID, func(t *testing.T) {
+			got, err := ModerateNote(context.Background(), g, f.Note, &types.WelcomeNoteInput{Language: f.Language})
+			if err != nil {
+				t.Fatalf("ModerateNote = %v", err)
+			}
+			if got.Blocked != f.Unsafe {
+				t.Fatalf("blocked = %v, want %v (%s)", got.Blocked, f.Unsafe, got.ModerationNote)
+			}
+			wantDecision := moderation.DecisionAllow
+			if f.Unsafe {
+				wantDecision = moderation.DecisionBlock
+			}
+			if got.Decision != wantDecision {
+				t.Errorf("decision = %q, want %q", got.Decision, wantDecision)
+			}
+			if f.Language == "english" {
+				if got.Pivot != nil {
+					t.Errorf("pivot = %+v, want none for an English note", got.Pivot)
+				}
+				return
+			}
+
+			if got.Pivot == nil || !strings.HasSuffix(got.Pivot.Translation, f.ID) {
+				t.Fatalf("pivot = %+v, want the translation of %s", got.Pivot, f.ID)
+			}
+			// each category takes the higher of the two severities
+			want := map[string]float64{types.CategoryToxicity: 0.1, types.CategoryHarassment: 0.05}
+			if f.Unsafe {
+				want = map[string]float64{types.CategoryToxicity: 0.1, f.Category: 0.9}
+			}
+			for _, c := range types.ModerationCategories {
+				if s := severity(got.Categories, c); s != want[c] {
+					t.Errorf("%s severity = %v, want %v", c, s, want[c])
+				}
+			}
+			if f.Unsafe && !strings.HasPrefix(got.ModerationNote, "blocked in English translation") {
+				t.Errorf("moderation note = %q, want the English verdict", got.ModerationNote)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package flows
+
+import (
+	"context"
+	"time"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+const (
+	stepStatusRunning = "running"
+	stepStatusDone    = "done"
+	stepStatusFailed  = "failed"
+)
+
+// runStep wraps genkit.Run and reports the step's progress through cb:
+// a "running" event before fn starts and a "done" or "failed" event with the
+// duration (and the step output on success) once it returns.
+// cb may be nil when the flow is not being streamed.
+func runStep[Out any](ctx context.Context, name string, cb core.StreamCallback[*types.PipelineProgress], fn func() (Out, error)) (Out, error) {
+	report := func(p *types.PipelineProgress) error {
+		if cb == nil {
+			return nil
+		}
+		return cb(ctx, p)
+	}
+
+	if err := report(&types.PipelineProgress{Step: name, Status: stepStatusRunning}); err != nil {
+		var zero Out
+		return zero, err
+	}
+
+	start := time.Now()
+	out, err := genkit.Run(ctx, name, fn)
+	elapsed := time.Since(start).Milliseconds()
+
+	if err != nil {
+		// the step error takes precedence over a failure to report it
+		_ = report(&types.PipelineProgress{Step: name, Status: stepStatusFailed, DurationMs: elapsed, Error: err.Error()})
+		return out, err
+	}
+
+	if err := report(&types.PipelineProgress{Step: name, Status: stepStatusDone, DurationMs: elapsed, Output: out}); err != nil {
+		return out, err
+	}
+	return out, nil
+}
@@ -0,0 +1,54 @@
+package flows
+
+import (
+	"fmt"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Prompt names, matching the .prompt files in the prompt directory
+const (
+	promptWelcomeV1  = "welcome_v1"
+	promptWelcomeV2  = "welcome_v2"
+	promptWelcomeV3  = "welcome_v3"
+	promptModeration = "moderation"
+	promptInterpret  = "interpret"
+	promptJudge      = "judge"
+	promptRefine     = "refine"
+	promptInputGuard = "input_guard"
+	promptTranslate  = "translate"
+	promptPivot      = "pivot_sanitize"
+)
+
+var promptStore *prompts.Store
+
+// SetPromptStore sets the prompt templates used by all flows.
+// It must be called at startup, before any flow runs.
+func SetPromptStore(s *prompts.Store) {
+	promptStore = s
+}
+
+// renderPrompt renders one of the flow prompts from the configured store
+func renderPrompt(name string, input map[string]any) (*prompts.Rendered, error) {
+	if promptStore == nil {
+		return nil, fmt.Errorf("prompt store is not configured")
+	}
+	return promptStore.Render(name, input)
+}
+
+// recordPromptVersion adds the version of the named prompt to the note metadata.
+// The store is immutable once loaded, so this is the version that was rendered.
+func recordPromptVersion(metadata *types.WelcomeNoteV3Metadata, name string) {
+	if promptStore == nil {
+		return
+	}
+	p := promptStore.Lookup(name)
+	if p == nil {
+		return
+	}
+	if metadata.PromptVersions == nil {
+		metadata.PromptVersions = map[string]string{}
+	}
+	metadata.PromptVersions[name] = p.Version
+}
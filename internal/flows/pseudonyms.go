@@ -0,0 +1,129 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/prompts"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/pseudonym"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Whether personal data is replaced by placeholders before prompts reach the model
+var pseudonymize = true
+
+// SetPseudonymization turns pseudonymization of names, emails, phone numbers, card
+// numbers and national IDs in prompts on or off. It must be called at startup, before
+// any flow runs.
+func SetPseudonymization(enabled bool) {
+	pseudonymize = enabled
+}
+
+// placeholderNotice is added to the system prompt of a pseudonymized request
+const placeholderNotice = "Some personal details in this request are replaced by placeholders such as [PERSON_1] or " +
+	"[EMAIL_1]. Write a placeholder exactly as given, brackets included, wherever its real value belongs. " +
+	"Never guess, invent or describe the real value."
+
+type vaultKey struct{}
+
+// requestVault returns the pseudonym vault of the request, nil when pseudonymization is off
+func requestVault(ctx context.Context) *pseudonym.Vault {
+	v, _ := ctx.Value(vaultKey{}).(*pseudonym.Vault)
+	return v
+}
+
+// withPseudonyms returns a context whose vault knows the names in names (fields listing
+// people) and in text (free text such as the occasion). A flow called from another flow
+// adds to the caller's vault, so a value keeps one placeholder for the whole request.
+// The vault lives only as long as the request's context.
+func withPseudonyms(ctx context.Context, names []string, text ...string) context.Context {
+	if !pseudonymize {
+		return ctx
+	}
+	v := requestVault(ctx)
+	if v == nil {
+		v = pseudonym.NewVault()
+		ctx = context.WithValue(ctx, vaultKey{}, v)
+	}
+	for _, field := range names {
+		v.AddNameList(field)
+	}
+	for _, t := range text {
+		v.AddNames(t)
+		v.AddPII(t)
+	}
+	return ctx
+}
+
+// withInputPseudonyms is withPseudonyms for the fields of a WelcomeNoteInput
+func withInputPseudonyms(ctx context.Context, input *types.WelcomeNoteInput) context.Context {
+	return withPseudonyms(ctx,
+		[]string{input.Recipients, input.Sender, input.Signature},
+		input.Occasion, input.Relationship, input.Signature)
+}
+
+// promptOptions passes a rendered prompt to the model its step is routed to, with the
+// request's personal data replaced by placeholders
+func promptOptions(ctx context.Context, rendered *prompts.Rendered) []ai.GenerateOption {
+	system, user := rendered.System, rendered.User
+	if v := requestVault(ctx); v != nil {
+		user = v.Mask(user)
+		system = v.Mask(system)
+		if v.Len() > 0 {
+			system += "\n\n" + placeholderNotice
+		}
+	}
+	opts := []ai.GenerateOption{
+		ai.WithSystem(system),
+		ai.WithPrompt(user),
+	}
+	if model := modelFor(ctx, rendered.Name); model != "" {
+		opts = append(opts, ai.WithModelName(model))
+		recordModel(ctx, rendered.Name, model)
+	}
+	return opts
+}
+
+// restoreText puts the real values back in model output
+func restoreText(ctx context.Context, text string) string {
+	if v := requestVault(ctx); v != nil {
+		return v.Restore(text)
+	}
+	return text
+}
+
+// restorePartialText is restoreText for output that is still streaming
+func restorePartialText(ctx context.Context, text string) string {
+	if v := requestVault(ctx); v != nil {
+		return v.RestorePartial(text)
+	}
+	return text
+}
+
+// restoreData puts the real values back in every string of structured model output
+func restoreData[T any](ctx context.Context, out *T) error {
+	v := requestVault(ctx)
+	if v == nil || out == nil || v.Len() == 0 {
+		return nil
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("restoring pseudonyms: %w", err)
+	}
+	var restored T
+	if err := json.Unmarshal(v.RestoreJSON(data), &restored); err != nil {
+		return fmt.Errorf("restoring pseudonyms: %w", err)
+	}
+	*out = restored
+	return nil
+}
+
+// pseudonymCounts reports how many values of each kind the request pseudonymized
+func pseudonymCounts(ctx context.Context) map[string]int {
+	if v := requestVault(ctx); v != nil {
+		return v.Counts()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package flows
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// How many times the Safe flow regenerates a blocked note; see SetRemediationRetries
+var remediationRetries = 0
+
+// SetRemediationRetries sets how many times the Safe flow regenerates a blocked note,
+// telling the model what moderation objected to. When every attempt is blocked, a
+// template note is returned instead. 0 disables remediation; blocked notes stay blocked.
+func SetRemediationRetries(n int) {
+	remediationRetries = max(n, 0)
+}
+
+type remediationKey struct{}
+
+// withBlockedAttempts returns a context that makes V3 generation avoid what moderation
+// blocked in attempts
+func withBlockedAttempts(ctx context.Context, attempts []types.RemediationAttempt) context.Context {
+	return context.WithValue(ctx, remediationKey{}, attempts)
+}
+
+// withRemediation adds the categories and reasons of the blocked attempts carried by ctx
+// to prompt variables. Categories are listed once, most severe first.
+func withRemediation(ctx context.Context, vars map[string]any) map[string]any {
+	attempts, _ := ctx.Value(remediationKey{}).([]types.RemediationAttempt)
+	severity := map[string]float64{}
+	var reasons []string
+	for _, a := range attempts {
+		if a.Decision != moderation.DecisionBlock {
+			continue
+		}
+		for _, c := range a.Categories {
+			if c.Severity > 0 {
+				severity[c.Category] = max(severity[c.Category], c.Severity)
+			}
+		}
+		if reason := cmp.Or(a.ModerationNote, a.DecisionReason); reason != "" && !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(severity) == 0 && len(reasons) == 0 {
+		return vars
+	}
+
+	categories := make([]string, 0, len(severity))
+	for c := range severity {
+		categories = append(categories, c)
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		return cmp.Or(cmp.Compare(severity[b], severity[a]), strings.Compare(a, b))
+	})
+	if len(categories) > 0 {
+		vars["blockedCategories"] = categories
+	}
+	if len(reasons) > 0 {
+		vars["blockedReasons"] = reasons
+	}
+	return vars
+}
+
+// remediateBlockedNote regenerates a blocked Safe note up to remediationRetries times,
+// each time telling the model what moderation blocked so far. The first note that isn't
+// blocked is returned; when all are, a template note is. Either way the output lists
+// every attempt.
+func remediateBlockedNote(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, blocked *types.SafeWelcomeNoteOutput) (*types.SafeWelcomeNoteOutput, error) {
+	attempts := []types.RemediationAttempt{remediationAttempt(1, blocked)}
+	for attempt := 2; attempt <= remediationRetries+1; attempt++ {
+		base, err := generateWelcomeNote3(withBlockedAttempts(ctx, attempts), g, input)
+		if err != nil {
+			return nil, fmt.Errorf("remediation attempt %d: %w", attempt, err)
+		}
+		out, err := moderateSafeNote(ctx, g, base, input)
+		if err != nil {
+			return nil, fmt.Errorf("remediation attempt %d: %w", attempt, err)
+		}
+		attempts = append(attempts, remediationAttempt(attempt, out))
+		if !out.Blocked {
+			out.Remediation = attempts
+			return out, nil
+		}
+	}
+
+	slog.WarnContext(ctx, "every attempt was blocked, using the fallback note", slog.Int("attempts", len(attempts)))
+	return fallbackOutput(ctx, g, input, blocked, attempts)
+}
+
+// moderateSafeNote moderates a generated note, or all of its candidates, into a Safe output
+func moderateSafeNote(ctx context.Context, g *genkit.Genkit, base *types.WelcomeNoteV3Output, input *types.WelcomeNoteInput) (*types.SafeWelcomeNoteOutput, error) {
+	if len(base.Candidates) > 0 {
+		candidates, err := moderateCandidates(ctx, g, base.Candidates, input)
+		if err != nil {
+			return nil, err
+		}
+		return safeOutputFromCandidates(base, candidates), nil
+	}
+	moderated, err := moderateWelcomeNote(ctx, g, base.Note, input)
+	if err != nil {
+		return nil, err
+	}
+	return safeOutput(base, moderated), nil
+}
+
+func remediationAttempt(attempt int, out *types.SafeWelcomeNoteOutput) types.RemediationAttempt {
+	return types.RemediationAttempt{
+		Attempt:        attempt,
+		Decision:       out.Decision,
+		DecisionReason: out.DecisionReason,
+		ModerationNote: out.ModerationNote,
+		Categories:     out.Categories,
+	}
+}
+
+// fallbackOutput is the Safe output when every attempt was blocked: a template note
+// with none of the blocked text. The request's personalization is user text, so the
+// template built from it is moderated like any note; unless moderation allows it as
+// written, the template leaves the personalization out.
+func fallbackOutput(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, blocked *types.SafeWelcomeNoteOutput, attempts []types.RemediationAttempt) (*types.SafeWelcomeNoteOutput, error) {
+	note, reason := fallbackNote(input, false), "template note"
+	if personalized := fallbackNote(input, true); personalized != note {
+		moderated, err := moderateWelcomeNote(ctx, g, personalized, input)
+		if err != nil {
+			return nil, fmt.Errorf("moderating the fallback note: %w", err)
+		}
+		if moderated.Decision == moderation.DecisionAllow {
+			note = personalized
+		} else {
+			reason = "template note without personalization: " + cmp.Or(moderated.DecisionReason, moderated.Decision)
+		}
+	}
+
+	return &types.SafeWelcomeNoteOutput{
+		Note:     note,
+		Occasion: blocked.Occasion,
+		Language: blocked.Language,
+		Length:   blocked.Length,
+		Tone:     blocked.Tone,
+		Metadata: types.WelcomeNoteV3Metadata{
+			PromptVersions: blocked.Metadata.PromptVersions,
+			Locale:         blocked.Metadata.Locale,
+			ToneBlend:      blocked.Metadata.ToneBlend,
+			InputGuard:     blocked.Metadata.InputGuard,
+			Pseudonymized:  blocked.Metadata.Pseudonymized,
+		},
+
+		ModerationNote: fmt.Sprintf("all %d generated notes were blocked", len(attempts)),
+		Decision:       moderation.DecisionAllow,
+		DecisionReason: reason,
+
+		Remediation: attempts,
+		Fallback:    true,
+	}, nil
+}
+
+// fallbackText is a template welcome note in one language. Sentences are joined with
+// sep, a space unless the language doesn't use one.
+type fallbackText struct {
+	hello     string // greets the recipients, given as %s
+	welcome   string
+	welcomeTo string // welcomes to the organization, given as %s
+	glad      string
+	sep       string
+}
+
+// fallbackTexts are keyed by base language; other languages get English
+var fallbackTexts = map[string]fallbackText{
+	"english":    {"Hello, %s!", "Welcome!", "Welcome to %s!", "We're glad you're here.", " "},
+	"spanish":    {"¡Hola, %s!", "¡Te damos la bienvenida!", "¡Te damos la bienvenida a %s!", "Nos alegra mucho tenerte aquí.", " "},
+	"french":     {"Bonjour %s !", "Bienvenue !", "Bienvenue chez %s !", "Nous sommes ravis de vous accueillir.", " "},
+	"german":     {"Hallo %s!", "Herzlich willkommen!", "Herzlich willkommen bei %s!", "Schön, dass Sie da sind.", " "},
+	"portuguese": {"Olá, %s!", "Boas-vindas!", "Boas-vindas a %s!", "É uma alegria ter você aqui.", " "},
+	"italian":    {"Ciao %s!", "Un caloroso benvenuto!", "Un caloroso benvenuto in %s!", "Siamo felici di averti qui.", " "},
+	"dutch":      {"Hallo %s!", "Welkom!", "Welkom bij %s!", "Fijn dat je er bent.", " "},
+	"hindi":      {"नमस्ते %s!", "आपका स्वागत है!", "%s में आपका स्वागत है!", "हमें खुशी है कि आप यहाँ हैं।", " "},
+	"japanese":   {"%sさん、こんにちは！", "ようこそ！", "%sへようこそ！", "お迎えできてうれしいです。", ""},
+}
+
+// fallbackNote writes a short, neutral welcome note in the input's language where a
+// template exists, greeting the recipients and naming the organization and signature
+// when personalized
+func fallbackNote(input *types.WelcomeNoteInput, personalized bool) string {
+	t, ok := fallbackTexts[baseLanguage(input)]
+	if !ok {
+		t = fallbackTexts["english"]
+	}
+
+	var sentences []string
+	if personalized && input.Recipients != "" {
+		sentences = append(sentences, fmt.Sprintf(t.hello, input.Recipients))
+	}
+	if personalized && input.Organization != "" {
+		sentences = append(sentences, fmt.Sprintf(t.welcomeTo, input.Organization))
+	} else {
+		sentences = append(sentences, t.welcome)
+	}
+	sentences = append(sentences, t.glad)
+
+	note := strings.Join(sentences, t.sep)
+	if personalized && input.Signature != "" {
+		note += "\n\n" + input.Signature
+	}
+	return note
+}
@@ -0,0 +1,30 @@
+package flows
+
+import (
+	"testing"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestFallbackNote(t *testing.T) {
+	full := types.WelcomeNoteInput{Language: "english", Recipients: "Jane", Organization: "Acme", Signature: "The Acme team"}
+	tests := []struct {
+		name         string
+		input        types.WelcomeNoteInput
+		personalized bool
+		want         string
+	}{
+		{"personalized", full, true, "Hello, Jane! Welcome to Acme! We're glad you're here.\n\nThe Acme team"},
+		{"without personalization", full, false, "Welcome! We're glad you're here."},
+		{"nothing to personalize", types.WelcomeNoteInput{Language: "english"}, true, "Welcome! We're glad you're here."},
+		{"language without a space", types.WelcomeNoteInput{Language: "japanese", Recipients: "ハナ"}, true, "ハナさん、こんにちは！ようこそ！お迎えできてうれしいです。"},
+		{"language without a template", types.WelcomeNoteInput{Language: "telugu"}, false, "Welcome! We're glad you're here."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fallbackNote(&tt.input, tt.personalized); got != tt.want {
+				t.Errorf("fallbackNote = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
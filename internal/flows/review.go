@@ -0,0 +1,90 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// What the generator's metadata says about a note a person should check
+const safetyNeedsReview = "needs_review"
+
+// Optional queue of notes held for a reviewer; see SetReviewQueue
+var reviewQueue review.Store
+
+// SetReviewQueue makes the Safe and Smart flows queue the notes they return that need a
+// person to check them: notes the generator marked needs_review and notes moderation held
+// for review. nil queues nothing. It must be called at startup, before any flow runs.
+func SetReviewQueue(q review.Store) {
+	reviewQueue = q
+}
+
+// ReviewQueue returns the review queue, nil when notes aren't queued
+func ReviewQueue() review.Store {
+	return reviewQueue
+}
+
+// reviewReasons returns why out should be held for review; none means it needn't be
+func reviewReasons(out *types.SafeWelcomeNoteOutput) []string {
+	if out.Blocked {
+		return nil
+	}
+	var reasons []string
+	if out.Metadata.Safety == safetyNeedsReview {
+		reasons = append(reasons, "generator marked the note needs_review")
+	}
+	if out.Decision == moderation.DecisionReview {
+		reasons = append(reasons, "moderation: "+out.DecisionReason)
+	}
+	return reasons
+}
+
+// needsReview reports whether out's note must wait for a person: it is queued when there
+// is a review queue, and a note moderation held for review is withheld even without one
+func needsReview(out *types.SafeWelcomeNoteOutput) bool {
+	if out.Blocked {
+		return false
+	}
+	return out.Decision == moderation.DecisionReview || (reviewQueue != nil && len(reviewReasons(out)) > 0)
+}
+
+// queueForReview holds out's note for a reviewer. It is added to the review queue and the
+// requester gets out back with the note withheld, to follow at /api/review/{id}. Without a
+// queue nobody could release it, so it stays withheld. A failed write fails the request
+// rather than releasing the note.
+func queueForReview(ctx context.Context, out *types.SafeWelcomeNoteOutput) (*types.SafeWelcomeNoteOutput, error) {
+	held := withheld(out)
+	if reviewQueue == nil {
+		held.DecisionReason = strings.TrimPrefix(held.DecisionReason+"; withheld: no review queue to release it", "; ")
+		return held, nil
+	}
+
+	it := newReviewItem(ctx, review.KindReview, out)
+	it.Reasons = reviewReasons(out)
+	if err := reviewQueue.Add(ctx, it); err != nil {
+		return nil, fmt.Errorf("queueing note for review: %w", err)
+	}
+	held.ReviewID = it.ID
+	held.ReviewStatus = it.Status
+	return held, nil
+}
+
+// newReviewItem returns a pending item of the given kind for the note out carries
+func newReviewItem(ctx context.Context, kind string, out *types.SafeWelcomeNoteOutput) review.Item {
+	it := review.NewItem(kind, out.Note)
+	it.RequestID = audit.RequestID(ctx)
+	it.Flow = core.FlowNameFromContext(ctx)
+	it.OriginalNote = out.OriginalNote
+	it.Occasion = out.Occasion
+	it.Language = out.Language
+	it.Tone = out.Tone
+	it.ModerationNote = out.ModerationNote
+	it.Categories = out.Categories
+	return it
+}
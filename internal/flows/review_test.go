@@ -0,0 +1,93 @@
+package flows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// failingQueue is a review queue whose writes fail
+type failingQueue struct{ review.Store }
+
+func (failingQueue) Add(context.Context, review.Item) error { return errors.New("disk full") }
+
+func TestNeedsReview(t *testing.T) {
+	tests := []struct {
+		name  string
+		queue review.Store
+		out   types.SafeWelcomeNoteOutput
+		want  bool
+	}{
+		{"allowed", review.NewMemoryStore(nil), types.SafeWelcomeNoteOutput{Decision: moderation.DecisionAllow}, false},
+		{"held by moderation", review.NewMemoryStore(nil), types.SafeWelcomeNoteOutput{Decision: moderation.DecisionReview}, true},
+		{"held by moderation without a queue", nil, types.SafeWelcomeNoteOutput{Decision: moderation.DecisionReview}, true},
+		{"marked by the generator", review.NewMemoryStore(nil), types.SafeWelcomeNoteOutput{Metadata: types.WelcomeNoteV3Metadata{Safety: safetyNeedsReview}}, true},
+		{"marked by the generator without a queue", nil, types.SafeWelcomeNoteOutput{Metadata: types.WelcomeNoteV3Metadata{Safety: safetyNeedsReview}}, false},
+		{"blocked", review.NewMemoryStore(nil), types.SafeWelcomeNoteOutput{Blocked: true, Decision: moderation.DecisionReview}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetReviewQueue(tt.queue)
+			t.Cleanup(func() { SetReviewQueue(nil) })
+			if got := needsReview(&tt.out); got != tt.want {
+				t.Errorf("needsReview = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueForReview(t *testing.T) {
+	held := func() *types.SafeWelcomeNoteOutput {
+		return &types.SafeWelcomeNoteOutput{
+			Note:           "Welcome, Sam!",
+			Decision:       moderation.DecisionReview,
+			DecisionReason: "toxicity 0.62 ≥ review threshold 0.50",
+			Candidates:     []types.WelcomeNoteCandidate{{Rank: 1, Note: "Welcome, Sam!"}, {Rank: 2, Note: "Hi Sam!"}},
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("queued", func(t *testing.T) {
+		queue := review.NewMemoryStore(nil)
+		SetReviewQueue(queue)
+		t.Cleanup(func() { SetReviewQueue(nil) })
+
+		got, err := queueForReview(ctx, held())
+		if err != nil {
+			t.Fatalf("queueForReview = %v", err)
+		}
+		if got.Note != "" || got.Candidates != nil || got.ReviewStatus != review.StatusPending {
+			t.Errorf("output = %+v, want the note withheld pending review", got)
+		}
+		it, err := queue.Get(ctx, got.ReviewID)
+		if err != nil {
+			t.Fatalf("Get = %v", err)
+		}
+		if it.Note != "Welcome, Sam!" {
+			t.Errorf("queued note = %q, want the held note", it.Note)
+		}
+	})
+
+	t.Run("failed write", func(t *testing.T) {
+		SetReviewQueue(failingQueue{})
+		t.Cleanup(func() { SetReviewQueue(nil) })
+
+		if got, err := queueForReview(ctx, held()); err == nil {
+			t.Errorf("queueForReview = %+v, want an error", got)
+		}
+	})
+
+	t.Run("no queue", func(t *testing.T) {
+		got, err := queueForReview(ctx, held())
+		if err != nil {
+			t.Fatalf("queueForReview = %v", err)
+		}
+		if got.Note != "" || got.Candidates != nil || got.ReviewID != "" {
+			t.Errorf("output = %+v, want the note withheld", got)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package flows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/sessions"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Most recent conversation turns included in the interpret prompt
+const maxHistoryTurns = 10
+
+// Kept in a session in place of a note moderation blocked, or one withheld for review
+const (
+	blockedTurnNote = "[blocked]"
+	heldTurnNote    = "[held for review]"
+)
+
+var sessionStore sessions.Store
+
+// SetSessionStore enables conversations in the Smart flow.
+// Without a store every description is interpreted on its own.
+func SetSessionStore(s sessions.Store) {
+	sessionStore = s
+}
+
+// loadSession returns the session to continue, or a new one when id is empty,
+// unknown or expired. It returns nil when sessions are disabled.
+func loadSession(ctx context.Context, id string) (*sessions.Session, error) {
+	if sessionStore == nil {
+		return nil, nil
+	}
+	if id == "" {
+		return sessions.New(), nil
+	}
+	session, err := sessionStore.Get(ctx, id)
+	if errors.Is(err, sessions.ErrNotFound) {
+		return sessions.New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+	return session, nil
+}
+
+// recordTurn adds a description and the note generated for it to the session and saves it.
+// A blocked note is recorded as a placeholder: history is shown again and fed into the next
+// turn's interpret prompt.
+func recordTurn(ctx context.Context, session *sessions.Session, description string, input *types.WelcomeNoteInput, out *types.SafeWelcomeNoteOutput) error {
+	note := out.Note
+	switch {
+	case out.Blocked:
+		note = blockedTurnNote
+	case note == "":
+		note = heldTurnNote
+	}
+	session.AddTurn(sessions.RoleUser, description)
+	session.AddTurn(sessions.RoleAssistant, note)
+	interpreted := *input
+	session.LastInput = &interpreted
+	session.Notes = append(session.Notes, note)
+
+	if err := sessionStore.Save(ctx, session); err != nil {
+		return fmt.Errorf("saving session: %w", err)
+	}
+	return nil
+}
+
+// withSessionPseudonyms adds the names and personal data of an earlier conversation
+// to the request's vault, since the interpret prompt repeats them
+func withSessionPseudonyms(ctx context.Context, session *sessions.Session) context.Context {
+	if session == nil {
+		return ctx
+	}
+	if session.LastInput != nil {
+		ctx = withInputPseudonyms(ctx, session.LastInput)
+	}
+	var said []string
+	for _, turn := range session.RecentTurns(maxHistoryTurns) {
+		if turn.Role == sessions.RoleUser {
+			said = append(said, turn.Text)
+		}
+	}
+	return withPseudonyms(ctx, nil, said...)
+}
+
+// conversationPromptInput returns the interpret prompt's previous/history input for a session
+func conversationPromptInput(session *sessions.Session) map[string]any {
+	if session == nil || session.LastInput == nil {
+		return map[string]any{}
+	}
+
+	previous := map[string]any{
+		"occasion": session.LastInput.Occasion,
+		"language": session.LastInput.Language,
+		"length":   session.LastInput.Length,
+		"tone":     session.LastInput.Tone,
+	}
+	withPersonalization(previous, session.LastInput)
+
+	turns := session.RecentTurns(maxHistoryTurns)
+	history := make([]map[string]any, len(turns))
+	for i, turn := range turns {
+		history[i] = map[string]any{"speaker": turn.Role, "text": turn.Text}
+	}
+
+	return map[string]any{
+		"previous": previous,
+		"history":  history,
+	}
+}
@@ -1,16 +1,232 @@
 package flows
 
-// ValidTones defines the acceptable tone options
-var ValidTones = map[string]string{
-	"formal":       "Professional and respectful, suitable for business or formal events",
-	"casual":       "Friendly and relaxed, perfect for informal gatherings",
-	"warm":         "Heartfelt and affectionate, emphasizing emotional connection",
-	"humorous":     "Light-hearted and fun, with tasteful humor",
-	"professional": "Business-appropriate while remaining welcoming",
-	"poetic":       "Elegant and lyrical, with beautiful imagery and flow",
-	"sarcastic":    "Dry, mocking tone with subtle digs",
-	"insulting":    "Bluntly offensive or rude remarks",
-	"aggressive":   "Confrontational wording with sharp jabs",
-	"passive":      "Passive-aggressive with backhanded welcomes",
-	"gloomy":       "Negative, bleak outlook that undercuts the welcome",
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/tones"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Most tones a tone mix may blend
+const maxToneMix = 4
+
+var toneStore tones.Store = mustTones(tones.NewMemoryStore(tones.Defaults()))
+
+func mustTones(s *tones.MemoryStore, err error) tones.Store {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Optional check applied to every resolved tone; see SetTonePolicy
+var tonePolicy func(tones.Tone) error
+
+// SetTonePolicy restricts the tones flows accept. check returns an error for tones the
+// deployment doesn't allow; structured flows fail with it, the Smart flow falls back to the
+// default tone and only offers allowed tones to the interpret prompt.
+// It must be called at startup, before any flow runs.
+func SetTonePolicy(check func(tones.Tone) error) {
+	tonePolicy = check
+}
+
+// SetToneStore sets the tone registry flows accept. It must be called at startup, before any flow runs.
+func SetToneStore(s tones.Store) {
+	toneStore = s
+}
+
+// ToneStore returns the tone registry flows accept
+func ToneStore() tones.Store {
+	return toneStore
+}
+
+// ValidateTone reports whether a request's tone, or every tone of its tone mix, is in the registry
+func ValidateTone(ctx context.Context, input *types.WelcomeNoteInput) error {
+	in := *input
+	_, err := normalizeInputTone(ctx, &in)
+	return err
+}
+
+// normalizeInputTone resolves the input's tone mix, when it has one, and its tone.
+// A mix takes precedence: Tone becomes its heaviest tone and ToneMix the normalized
+// weights. It returns the blend, nil without a mix.
+func normalizeInputTone(ctx context.Context, input *types.WelcomeNoteInput) ([]types.ToneWeight, error) {
+	blend, err := normalizeToneMix(ctx, input.ToneMix)
+	if err != nil {
+		return nil, err
+	}
+	if len(blend) > 0 {
+		input.Tone = blend[0].Tone
+		input.ToneMix = make(map[string]float64, len(blend))
+		for _, w := range blend {
+			input.ToneMix[w.Tone] = w.Weight
+		}
+	}
+
+	tone, err := normalizeTone(ctx, input.Tone)
+	if err != nil {
+		return nil, err
+	}
+	input.Tone = tone.Name
+	return blend, nil
+}
+
+// normalizeToneMix resolves each tone of a mix against the registry and the tone policy,
+// merges aliases of the same tone and scales the weights to sum to 1, heaviest first
+func normalizeToneMix(ctx context.Context, mix map[string]float64) ([]types.ToneWeight, error) {
+	if len(mix) == 0 {
+		return nil, nil
+	}
+
+	weights := map[string]float64{}
+	total := 0.0
+	for name, weight := range mix {
+		if tones.NormalizeName(name) == "" {
+			return nil, fmt.Errorf("normalizing tone mix: %w: empty tone name", tones.ErrInvalid)
+		}
+		if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, fmt.Errorf("normalizing tone mix: %w: weight of %q must be a positive number", tones.ErrInvalid, name)
+		}
+		t, err := normalizeTone(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		weights[t.Name] += weight
+		total += weight
+	}
+	if len(weights) > maxToneMix {
+		return nil, fmt.Errorf("normalizing tone mix: %w: at most %d tones can be blended", tones.ErrInvalid, maxToneMix)
+	}
+
+	blend := make([]types.ToneWeight, 0, len(weights))
+	for name, weight := range weights {
+		blend = append(blend, types.ToneWeight{Tone: name, Weight: math.Round(weight/total*100) / 100})
+	}
+	sort.Slice(blend, func(i, j int) bool {
+		if blend[i].Weight != blend[j].Weight {
+			return blend[i].Weight > blend[j].Weight
+		}
+		return blend[i].Tone < blend[j].Tone
+	})
+	return blend, nil
+}
+
+// normalizeTone resolves a tone name or alias against the registry; empty means the default tone
+func normalizeTone(ctx context.Context, tone string) (tones.Tone, error) {
+	if tones.NormalizeName(tone) == "" {
+		tone = tones.DefaultTone
+	}
+	t, err := tones.Resolve(ctx, toneStore, tone)
+	if err != nil {
+		return tones.Tone{}, fmt.Errorf("normalizing tone: %w", err)
+	}
+	if tonePolicy != nil {
+		if err := tonePolicy(t); err != nil {
+			return tones.Tone{}, err
+		}
+	}
+	return t, nil
+}
+
+// AllowedTones lists the registry's tones that pass the tone policy
+func AllowedTones(ctx context.Context) ([]tones.Tone, error) {
+	all, err := toneStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tones: %w", err)
+	}
+	if tonePolicy == nil {
+		return all, nil
+	}
+	allowed := all[:0]
+	for _, t := range all {
+		if tonePolicy(t) == nil {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed, nil
+}
+
+// normalizeToneOrDefault is normalizeTone for model-inferred tones,
+// where a tone missing from the registry falls back to the default
+func normalizeToneOrDefault(ctx context.Context, tone string) tones.Tone {
+	t, err := normalizeTone(ctx, tone)
+	if err != nil {
+		return tones.Tone{Name: tones.DefaultTone}
+	}
+	return t
+}
+
+// withTone adds the registry's description, examples and locale guidance
+// for the input's tone to prompt variables. A tone mix of more than one tone
+// replaces them with each tone's share, description and guidance.
+func withTone(ctx context.Context, vars map[string]any, input *types.WelcomeNoteInput) map[string]any {
+	if blend, err := normalizeToneMix(ctx, input.ToneMix); err == nil && len(blend) > 1 {
+		return withToneMix(ctx, vars, blend, input.Locale)
+	}
+
+	t, err := normalizeTone(ctx, input.Tone)
+	if err != nil {
+		return vars
+	}
+	vars["toneDescription"] = t.Description
+	if len(t.Examples) > 0 {
+		vars["toneExamples"] = t.Examples
+	}
+	if g := t.GuidanceFor(input.Locale); g != "" {
+		vars["toneGuidance"] = g
+	}
+	return vars
+}
+
+// tonePromptList lists the registry's tones for the interpret prompt
+func tonePromptList(ctx context.Context) ([]map[string]any, error) {
+	all, err := AllowedTones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]map[string]any, len(all))
+	for i, t := range all {
+		list[i] = map[string]any{"name": t.Name, "description": t.Description}
+	}
+	return list, nil
+}
+
+// withToneMix adds a blend of tones to prompt variables; "tone" becomes a label such as
+// "70% professional, 30% humorous"
+func withToneMix(ctx context.Context, vars map[string]any, blend []types.ToneWeight, locale string) map[string]any {
+	mix := make([]map[string]any, 0, len(blend))
+	for _, w := range blend {
+		t, err := normalizeTone(ctx, w.Tone)
+		if err != nil {
+			continue
+		}
+		entry := map[string]any{"name": t.Name, "percent": int(math.Round(w.Weight * 100)), "description": t.Description}
+		if g := t.GuidanceFor(locale); g != "" {
+			entry["guidance"] = g
+		}
+		mix = append(mix, entry)
+	}
+	vars["tone"] = toneMixLabel(blend)
+	vars["toneMix"] = mix
+	return vars
+}
+
+// toneLabel names the input's tone, or its blend when it has a tone mix of more than one tone
+func toneLabel(ctx context.Context, input *types.WelcomeNoteInput) string {
+	if blend, err := normalizeToneMix(ctx, input.ToneMix); err == nil && len(blend) > 1 {
+		return toneMixLabel(blend)
+	}
+	return input.Tone
+}
+
+func toneMixLabel(blend []types.ToneWeight) string {
+	labels := make([]string, len(blend))
+	for i, w := range blend {
+		labels[i] = fmt.Sprintf("%d%% %s", int(math.Round(w.Weight*100)), w.Tone)
+	}
+	return strings.Join(labels, ", ")
 }
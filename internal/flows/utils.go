@@ -1,6 +1,7 @@
 package flows
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/firebase/genkit/go/core/api"
@@ -16,21 +17,6 @@ func normalizeLength(length string) string {
 	return "short"
 }
 
-func normalizeLanguage(language string) string {
-	if language == "" {
-		return "english"
-	}
-	return language
-}
-
-func normalizeTone(tone string) string {
-	tone = strings.ToLower(strings.TrimSpace(tone))
-	if _, exists := ValidTones[tone]; exists {
-		return tone
-	}
-	return "warm" // default tone
-}
-
 func lookupFlow(g *genkit.Genkit, flowName string) api.Action {
 	for _, flow := range genkit.ListFlows(g) {
 		if flow.Name() == flowName {
@@ -39,3 +25,64 @@ func lookupFlow(g *genkit.Genkit, flowName string) api.Action {
 	}
 	return nil
 }
+
+// partialJSONString extracts the value of a top-level string field from a JSON
+// document that may still be incomplete, as seen while a model is streaming.
+// It returns the decoded prefix received so far, or "" if the field hasn't started.
+func partialJSONString(raw, key string) string {
+	idx := strings.Index(raw, `"`+key+`"`)
+	if idx < 0 {
+		return ""
+	}
+	rest := strings.TrimLeft(raw[idx+len(key)+2:], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return ""
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, `"`) {
+		return ""
+	}
+	rest = rest[1:]
+
+	var sb strings.Builder
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c == '"' {
+			break
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		// escape sequence; stop if it hasn't fully arrived yet
+		if i+1 >= len(rest) {
+			break
+		}
+		if rest[i+1] == 'u' {
+			if i+6 > len(rest) {
+				break
+			}
+			r, err := strconv.Unquote(`"` + rest[i:i+6] + `"`)
+			if err != nil {
+				break
+			}
+			sb.WriteString(r)
+			i += 5
+			continue
+		}
+		switch rest[i+1] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'b', 'f':
+			// ignore control characters
+		default:
+			sb.WriteByte(rest[i+1])
+		}
+		i++
+	}
+	return sb.String()
+}
@@ -0,0 +1,147 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// refinement is the refine prompt's structured output
+type refinement struct {
+	Note    string `json:"note" jsonschema:"description=the full revised welcome note"`
+	Changes string `json:"changes" jsonschema:"description=brief summary of what was changed"`
+}
+
+func RegisterWelcomeNoteFlowRefine(g *genkit.Genkit, name string) {
+
+	f := genkit.DefineStreamingFlow(g, name, func(ctx context.Context, input *types.RefineInput, cb core.StreamCallback[*types.PipelineProgress]) (*types.RefineOutput, error) {
+		// 1) Screen the instruction, the note and its original input
+		original := &input.Input
+		ctx, err := WithModel(ctx, original.Model)
+		if err != nil {
+			return nil, err
+		}
+		normalizePersonalization(original)
+		ctx = withPseudonyms(withInputPseudonyms(ctx, original), nil, input.Instruction, input.Note)
+		ctx, verdict, err := guardInput(ctx, g, append([]inputField{
+			{"instruction", &input.Instruction},
+			{"note", &input.Note},
+		}, welcomeNoteFields(original)...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		// 2) Revise the note following the instruction
+		revised, err := runStep(ctx, "refine_note", cb, func() (*refinement, error) {
+			return refineWelcomeNote(ctx, g, input)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// 3) Moderate the revision, same as the Safe flow; a blocked or held one isn't returned
+		moderated, err := runStep(ctx, "moderate_and_sanitize", cb, func() (*types.ModerationResult, error) {
+			return moderateWelcomeNote(ctx, g, revised.Note, &input.Input)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		safe := safeOutput(&types.WelcomeNoteV3Output{
+			Note:     revised.Note,
+			Occasion: original.Occasion,
+			Language: original.Language,
+			Length:   original.Length,
+			Tone:     original.Tone,
+		}, moderated)
+		if safe.Blocked {
+			safe = withheld(safe)
+		}
+
+		// 4) Hold a revision that needs a person to check it, same as the Safe flow
+		if needsReview(safe) {
+			safe, err = runStep(ctx, "queue_for_review", cb, func() (*types.SafeWelcomeNoteOutput, error) {
+				return queueForReview(ctx, safe)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		out := &types.RefineOutput{
+			SafeWelcomeNoteOutput: safe,
+			PreviousNote:          input.Note,
+			Instruction:           input.Instruction,
+		}
+		if safe.Note != "" {
+			out.Changes = revised.Changes
+			out.Diff = diffWords(input.Note, safe.Note)
+		}
+
+		var versions types.WelcomeNoteV3Metadata
+		recordPromptVersion(&versions, promptRefine)
+		recordPromptVersion(&versions, promptModeration)
+		if verdict != nil && verdict.Classifier != nil {
+			recordPromptVersion(&versions, promptInputGuard)
+		}
+		out.PromptVersions = versions.PromptVersions
+		out.InputGuard = verdict
+		out.Pseudonymized = pseudonymCounts(ctx)
+
+		return out, nil
+	})
+
+	SetFlow(name, f)
+}
+
+func refineWelcomeNote(ctx context.Context, g *genkit.Genkit, input *types.RefineInput) (*refinement, error) {
+	input.Note = strings.TrimSpace(input.Note)
+	input.Instruction = strings.TrimSpace(input.Instruction)
+	if input.Note == "" || input.Instruction == "" {
+		return nil, fmt.Errorf("refining welcome note: note and instruction are required")
+	}
+
+	// the original input is optional context; only normalize what was given
+	original := &input.Input
+	normalizePersonalization(original)
+	vars := map[string]any{
+		"note":        input.Note,
+		"instruction": input.Instruction,
+		"occasion":    strings.TrimSpace(original.Occasion),
+	}
+	if original.Language != "" || original.Locale != "" {
+		if _, err := normalizeLocale(original); err != nil {
+			return nil, fmt.Errorf("refining welcome note: %w", err)
+		}
+		withLocale(vars, original)
+		vars["language"] = original.Language
+	}
+	if original.Length != "" {
+		vars["length"] = normalizeLength(original.Length)
+	}
+	if original.Tone != "" || len(original.ToneMix) > 0 {
+		if _, err := normalizeInputTone(ctx, original); err != nil {
+			return nil, fmt.Errorf("refining welcome note: %w", err)
+		}
+		vars["tone"] = original.Tone
+		withTone(ctx, vars, original)
+	}
+
+	rendered, err := renderPrompt(promptRefine, withPersonalization(vars, original))
+	if err != nil {
+		return nil, fmt.Errorf("refining welcome note: %w", err)
+	}
+
+	result, _, err := genkit.GenerateData[refinement](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return nil, fmt.Errorf("refining welcome note: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, fmt.Errorf("refining welcome note: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package flows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestRefineFlowModeration(t *testing.T) {
+	const previous, revised = "Welcome to the team, Sam!", "Welcome to the team, Sam! See you at lunch, slowpoke."
+	holdRude := &moderation.Policy{Name: "test", Categories: map[string]moderation.Thresholds{types.CategoryToxicity: {Review: 0.3}}}
+	tests := []struct {
+		name     string
+		verdict  types.ModerationResult
+		policy   *moderation.Policy
+		decision string
+		note     string // the released note; none means no diff either
+	}{
+		{"allowed", types.ModerationResult{ModerationNote: "ok"}, nil, moderation.DecisionAllow, revised},
+		{"sanitized", types.ModerationResult{SanitizedNote: "Welcome to the team, Sam! See you at lunch.", ModerationNote: "removed a jab"}, nil, moderation.DecisionSanitize, "Welcome to the team, Sam! See you at lunch."},
+		{"blocked", types.ModerationResult{Blocked: true, ModerationNote: "insult"}, nil, moderation.DecisionBlock, ""},
+		{"held for review", types.ModerationResult{ModerationNote: "teasing", Categories: scores(map[string]float64{types.CategoryToxicity: 0.4})}, holdRude, moderation.DecisionReview, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := tt.verdict
+			if verdict.Categories == nil {
+				verdict.Categories = scores(nil)
+			}
+			g := newStubGenkit(t, &stubModerator{revision: revised, verdicts: map[string]types.ModerationResult{revised: verdict}})
+			queue := review.NewMemoryStore(nil)
+			SetReviewQueue(queue)
+			SetModerationPolicy(tt.policy)
+			t.Cleanup(func() {
+				SetReviewQueue(nil)
+				SetModerationPolicy(nil)
+			})
+			RegisterWelcomeNoteFlowRefine(g, "welcomeNoteFlowRefine")
+			val, _ := GetFlow("welcomeNoteFlowRefine")
+			flow := val.(*core.Flow[*types.RefineInput, *types.RefineOutput, *types.PipelineProgress])
+
+			got, err := flow.Run(context.Background(), &types.RefineInput{Note: previous, Instruction: "mention the lunch"})
+			if err != nil {
+				t.Fatalf("refine = %v", err)
+			}
+			if got.Decision != tt.decision {
+				t.Errorf("decision = %q, want %q (%s)", got.Decision, tt.decision, got.DecisionReason)
+			}
+			if got.Note != tt.note {
+				t.Errorf("note = %q, want %q", got.Note, tt.note)
+			}
+			if (got.Diff != nil) != (tt.note != "") {
+				t.Errorf("diff = %v, want one only with a note", got.Diff)
+			}
+			if len(got.Categories) != len(types.ModerationCategories) {
+				t.Errorf("categories = %v, want the Safe flow's moderation report", got.Categories)
+			}
+			held, _ := queue.List(context.Background(), review.StatusPending)
+			if queued := tt.decision == moderation.DecisionReview; queued != (got.ReviewID != "") || queued != (len(held) == 1) {
+				t.Errorf("review ID %q with %d items queued, want the revision queued only when held", got.ReviewID, len(held))
+			}
+		})
+	}
+}
@@ -1,68 +1,220 @@
 package flows
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
-	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
 	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
 )
 
 func RegisterWelcomeNoteFlowSafe(g *genkit.Genkit, name string) {
+	approvalTool := defineApprovalGate(g)
 
-	f := genkit.DefineFlow(g, name, func(ctx context.Context, input *types.WelcomeNoteInput) (*types.SafeWelcomeNoteOutput, error) {
-		// 1) Run base generator (V3)
-		base, err := genkit.Run(ctx, "run_base_flow", func() (*types.WelcomeNoteV3Output, error) {
-			return generateWelcomeNote3(ctx, g, input)
-		})
+	f := genkit.DefineStreamingFlow(g, name, func(ctx context.Context, input *types.WelcomeNoteInput, cb core.StreamCallback[*types.PipelineProgress]) (*types.SafeWelcomeNoteOutput, error) {
+		ctx, err := WithModel(ctx, input.Model)
 		if err != nil {
 			return nil, err
 		}
+		ctx = withInputPseudonyms(ctx, input)
 
-		// 2) Run moderation on the generated note
-		moderated, err := genkit.Run(ctx, "moderate_and_sanitize", func() (*types.ModerationResult, error) {
-			return moderateWelcomeNote(ctx, g, base.Note)
+		// 1) Run base generator (V3)
+		base, err := runStep(ctx, "run_base_flow", cb, func() (*types.WelcomeNoteV3Output, error) {
+			return generateWelcomeNote3(ctx, g, input)
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		finalNote := base.Note
-		originalNote := ""
-
-		// Only treat as sanitized if it's non-empty and different from original
-		if moderated != nil && moderated.SanitizedNote != "" && moderated.SanitizedNote != base.Note {
-			originalNote = base.Note
-			finalNote = moderated.SanitizedNote
+		// 2) Run moderation on the generated note, or on every candidate
+		var out *types.SafeWelcomeNoteOutput
+		if len(base.Candidates) > 0 {
+			candidates, err := runStep(ctx, "moderate_and_sanitize", cb, func() ([]types.WelcomeNoteCandidate, error) {
+				return moderateCandidates(ctx, g, base.Candidates, input)
+			})
+			if err != nil {
+				return nil, err
+			}
+			out = safeOutputFromCandidates(base, candidates)
+		} else {
+			moderated, err := runStep(ctx, "moderate_and_sanitize", cb, func() (*types.ModerationResult, error) {
+				return moderateWelcomeNote(ctx, g, base.Note, input)
+			})
+			if err != nil {
+				return nil, err
+			}
+			out = safeOutput(base, moderated)
 		}
 
-		// 3) Build safe output (V3 + safety)
-		out := &types.SafeWelcomeNoteOutput{
-			Note:     finalNote,
-			Occasion: base.Occasion,
-			Language: base.Language,
-			Length:   base.Length,
-			Tone:     base.Tone,
-			Metadata: base.Metadata, // if you keep the V3 metadata
-
-			Blocked:        moderated != nil && moderated.Blocked,
-			ModerationNote: "",
-			OriginalNote:   originalNote,
+		// 3) Regenerate a blocked note with the verdict as a constraint; only blocked
+		// candidates means the best one is blocked too
+		if out.Blocked && remediationRetries > 0 {
+			out, err = runStep(ctx, "remediate_blocked_note", cb, func() (*types.SafeWelcomeNoteOutput, error) {
+				return remediateBlockedNote(ctx, g, input, out)
+			})
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		if moderated != nil && moderated.ModerationNote != "" {
-			out.ModerationNote = moderated.ModerationNote
+		// 4) In approval mode pause until an approver decides; otherwise hold a note that
+		// needs a person to check it in the review queue
+		switch {
+		case approvalMode && !out.Blocked:
+			return runStep(ctx, "await_approval", cb, func() (*types.SafeWelcomeNoteOutput, error) {
+				return awaitApproval(ctx, g, approvalTool, input, out)
+			})
+		case needsReview(out):
+			return runStep(ctx, "queue_for_review", cb, func() (*types.SafeWelcomeNoteOutput, error) {
+				return queueForReview(ctx, out)
+			})
 		}
-
 		return out, nil
 	})
 
 	SetFlow(name, f)
 }
 
-func moderateWelcomeNote(ctx context.Context, g *genkit.Genkit, note string) (*types.ModerationResult, error) {
+// safeOutput builds the Safe output from a generated note and its moderation result
+func safeOutput(base *types.WelcomeNoteV3Output, moderated *types.ModerationResult) *types.SafeWelcomeNoteOutput {
+	finalNote := base.Note
+	originalNote := ""
+
+	// Only treat as sanitized if it's non-empty and different from original
+	if moderated != nil && moderated.SanitizedNote != "" && moderated.SanitizedNote != base.Note {
+		originalNote = base.Note
+		finalNote = moderated.SanitizedNote
+	}
+
+	// Build safe output (V3 + safety)
+	out := &types.SafeWelcomeNoteOutput{
+		Note:     finalNote,
+		Occasion: base.Occasion,
+		Language: base.Language,
+		Length:   base.Length,
+		Tone:     base.Tone,
+		Metadata: base.Metadata, // if you keep the V3 metadata
+
+		Blocked:        moderated != nil && moderated.Blocked,
+		ModerationNote: "",
+		OriginalNote:   originalNote,
+	}
+
+	if moderated != nil {
+		out.ModerationNote = moderated.ModerationNote
+		out.Categories = moderated.Categories
+		out.ChangedSpans = moderated.ChangedSpans
+		out.Decision = moderated.Decision
+		out.DecisionReason = moderated.DecisionReason
+		out.Votes = moderated.Votes
+		out.Pivot = moderated.Pivot
+		recordPivotPromptVersions(&out.Metadata, moderated.Pivot)
+	}
+	recordPromptVersion(&out.Metadata, promptModeration)
+	return out
+}
+
+// withheld returns a copy of out without the text of the note, its sanitized parts,
+// its translation or the other candidates
+func withheld(out *types.SafeWelcomeNoteOutput) *types.SafeWelcomeNoteOutput {
+	w := *out
+	w.Note = ""
+	w.OriginalNote = ""
+	w.ChangedSpans = nil
+	w.Candidates = nil
+	w.Pivot = nil
+	return &w
+}
+
+// moderateCandidates moderates all candidates concurrently and returns them with the
+// sanitized notes applied. Candidates held for review drop below the others, and blocked
+// ones below those; order is kept otherwise.
+func moderateCandidates(ctx context.Context, g *genkit.Genkit, candidates []types.WelcomeNoteCandidate, input *types.WelcomeNoteInput) ([]types.WelcomeNoteCandidate, error) {
+	results := make([]*types.ModerationResult, len(candidates))
+	errs := make([]error, len(candidates))
+	var wg sync.WaitGroup
+	for i := range candidates {
+		wg.Go(func() {
+			results[i], errs[i] = moderateWelcomeNote(ctx, g, candidates[i].Note, input)
+		})
+	}
+	wg.Wait()
+
+	moderated := make([]types.WelcomeNoteCandidate, 0, len(candidates))
+	var review, blocked []types.WelcomeNoteCandidate
+	for i, c := range candidates {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("candidate %d: %w", c.Rank, errs[i])
+		}
+		result := results[i]
+		if result.SanitizedNote != "" && result.SanitizedNote != c.Note {
+			c.OriginalNote = c.Note
+			c.Note = result.SanitizedNote
+		}
+		c.Blocked = result.Blocked
+		c.ModerationNote = result.ModerationNote
+		c.Categories = result.Categories
+		c.ChangedSpans = result.ChangedSpans
+		c.Decision = result.Decision
+		c.DecisionReason = result.DecisionReason
+		c.Votes = result.Votes
+		c.Pivot = result.Pivot
+		recordPromptVersion(&c.Metadata, promptModeration)
+		recordPivotPromptVersions(&c.Metadata, result.Pivot)
+
+		switch {
+		case c.Blocked:
+			blocked = append(blocked, c)
+		case c.Decision == moderation.DecisionReview:
+			review = append(review, c)
+		default:
+			moderated = append(moderated, c)
+		}
+	}
+
+	moderated = append(append(moderated, review...), blocked...)
+	for i := range moderated {
+		moderated[i].Rank = i + 1
+	}
+	return moderated, nil
+}
+
+// safeOutputFromCandidates builds the Safe output from moderated candidates, using the best one
+func safeOutputFromCandidates(base *types.WelcomeNoteV3Output, candidates []types.WelcomeNoteCandidate) *types.SafeWelcomeNoteOutput {
+	best := candidates[0]
+	return &types.SafeWelcomeNoteOutput{
+		Note:     best.Note,
+		Occasion: base.Occasion,
+		Language: base.Language,
+		Length:   base.Length,
+		Tone:     base.Tone,
+		Metadata: best.Metadata,
+
+		Blocked:        best.Blocked,
+		ModerationNote: best.ModerationNote,
+		OriginalNote:   best.OriginalNote,
+		Categories:     best.Categories,
+		ChangedSpans:   best.ChangedSpans,
+		Decision:       best.Decision,
+		DecisionReason: best.DecisionReason,
+		Votes:          best.Votes,
+		Pivot:          best.Pivot,
+
+		Candidates: candidates,
+	}
+}
+
+// moderateWelcomeNote moderates a note written for input. The local rules run first:
+// a blocking match skips the LLM moderator, unless the rules are one vote in a moderation
+// ensemble, and sanitized matches, PII in particular, are masked before the note reaches
+// any model. A note in another language is also moderated in an English translation.
+// Any decision but allow is written to the audit log. input may be nil when it isn't known.
+func moderateWelcomeNote(ctx context.Context, g *genkit.Genkit, note string, input *types.WelcomeNoteInput) (*types.ModerationResult, error) {
 	if strings.TrimSpace(note) == "" {
 		return &types.ModerationResult{
 			SanitizedNote:  note,
@@ -71,41 +223,33 @@ func moderateWelcomeNote(ctx context.Context, g *genkit.Genkit, note string) (*t
 		}, nil
 	}
 
-	systemPrompt := `You are a content safety filter that removes toxicity, hate speech, personal attacks,
-sexual content, self-harm encouragement, or personally identifiable information.
-When issues are found, either redact them or replace them with neutral language
-appropriate for a friendly welcome note.
-`
-
-	userPrompt := fmt.Sprintf(`
-Review the following welcome note for safety issues and return a JSON object.
-
-Rules:
-- "sanitizedNote": a safe version of the note with unsafe content removed or rewritten.
-  - If the note is already safe, return it unchanged.
-  - If only parts are unsafe, rewrite only those parts.
-  - If the note is fully blocked, this should be an empty string.
-- "blocked": a boolean.
-  - Use true only if the content is extremely unsafe and cannot be rewritten safely.
-  - Otherwise false.
-- "moderationNote": a brief explanation of what was changed or why blocking occurred.
-  - Example: "removed insult", "redacted private info", "no issues found".
-
-Output format:
-- Respond with a single JSON object only.
-- Use exactly these keys: sanitizedNote (string), blocked (boolean), moderationNote (string).
-- Do not include any other fields or text.
-
-Welcome note to review:
-%s
-`, note)
-
-	result, _, err := genkit.GenerateData[types.ModerationResult](ctx, g,
-		ai.WithSystem(systemPrompt),
-		ai.WithPrompt(userPrompt),
-	)
+	rules := checkModerationRules(note, input)
+	ruleVotes := moderationEnsemble.Has(moderation.ModeratorRules)
+	if rules.Blocked && !ruleVotes {
+		result := &types.ModerationResult{
+			Blocked:        true,
+			ModerationNote: "blocked by moderation rules: " + moderation.Summary(rules.Findings),
+			Categories:     moderationCategories(nil, rules.Findings),
+			RuleFindings:   rules.Findings,
+			Decision:       moderation.DecisionBlock,
+			DecisionReason: "moderation rules: " + moderation.Summary(rules.Findings),
+		}
+		recordModeration(ctx, note, input, result)
+		return result, nil
+	}
+
+	pivot, err := translatePivot(ctx, g, rules.Sanitized, noteLanguage(note, input))
 	if err != nil {
-		return nil, fmt.Errorf("moderating welcome note: %w", err)
+		return nil, err
+	}
+	var result *types.ModerationResult
+	if moderationEnsemble != nil {
+		result, err = runModerationEnsemble(ctx, g, rules, pivot)
+	} else {
+		result, err = moderateWithPivot(ctx, g, rules.Sanitized, pivot, nil)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	/*
@@ -114,5 +258,65 @@ Welcome note to review:
 		}
 	*/
 
+	// merge the rule findings; the LLM saw the rule-sanitized note, so fall back to it
+	if len(rules.Findings) > 0 {
+		result.RuleFindings = rules.Findings
+		if result.SanitizedNote == "" && !result.Blocked {
+			result.SanitizedNote = rules.Sanitized
+		}
+		result.ModerationNote = strings.TrimSuffix("masked by moderation rules: "+moderation.Summary(rules.Findings)+"; "+result.ModerationNote, "; ")
+	}
+	// a voting rule engine has already scored the note, if its side won
+	if !ruleVotes {
+		result.Categories = moderationCategories(result.Categories, rules.Findings)
+	}
+
+	switch {
+	case moderationEnsemble != nil && result.Blocked:
+		// the moderators already applied the policy to their own votes
+		result.Decision = moderation.DecisionBlock
+	case moderationPolicy != nil:
+		if err := applyModerationPolicy(ctx, g, rules, result); err != nil {
+			return nil, err
+		}
+	default:
+		result.Decision, result.DecisionReason = moderation.DecisionAllow, cmp.Or(result.DecisionReason, "moderator's call")
+		switch {
+		case result.Blocked:
+			result.Decision = moderation.DecisionBlock
+		case result.SanitizedNote != "" && result.SanitizedNote != note:
+			result.Decision = moderation.DecisionSanitize
+		}
+	}
+
+	if result.SanitizedNote != "" && result.SanitizedNote != note {
+		result.ChangedSpans = changedSpans(note, result.SanitizedNote)
+	}
+
+	recordModeration(ctx, note, input, result)
+	return result, nil
+}
+
+// runModerationPrompt asks the LLM moderator to score and sanitize a note. mustSanitize
+// lists categories the moderation policy requires rewritten, however mild.
+func runModerationPrompt(ctx context.Context, g *genkit.Genkit, note string, mustSanitize []string) (*types.ModerationResult, error) {
+	vars := map[string]any{
+		"note": note,
+	}
+	if len(mustSanitize) > 0 {
+		vars["mustSanitize"] = mustSanitize
+	}
+	rendered, err := renderPrompt(promptModeration, vars)
+	if err != nil {
+		return nil, fmt.Errorf("moderating welcome note: %w", err)
+	}
+
+	result, _, err := genkit.GenerateData[types.ModerationResult](ctx, g, promptOptions(ctx, rendered)...)
+	if err != nil {
+		return nil, fmt.Errorf("moderating welcome note: %w", err)
+	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, fmt.Errorf("moderating welcome note: %w", err)
+	}
 	return result, nil
 }
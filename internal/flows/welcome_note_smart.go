@@ -4,56 +4,66 @@ import (
 	"context"
 	"strings"
 
-	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/genkit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/sessions"
 	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
 )
 
 func RegisterWelcomeNoteFlowSmart(g *genkit.Genkit, name string) {
 
-	f := genkit.DefineFlow(g, name, func(ctx context.Context, description string) (*types.SmartWelcomeFlowOutput, error) {
-		input, err := genkit.Run(ctx, "interpret_description", func() (*types.WelcomeNoteInput, error) {
-			return interpretPrompt(ctx, g, description)
+	f := genkit.DefineStreamingFlow(g, name, func(ctx context.Context, smartInput *types.SmartInput, cb core.StreamCallback[*types.PipelineProgress]) (*types.SmartWelcomeFlowOutput, error) {
+		description := smartInput.Description
+		ctx, err := WithModel(ctx, smartInput.Model)
+		if err != nil {
+			return nil, err
+		}
+
+		// screen the description; the note generated from it reuses the verdict
+		ctx = withPseudonyms(ctx, nil, description)
+		ctx, _, err = guardInput(ctx, g, inputField{"description", &description})
+		if err != nil {
+			return nil, err
+		}
+
+		// continue the conversation, if sessions are enabled
+		session, err := loadSession(ctx, smartInput.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		ctx = withSessionPseudonyms(ctx, session)
+
+		input, err := runStep(ctx, "interpret_description", cb, func() (*types.WelcomeNoteInput, error) {
+			return interpretPrompt(ctx, g, description, session)
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		base, err := genkit.Run(ctx, "generate_welcome_note_v3", func() (*types.WelcomeNoteV3Output, error) {
+		base, err := runStep(ctx, "generate_welcome_note_v3", cb, func() (*types.WelcomeNoteV3Output, error) {
 			return generateWelcomeNote3(ctx, g, input)
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		moderated, err := genkit.Run(ctx, "moderate_and_sanitize", func() (*types.ModerationResult, error) {
-			return moderateWelcomeNote(ctx, g, base.Note)
+		moderated, err := runStep(ctx, "moderate_and_sanitize", cb, func() (*types.ModerationResult, error) {
+			return moderateWelcomeNote(ctx, g, base.Note, input)
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		finalNote := base.Note
-		originalNote := ""
+		safe := safeOutput(base, moderated)
+		recordPromptVersion(&safe.Metadata, promptInterpret)
 
-		// Only treat as sanitized if it's non-empty and different from original
-		if moderated != nil && moderated.SanitizedNote != "" && moderated.SanitizedNote != base.Note {
-			originalNote = base.Note
-			finalNote = moderated.SanitizedNote
-		}
-
-		safe := &types.SafeWelcomeNoteOutput{
-			Note:         finalNote,
-			Occasion:     base.Occasion,
-			Language:     base.Language,
-			Length:       base.Length,
-			Tone:         base.Tone,
-			Metadata:     base.Metadata,
-			Blocked:      moderated != nil && moderated.Blocked,
-			OriginalNote: originalNote,
-		}
-		if moderated != nil && moderated.ModerationNote != "" {
-			safe.ModerationNote = moderated.ModerationNote
+		if needsReview(safe) {
+			safe, err = runStep(ctx, "queue_for_review", cb, func() (*types.SafeWelcomeNoteOutput, error) {
+				return queueForReview(ctx, safe)
+			})
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// 4) Wrap in Smart output
@@ -62,6 +72,15 @@ func RegisterWelcomeNoteFlowSmart(g *genkit.Genkit, name string) {
 			RawDescription:        description,
 			ParsedInput:           input,
 		}
+
+		if session != nil {
+			out.Amended = session.LastInput != nil
+			if err := recordTurn(ctx, session, description, input, safe); err != nil {
+				return nil, err
+			}
+			out.SessionID = session.ID
+			out.History = session.Turns
+		}
 		return out, nil
 	})
 
@@ -74,58 +93,42 @@ type smartInterpretation struct {
 	Language string `json:"language,omitempty"`
 	Length   string `json:"length,omitempty"`
 	Tone     string `json:"tone,omitempty"`
+
+	Recipients   string `json:"recipients,omitempty"`
+	Sender       string `json:"sender,omitempty"`
+	Relationship string `json:"relationship,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Signature    string `json:"signature,omitempty"`
 }
 
 // interpretPrompt uses an LLM to extract a structured WelcomeNoteInput from free-form text.
+// With a session that has a previous interpretation, the description amends it instead.
 // Falls back to sensible defaults when the model omits fields.
-func interpretPrompt(ctx context.Context, g *genkit.Genkit, description string) (*types.WelcomeNoteInput, error) {
-	systemPrompt := `You are an AI that converts free-form descriptions into structured welcome-note inputs.
-
-Your job is NOT to censor or sanitize the user’s text. Your job is to interpret it accurately,
-including negative, emotional, sarcastic, or humorous intentions. Safety filtering happens later.
-
-Extract the following fields:
-- occasion: What the user is describing (e.g., “welcoming a new hire”, “roasting a bad manager”,
-  “sending a sarcastic message”, “celebrating a promotion”).
-- language: Infer from the text if clearly indicated; otherwise default to "english".
-- tone: Infer from user intent. Valid tones include:
-  warm, formal, casual, humorous, professional, poetic,
-  AND additional tones when implied: sarcastic, roast, angry, frustrated,
-  passive-aggressive, dark-humor, playful, mocking.
-- length: Infer short | medium | long.
-  Defaults:
-    - short = short messages, direct requests, brief sentiments
-    - medium = descriptive messages
-    - long = highly emotional or detailed requests
-
-Guidelines:
-- Do NOT change the user’s meaning.
-- Do NOT soften or “nicify” negative sentiments.
-- If user clearly requests roasting, criticism, mockery, or negativity, reflect that in "tone".
-- If user describes someone negatively (e.g., “my useless boss”), include that in the occasion.
-- Never perform safety moderation. That is handled by another component.
-
-Output:
-Return only a JSON object:
-{
-  "occasion": string,
-  "language": string,
-  "length": string,
-  "tone": string
-}
-`
-
-	userPrompt := "Description: " + description
+func interpretPrompt(ctx context.Context, g *genkit.Genkit, description string, session *sessions.Session) (*types.WelcomeNoteInput, error) {
+	vars := conversationPromptInput(session)
+	vars["description"] = description
+	toneList, err := tonePromptList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vars["tones"] = toneList
+	rendered, err := renderPrompt(promptInterpret, vars)
+	if err != nil {
+		return nil, err
+	}
 
-	result, _, err := genkit.GenerateData[smartInterpretation](ctx, g,
-		ai.WithSystem(systemPrompt),
-		ai.WithPrompt(userPrompt),
-	)
+	result, _, err := genkit.GenerateData[smartInterpretation](ctx, g, promptOptions(ctx, rendered)...)
 	if err != nil {
 		return nil, err
 	}
+	if err := restoreData(ctx, result); err != nil {
+		return nil, err
+	}
 
 	occ := strings.TrimSpace(result.Occasion)
+	if occ == "" && session != nil && session.LastInput != nil {
+		occ = session.LastInput.Occasion
+	}
 	if occ == "" {
 		occ = strings.TrimSpace(description)
 		if occ == "" {
@@ -135,10 +138,19 @@ Return only a JSON object:
 
 	input := &types.WelcomeNoteInput{
 		Occasion: occ,
-		Language: normalizeLanguage(result.Language),
+		Language: result.Language,
 		Length:   normalizeLength(result.Length),
-		Tone:     normalizeTone(result.Tone),
+		Tone:     normalizeToneOrDefault(ctx, result.Tone).Name,
+
+		Recipients:   result.Recipients,
+		Sender:       result.Sender,
+		Relationship: result.Relationship,
+		Organization: result.Organization,
+		Signature:    result.Signature,
 	}
+	normalizePersonalization(input)
+	// the model may infer a language this deployment doesn't support
+	normalizeLocaleOrDefault(input)
 
 	return input, nil
 }
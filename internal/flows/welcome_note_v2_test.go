@@ -0,0 +1,42 @@
+package flows
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestWelcomeNoteFlowV2Retries(t *testing.T) {
+	const (
+		short   = "Welcome to the team, Sam. We are glad you are here. See you on Monday."
+		tooLong = "Welcome to the team, Sam. We are glad you are here. Your desk is ready. " +
+			"Lunch is on us. The team meets at ten. Ask anyone for help. See you on Monday."
+	)
+	m := &countingWriter{calls: map[string]int{}, v2Notes: []string{tooLong, short}}
+	g := newCountingGenkit(t, m)
+	RegisterWelcomeNoteFlowV2(g, "welcomeNoteFlowV2")
+	val, _ := GetFlow("welcomeNoteFlowV2")
+	flow := val.(*core.Flow[*types.WelcomeNoteInput, string, *types.WelcomeNoteChunk])
+
+	got, err := flow.Run(context.Background(), &types.WelcomeNoteInput{
+		Occasion: "first day on the team",
+		Language: "english",
+		Length:   "short",
+		Tone:     "warm",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != short {
+		t.Errorf("note = %q, want the regenerated short note", got)
+	}
+	if len(m.v2Prompts) != 2 {
+		t.Fatalf("generations = %d, want 2", len(m.v2Prompts))
+	}
+	if strings.Contains(m.v2Prompts[0], "Correction:") || !strings.Contains(m.v2Prompts[1], "Correction:") {
+		t.Errorf("only the retry should carry a correction, prompts = %q", m.v2Prompts)
+	}
+}
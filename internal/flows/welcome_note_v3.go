@@ -2,46 +2,93 @@ package flows
 
 import (
 	"context"
-	"fmt"
+	"strings"
 
 	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/genkit"
 	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
 )
 
 func RegisterWelcomeNoteFlowV3(g *genkit.Genkit, name string) {
-	f := genkit.DefineFlow(g, name, func(ctx context.Context, input *types.WelcomeNoteInput) (*types.WelcomeNoteV3Output, error) {
+	f := genkit.DefineStreamingFlow(g, name, func(ctx context.Context, input *types.WelcomeNoteInput, cb core.StreamCallback[*types.WelcomeNoteChunk]) (*types.WelcomeNoteV3Output, error) {
 		// Implement AI logic here
-		return generateWelcomeNote3(ctx, g, input)
+		ctx, err := WithModel(ctx, input.Model)
+		if err != nil {
+			return nil, err
+		}
+		return generateWelcomeNote3Stream(ctx, g, input, cb)
 	})
 
 	SetFlow(name, f)
 }
 
 func generateWelcomeNote3(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput) (*types.WelcomeNoteV3Output, error) {
+	return generateWelcomeNote3Stream(ctx, g, input, nil)
+}
+
+// generateWelcomeNote3Stream is generateWelcomeNote3 with an optional stream callback.
+// The model streams raw JSON, so only the partially decoded "note" field is forwarded.
+// When several candidates are requested nothing is streamed: the note shown first
+// might not be the one that ranks best.
+func generateWelcomeNote3Stream(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, cb core.StreamCallback[*types.WelcomeNoteChunk]) (*types.WelcomeNoteV3Output, error) {
 	input.Length = normalizeLength(input.Length)
-	input.Language = normalizeLanguage(input.Language)
-	input.Tone = normalizeTone(input.Tone)
-
-	// Build the prompt with tone guidance
-	//prompt := buildPromptWithTone(input.Occasion, input.Language, input.Length, input.Tone)
-	systemPrompt := buildSystemPromptV3()
-	prompt := fmt.Sprintf(
-		`Generate the JSON response described in the system prompt using:
-Occasion: %s
-Language: %s
-Length: %s
-Tone: %s`,
-		input.Occasion, input.Language, input.Length, input.Tone,
-	)
-
-	out, _, err := genkit.GenerateData[types.WelcomeNoteV3Output](ctx, g,
-		ai.WithPrompt(prompt),
-		ai.WithSystem(systemPrompt),
-	)
+	normalizePersonalization(input)
+	ctx = withInputPseudonyms(ctx, input)
+	ctx, verdict, err := guardInput(ctx, g, welcomeNoteFields(input)...)
+	if err != nil {
+		return nil, err
+	}
+	locale, err := normalizeLocale(input)
+	if err != nil {
+		return nil, err
+	}
+	blend, err := normalizeInputTone(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// the input is guarded and normalized once; only generation fans out to candidates
+	if input.Candidates > 1 {
+		return generateRankedWelcomeNotes(ctx, g, input, func() (*types.WelcomeNoteV3Output, error) {
+			return generateMeasuredWelcomeNote3(ctx, g, input, locale.Language, blend, verdict, nil)
+		})
+	}
+	return generateMeasuredWelcomeNote3(ctx, g, input, locale.Language, blend, verdict, cb)
+}
+
+// generateMeasuredWelcomeNote3 generates a note from normalized, guarded input,
+// regenerating it until it has the requested length and language, and fills in the
+// metadata found locally
+func generateMeasuredWelcomeNote3(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, language string, blend []types.ToneWeight, verdict *types.InputGuardVerdict, cb core.StreamCallback[*types.WelcomeNoteChunk]) (*types.WelcomeNoteV3Output, error) {
+	var out *types.WelcomeNoteV3Output
+	measured, checked, err := generateChecked(language, input.Length, func(feedback string, attempt int) (string, error) {
+		var err error
+		out, err = generateWelcomeNote3Attempt(ctx, g, input, feedback, attempt, cb)
+		if err != nil {
+			return "", err
+		}
+		return out.Note, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	out.Metadata.Locale = input.Locale
+	out.Metadata.ToneBlend = blend
+	out.Metadata.InputGuard = verdict
+	out.Metadata.Pseudonymized = pseudonymCounts(ctx)
+	if verdict != nil && verdict.Classifier != nil {
+		recordPromptVersion(&out.Metadata, promptInputGuard)
+	}
+	out.Metadata.LanguageCheck = checked
+	if checked.Status != types.LanguageUnverified {
+		out.Metadata.EffectiveLanguage = checked.Detected
+	}
+	out.Metadata.MeasuredLength = measured
+	out.Metadata.EffectiveLength = lengthCategory(measured.Sentences, input.Length)
+
+	// keep only the personalization the note actually reflects
+	out.Metadata.PersonalizationUsed = personalizationUsed(input, out.Note, out.Metadata.PersonalizationUsed)
 
 	// Return structured response with metadata
 	/*
@@ -56,52 +103,95 @@ Tone: %s`,
 	return out, nil
 }
 
-func buildSystemPromptV3() string {
-	sysPrompt := `You are an assistant that writes personalized welcome-style notes using structured inputs
-and returns both the note and metadata as JSON.
-
-Your role is to generate text, not to enforce content safety policies. A separate moderation
-layer will review and, if needed, sanitize your output.
-
-Guidelines:
-- Use the provided "occasion" as the main theme of the welcome note.
-- Write the note in the specified "language".
-- Match the requested "tone" as closely as possible:
-  warm, formal, casual, humorous, professional, poetic (or any other tone provided).
-- Match the requested "length":
-  - short  = about 2–5 sentences
-  - medium = about 5–10 sentences
-  - long   = about 10+ sentences
-- If the occasion is unclear or unusual, still create a reasonable welcome-style message
-  and explain your interpretation in the metadata.
-- Do not invent specific factual details that are not implied by the input.
-- Reflect the sentiment implied by the occasion and tone, even if it is critical,
-  frustrated, or darkly humorous. Do not soften or censor strong language that is clearly
-  implied by the input just to make it more positive. Safety filtering will be handled
-  by another component.
-
-Output format:
-- Respond with a single JSON object only, no extra text, no markdown.
-- Use this exact structure and key names:
-
-{
-  "note": string,                    // the final welcome note
-  "occasion": string,                // the occasion you used when writing the note
-  "language": string,                // the language you actually used
-  "length": string,                  // the length you targeted: short, medium, or long
-  "tone": string,                    // the tone you aimed for
-  "metadata": {
-    "interpretedOccasion": string,   // how you interpreted or normalized the occasion
-    "effectiveLanguage": string,     // the final language actually used
-    "effectiveLength": string,       // the final length category: short, medium, long
-    "effectiveTone": string,         // the final tone you actually wrote in
-    "sentiment": "positive" | "neutral" | "negative",
-    "safety": "safe" | "needs_review",
-    "comments": string               // brief note about any adjustments or concerns
-  }
+// generateWelcomeNote3Attempt makes a single V3 generation. feedback, when set,
+// corrects a previous attempt; streamed chunks restart with each attempt.
+func generateWelcomeNote3Attempt(ctx context.Context, g *genkit.Genkit, input *types.WelcomeNoteInput, feedback string, attempt int, cb core.StreamCallback[*types.WelcomeNoteChunk]) (*types.WelcomeNoteV3Output, error) {
+	vars := withRemediation(ctx, withTone(ctx, withLocale(withPersonalization(map[string]any{
+		"occasion": input.Occasion,
+		"language": input.Language,
+		"length":   input.Length,
+		"tone":     input.Tone,
+	}, input), input), input))
+	if feedback != "" {
+		vars["feedback"] = feedback
+	}
+	rendered, err := renderPrompt(promptWelcomeV3, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := promptOptions(ctx, rendered)
+
+	if cb != nil {
+		var raw strings.Builder
+		sent := ""
+		opts = append(opts, ai.WithStreaming(func(chunkCtx context.Context, chunk *ai.ModelResponseChunk) error {
+			raw.WriteString(chunk.Text())
+			note := restorePartialText(ctx, partialJSONString(raw.String(), "note"))
+			if len(note) <= len(sent) {
+				return nil
+			}
+			delta := note[len(sent):]
+			sent = note
+			return cb(chunkCtx, &types.WelcomeNoteChunk{Delta: delta, Note: note, Attempt: attempt})
+		}))
+	}
+
+	generated, _, err := genkit.GenerateData[welcomeNoteV3Generation](ctx, g, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := restoreData(ctx, generated); err != nil {
+		return nil, err
+	}
+
+	out := generated.output()
+	recordPromptVersion(&out.Metadata, rendered.Name)
+	return out, nil
 }
 
-- Always produce valid JSON (double quotes around keys and strings, no trailing commas).
-`
-	return sysPrompt
+// welcomeNoteV3Generation is the part of a WelcomeNoteV3Output the model writes.
+// Genkit validates flow outputs against their full schema, so the fields filled in
+// locally (candidates, measured length, prompt versions, ...) can't be hidden from the
+// model with jsonschema tags; the model gets this narrower type instead.
+type welcomeNoteV3Generation struct {
+	Note     string                     `json:"note"`
+	Occasion string                     `json:"occasion"`
+	Language string                     `json:"language"`
+	Length   string                     `json:"length"`
+	Tone     string                     `json:"tone"`
+	Metadata welcomeNoteV3ModelMetadata `json:"metadata"`
+}
+
+// welcomeNoteV3ModelMetadata is the part of WelcomeNoteV3Metadata the model reports
+type welcomeNoteV3ModelMetadata struct {
+	InterpretedOccasion string   `json:"interpretedOccasion"`
+	EffectiveLanguage   string   `json:"effectiveLanguage"`
+	EffectiveLength     string   `json:"effectiveLength"`
+	EffectiveTone       string   `json:"effectiveTone"`
+	Sentiment           string   `json:"sentiment"`
+	Safety              string   `json:"safety"`
+	Comments            string   `json:"comments,omitempty"`
+	PersonalizationUsed []string `json:"personalizationUsed,omitempty"`
+}
+
+func (gen *welcomeNoteV3Generation) output() *types.WelcomeNoteV3Output {
+	m := gen.Metadata
+	return &types.WelcomeNoteV3Output{
+		Note:     gen.Note,
+		Occasion: gen.Occasion,
+		Language: gen.Language,
+		Length:   gen.Length,
+		Tone:     gen.Tone,
+		Metadata: types.WelcomeNoteV3Metadata{
+			InterpretedOccasion: m.InterpretedOccasion,
+			EffectiveLanguage:   m.EffectiveLanguage,
+			EffectiveLength:     m.EffectiveLength,
+			EffectiveTone:       m.EffectiveTone,
+			Sentiment:           m.Sentiment,
+			Safety:              m.Safety,
+			Comments:            m.Comments,
+			PersonalizationUsed: m.PersonalizationUsed,
+		},
+	}
 }
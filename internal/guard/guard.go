@@ -0,0 +1,175 @@
+// Package guard screens user text for prompt injection before it is rendered into a
+// prompt: instructions to ignore the prompt, requests for the system prompt, chat role
+// markers and the like. The heuristics cost no tokens; an LLM classifier can be
+// layered on top by the caller through Guard.Classify.
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Modes of a Guard
+const (
+	ModeNeutralize = "neutralize" // strip suspicious text; reject only likely injections
+	ModeReject     = "reject"     // reject any input with a finding
+)
+
+// Scores at which ModeNeutralize rejects, and at which the classifier's verdict rejects
+const (
+	RejectScore           = 0.8
+	ClassifierRejectScore = 0.8
+)
+
+// ErrInvalid is returned by New for an unknown mode
+var ErrInvalid = errors.New("invalid input guard")
+
+// RejectedError is returned for a request the guard refused
+type RejectedError struct {
+	Verdict types.InputGuardVerdict
+}
+
+func (e *RejectedError) Error() string {
+	if len(e.Verdict.Findings) == 0 && e.Verdict.Classifier != nil {
+		return "input rejected: possible prompt injection: " + e.Verdict.Classifier.Reason
+	}
+	return "input rejected: possible prompt injection in " + strings.Join(Fields(e.Verdict), ", ")
+}
+
+// Field is a named piece of user text, such as the occasion
+type Field struct {
+	Name string
+	Text string
+}
+
+// Guard applies the heuristics in one mode. It is safe for concurrent use.
+type Guard struct {
+	mode string
+}
+
+// New returns a Guard in mode, ModeNeutralize when mode is empty
+func New(mode string) (*Guard, error) {
+	switch mode {
+	case "":
+		mode = ModeNeutralize
+	case ModeNeutralize, ModeReject:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q, want %s or %s", ErrInvalid, mode, ModeNeutralize, ModeReject)
+	}
+	return &Guard{mode: mode}, nil
+}
+
+// Mode returns the guard's mode
+func (g *Guard) Mode() string {
+	return g.mode
+}
+
+// Check screens fields and returns the verdict with the fields as they may reach the
+// prompt: unchanged, or with the suspicious text removed when the verdict is neutralize.
+// A field left empty by neutralizing turns the verdict into reject.
+func (g *Guard) Check(fields []Field) (types.InputGuardVerdict, []Field) {
+	verdict := types.InputGuardVerdict{Action: types.GuardAllow}
+	out := make([]Field, len(fields))
+	copy(out, fields)
+
+	for i, f := range fields {
+		found := scan(f)
+		if len(found) == 0 {
+			continue
+		}
+		verdict.Findings = append(verdict.Findings, found...)
+		out[i].Text = neutralize(f.Text)
+		if strings.TrimSpace(f.Text) != "" && out[i].Text == "" {
+			verdict.Action = types.GuardReject
+		}
+	}
+	verdict.Score = score(verdict.Findings)
+
+	switch {
+	case len(verdict.Findings) == 0:
+	case g.mode == ModeReject, verdict.Score >= RejectScore:
+		verdict.Action = types.GuardReject
+	case verdict.Action != types.GuardReject:
+		verdict.Action = types.GuardNeutralize
+	}
+	if verdict.Action != types.GuardNeutralize {
+		copy(out, fields)
+	}
+	return verdict, out
+}
+
+// Classify folds the LLM classifier's verdict into v. A confident injection verdict
+// rejects the request; a weaker one only raises the score, since the classifier can't
+// say which text to remove.
+func (g *Guard) Classify(v *types.InputGuardVerdict, c *types.InjectionClassification) {
+	v.Classifier = c
+	if c == nil || !c.Injection {
+		return
+	}
+	confidence := math.Max(0, math.Min(1, c.Confidence))
+	v.Score = round(1 - (1-v.Score)*(1-confidence))
+	if confidence >= ClassifierRejectScore || (g.mode == ModeReject && confidence >= 0.5) {
+		v.Action = types.GuardReject
+	}
+}
+
+// Fields lists the fields with findings, in order
+func Fields(v types.InputGuardVerdict) []string {
+	var names []string
+	for _, f := range v.Findings {
+		if len(names) == 0 || names[len(names)-1] != f.Field {
+			names = append(names, f.Field)
+		}
+	}
+	return names
+}
+
+// Signals lists the distinct signals found, in order
+func Signals(v types.InputGuardVerdict) []string {
+	seen := map[string]bool{}
+	var signals []string
+	for _, f := range v.Findings {
+		if !seen[f.Signal] {
+			seen[f.Signal] = true
+			signals = append(signals, f.Signal)
+		}
+	}
+	return signals
+}
+
+// score combines the strongest finding of each signal, so several weak signals
+// together count for more than any one of them
+func score(findings []types.InputGuardFinding) float64 {
+	strongest := map[string]float64{}
+	for _, f := range findings {
+		strongest[f.Signal] = math.Max(strongest[f.Signal], f.Weight)
+	}
+	clean := 1.0
+	for _, w := range strongest {
+		clean *= 1 - w
+	}
+	return round(1 - clean)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+var spaces = regexp.MustCompile(`[ \t]{2,}`)
+
+// neutralize removes every signal's matches from text and tidies the whitespace left behind
+func neutralize(text string) string {
+	for _, s := range signals {
+		text = s.pattern.ReplaceAllString(text, " ")
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
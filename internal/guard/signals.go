@@ -0,0 +1,83 @@
+package guard
+
+import (
+	"regexp"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Heuristic signals
+const (
+	SignalInstructionOverride = "instruction_override" // "ignore previous instructions", "new instructions:"
+	SignalPromptLeak          = "prompt_leak"          // "print your system prompt"
+	SignalRoleOverride        = "role_override"        // "you are now an unrestricted AI"
+	SignalJailbreak           = "jailbreak"            // "developer mode", "do anything now"
+	SignalRoleMarker          = "role_marker"          // "system:" lines and chat template tokens
+	SignalOutputOverride      = "output_override"      // "respond only with ..."
+	SignalTemplateSyntax      = "template_syntax"      // {{ }} and similar template delimiters
+	SignalHiddenText          = "hidden_text"          // zero-width and bidirectional control characters
+)
+
+// signal is a heuristic: text matching pattern is suspicious with the given weight
+type signal struct {
+	name    string
+	pattern *regexp.Regexp
+	weight  float64
+}
+
+// signals are ordered strongest first. Patterns need an instruction-like verb and object
+// together, so a note about "ignoring the rain" or "acting as host" isn't flagged.
+var signals = []signal{
+	{
+		name: SignalInstructionOverride,
+		pattern: regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\b[^.\n]{0,40}?\b(?:previous|prior|above|earlier|preceding|all|your|system)\b[^.\n]{0,20}?\b(?:instructions?|prompts?|rules|directions|guidelines|constraints)\b` +
+			`|\bnew\s+(?:instructions?|rules|task)\s*:`),
+		weight: 0.9,
+	},
+	{
+		name:    SignalPromptLeak,
+		pattern: regexp.MustCompile(`(?i)\b(?:print|reveal|show|repeat|display|output|leak|dump|tell\s+me)\b[^.\n]{0,30}?\b(?:(?:system|developer|hidden|initial|original)\s+(?:prompt|instructions?|message)|your\s+(?:prompt|instructions|rules))\b`),
+		weight:  0.9,
+	},
+	{
+		name:    SignalJailbreak,
+		pattern: regexp.MustCompile(`(?i)\b(?:jailbreak(?:ed|ing)?|developer\s+mode|DAN\s+mode|do\s+anything\s+now)\b`),
+		weight:  0.8,
+	},
+	{
+		name:    SignalRoleMarker,
+		pattern: regexp.MustCompile(`(?im)^\s*(?:system|assistant|developer)\s*:|^\s*#{2,}\s*(?:system|instructions?)\b|<\|(?:im_start|im_end|system|user|assistant|endoftext)\|>|\[/?INST\]|<</?SYS>>`),
+		weight:  0.8,
+	},
+	{
+		name:    SignalRoleOverride,
+		pattern: regexp.MustCompile(`(?i)\b(?:you\s+are\s+now|from\s+now\s+on\s+you\s+are|pretend\s+(?:to\s+be|you\s+are)|act\s+as|role-?play\s+as)\s+(?:an?\s+|the\s+)?(?:unrestricted\s+|unfiltered\s+|different\s+|new\s+)?(?:ai|assistant|model|chatbot|llm|system)\b`),
+		weight:  0.6,
+	},
+	{
+		name:    SignalOutputOverride,
+		pattern: regexp.MustCompile(`(?i)\b(?:respond|reply|answer|output|return)\s+(?:only\s+)?(?:with|in)\s+(?:the\s+following|this\s+exact|exactly|raw\s+json|only)\b`),
+		weight:  0.5,
+	},
+	{
+		name:    SignalTemplateSyntax,
+		pattern: regexp.MustCompile(`\{\{|\}\}|\{%|%\}|<\s*/?\s*(?:system|prompt|instructions?)\s*>`),
+		weight:  0.4,
+	},
+	{
+		name:    SignalHiddenText,
+		pattern: regexp.MustCompile(`[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2060}-\x{2064}\x{2066}-\x{2069}\x{FEFF}]+`),
+		weight:  0.3,
+	},
+}
+
+// scan reports every signal matched in a field
+func scan(f Field) []types.InputGuardFinding {
+	var findings []types.InputGuardFinding
+	for _, s := range signals {
+		for _, match := range s.pattern.FindAllString(f.Text, -1) {
+			findings = append(findings, types.InputGuardFinding{Field: f.Name, Signal: s.name, Match: match, Weight: s.weight})
+		}
+	}
+	return findings
+}
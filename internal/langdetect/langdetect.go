@@ -0,0 +1,194 @@
+// Package langdetect identifies the language of a generated note locally, so the
+// language the model reports doesn't have to be trusted. Scripts identify most
+// languages directly; Latin-script languages are told apart by common words and diacritics.
+package langdetect
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Result is the outcome of Detect
+type Result struct {
+	Language   string  // lowercase language name, e.g. "hindi"; empty when inconclusive
+	Confidence float64 // 0–1
+}
+
+// Languages written in a script of their own (for our purposes)
+var scriptLanguages = []struct {
+	script   *unicode.RangeTable
+	language string
+}{
+	{unicode.Devanagari, "hindi"},
+	{unicode.Telugu, "telugu"},
+	{unicode.Tamil, "tamil"},
+	{unicode.Kannada, "kannada"},
+	{unicode.Malayalam, "malayalam"},
+	{unicode.Bengali, "bengali"},
+	{unicode.Gujarati, "gujarati"},
+	{unicode.Gurmukhi, "punjabi"},
+	{unicode.Arabic, "arabic"},
+	{unicode.Hebrew, "hebrew"},
+	{unicode.Greek, "greek"},
+	{unicode.Cyrillic, "russian"},
+	{unicode.Thai, "thai"},
+	{unicode.Hangul, "korean"},
+	{unicode.Hiragana, "japanese"},
+	{unicode.Katakana, "japanese"},
+	{unicode.Han, "chinese"},
+}
+
+// Frequent words that tell Latin-script languages apart in short welcome notes
+var commonWords = map[string][]string{
+	"english":    {"the", "and", "you", "we", "are", "is", "your", "our", "with", "for", "this", "that", "have", "to", "of", "it", "will", "all", "here", "welcome", "from", "so", "very", "glad", "happy", "warm", "be"},
+	"spanish":    {"el", "los", "las", "y", "que", "en", "una", "es", "por", "con", "para", "nos", "tu", "su", "muy", "bienvenido", "bienvenida", "bienvenidos", "estamos", "aquí", "equipo", "del", "al", "se", "lo", "como"},
+	"french":     {"le", "les", "et", "des", "du", "une", "est", "nous", "vous", "votre", "notre", "pour", "avec", "dans", "qui", "sur", "bienvenue", "très", "sommes", "êtes", "ce", "cette", "au", "aux"},
+	"german":     {"der", "die", "das", "und", "ist", "wir", "sie", "ihr", "ihre", "euch", "dich", "du", "mit", "für", "ein", "eine", "zu", "den", "dem", "im", "auf", "nicht", "willkommen", "herzlich", "freuen", "sehr", "uns", "auch"},
+	"portuguese": {"o", "os", "as", "em", "um", "uma", "é", "você", "nós", "seu", "sua", "nosso", "com", "bem-vindo", "bem-vinda", "muito", "equipe", "estamos", "não", "ao", "do", "da"},
+	"italian":    {"il", "gli", "di", "che", "è", "siamo", "sei", "benvenuto", "benvenuta", "con", "per", "nel", "della", "molto", "squadra", "noi", "voi", "tuo", "vostro", "ci"},
+	"dutch":      {"het", "een", "en", "van", "je", "jij", "wij", "zijn", "met", "voor", "welkom", "ons", "onze", "jullie", "heel", "blij", "dat", "niet", "op", "te", "bij"},
+}
+
+// Letters that are strong hints for one language
+var diacriticHints = map[rune]string{
+	'ñ': "spanish", '¿': "spanish", '¡': "spanish",
+	'ß': "german", 'ä': "german", 'ö': "german", 'ü': "german",
+	'ç': "french", 'œ': "french", 'è': "french", 'ê': "french", 'ù': "french", 'î': "french",
+	'ã': "portuguese", 'õ': "portuguese",
+	'ì': "italian", 'ò': "italian",
+}
+
+// Minimum evidence for a Latin-script guess: common words or hints found
+const minLatinEvidence = 3
+
+var wordLanguages = func() map[string][]string {
+	m := map[string][]string{}
+	for language, words := range commonWords {
+		for _, w := range words {
+			m[w] = append(m[w], language)
+		}
+	}
+	return m
+}()
+
+// Detect identifies the language text is written in
+func Detect(text string) Result {
+	scriptCounts := map[string]int{}
+	latin, letters := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, sl := range scriptLanguages {
+			if unicode.Is(sl.script, r) {
+				scriptCounts[sl.language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return Result{}
+	}
+
+	best, bestCount := "", 0
+	for language, count := range scriptCounts {
+		if count > bestCount {
+			best, bestCount = language, count
+		}
+	}
+	// Japanese mixes kana with Han characters, so any kana means Japanese
+	if best == "chinese" && scriptCounts["japanese"] > 0 {
+		best, bestCount = "japanese", bestCount+scriptCounts["japanese"]
+	}
+	if bestCount > latin {
+		return Result{Language: best, Confidence: round(float64(bestCount) / float64(letters))}
+	}
+
+	language, confidence := detectLatin(text)
+	if language == "" {
+		return Result{}
+	}
+	// scale by the share of Latin letters, so mixed-script notes aren't over-confident
+	return Result{Language: language, Confidence: round(confidence * float64(latin) / float64(letters))}
+}
+
+// detectLatin scores Latin-script text by common words and diacritic hints
+func detectLatin(text string) (string, float64) {
+	scores := map[string]float64{}
+	evidence := 0.0
+
+	lower := strings.ToLower(text)
+	for _, r := range lower {
+		if language, ok := diacriticHints[r]; ok {
+			scores[language] += 2
+			evidence++
+		}
+	}
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-' && r != '\''
+	})
+	for _, w := range words {
+		languages := wordLanguages[w]
+		for _, language := range languages {
+			// words shared by several languages count for less
+			scores[language] += 1 / float64(len(languages))
+		}
+		if len(languages) > 0 {
+			evidence++
+		}
+	}
+	if evidence < minLatinEvidence {
+		return "", 0
+	}
+
+	best, bestScore, total := "", 0.0, 0.0
+	for language, score := range scores {
+		total += score
+		if score > bestScore {
+			best, bestScore = language, score
+		}
+	}
+	return best, bestScore / total
+}
+
+// Supported reports whether Detect can identify the language
+func Supported(language string) bool {
+	language = Normalize(language)
+	if _, ok := commonWords[language]; ok {
+		return true
+	}
+	for _, sl := range scriptLanguages {
+		if sl.language == language {
+			return true
+		}
+	}
+	return false
+}
+
+// ISO 639-1 codes of the detectable languages
+var languageCodes = map[string]string{
+	"en": "english", "es": "spanish", "fr": "french", "de": "german", "pt": "portuguese",
+	"it": "italian", "nl": "dutch", "hi": "hindi", "te": "telugu", "ta": "tamil",
+	"kn": "kannada", "ml": "malayalam", "bn": "bengali", "gu": "gujarati", "pa": "punjabi",
+	"ar": "arabic", "he": "hebrew", "el": "greek", "ru": "russian", "th": "thai",
+	"ko": "korean", "ja": "japanese", "zh": "chinese",
+}
+
+// Normalize returns the lowercase language name for a name or ISO 639-1 code
+func Normalize(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if name, ok := languageCodes[language]; ok {
+		return name
+	}
+	return language
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,90 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"no letters", "123 !!! 456", ""},
+		{"english", "Welcome to the team! We are so glad you are here with us.", "english"},
+		{"spanish", "¡Bienvenido al equipo! Estamos muy felices de que estés aquí con nosotros.", "spanish"},
+		{"french", "Bienvenue dans l'équipe ! Nous sommes très heureux de vous avoir avec nous.", "french"},
+		{"german", "Herzlich willkommen im Team! Wir freuen uns sehr, dass du bei uns bist.", "german"},
+		{"portuguese", "Bem-vindo à equipe! Estamos muito felizes com você aqui.", "portuguese"},
+		{"italian", "Benvenuto nella squadra! Siamo molto felici che tu sia qui con noi.", "italian"},
+		{"dutch", "Welkom bij het team! We zijn heel blij dat jij er bent.", "dutch"},
+		{"hindi", "टीम में आपका स्वागत है! हमें बहुत खुशी है।", "hindi"},
+		{"telugu", "మా బృందానికి స్వాగతం!", "telugu"},
+		{"russian", "Добро пожаловать в команду!", "russian"},
+		{"chinese", "欢迎加入我们的团队！", "chinese"},
+		{"japanese mixes kana with han", "チームへようこそ！皆で歓迎します。", "japanese"},
+		{"korean", "팀에 오신 것을 환영합니다!", "korean"},
+		{"too little latin evidence", "Hello Priya", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.text)
+			if got.Language != tt.want {
+				t.Fatalf("Detect(%q) = %+v, want language %q", tt.text, got, tt.want)
+			}
+			if tt.want == "" && got.Confidence != 0 {
+				t.Errorf("Detect(%q) confidence = %v, want 0 when inconclusive", tt.text, got.Confidence)
+			}
+			if tt.want != "" && (got.Confidence <= 0 || got.Confidence > 1) {
+				t.Errorf("Detect(%q) confidence = %v, want in (0, 1]", tt.text, got.Confidence)
+			}
+		})
+	}
+}
+
+func TestDetectMixedScriptConfidence(t *testing.T) {
+	pure := Detect("टीम में आपका स्वागत है")
+	mixed := Detect("टीम में आपका स्वागत है, welcome")
+	if pure.Language != "hindi" || mixed.Language != "hindi" {
+		t.Fatalf("Detect = %+v and %+v, want hindi for both", pure, mixed)
+	}
+	if mixed.Confidence >= pure.Confidence {
+		t.Errorf("mixed-script confidence %v, want below pure %v", mixed.Confidence, pure.Confidence)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"en", "english"},
+		{" HI ", "hindi"},
+		{"Spanish", "spanish"},
+		{"zh", "chinese"},
+		{"klingon", "klingon"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"english", true},
+		{"de", true},
+		{"Telugu", true},
+		{"ja", true},
+		{"klingon", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := Supported(tt.in); got != tt.want {
+			t.Errorf("Supported(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package locales resolves free-form language input ("en", "English", "british english",
+// "pt_BR") to BCP-47 locales and validates it against the deployment's supported list.
+package locales
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// ErrUnsupported is returned for input that doesn't resolve to a supported locale
+var ErrUnsupported = errors.New("unsupported locale")
+
+// DefaultTags are the locales supported when none are configured
+var DefaultTags = []string{
+	"en-US", "en-GB", "es-ES", "es-MX", "fr-FR", "fr-CA", "de-DE",
+	"pt-BR", "pt-PT", "it-IT", "nl-NL", "hi-IN", "te-IN", "ja-JP",
+}
+
+// Locale is a supported BCP-47 locale
+type Locale struct {
+	Tag      string `json:"tag"`      // canonical BCP-47 tag, e.g. "pt-BR"
+	Name     string `json:"name"`     // English name including the regional variant, e.g. "Brazilian Portuguese"
+	Native   string `json:"native"`   // name of the language in itself, e.g. "português"
+	Language string `json:"language"` // lowercase English name of the base language, e.g. "portuguese"
+}
+
+// Registry holds the supported locales
+type Registry struct {
+	locales []Locale
+	tags    []language.Tag
+	matcher language.Matcher
+	aliases map[string]int // lowercase tags and names to index in locales
+	def     int
+}
+
+// New creates a Registry from BCP-47 tags. defaultTag must be one of them;
+// if empty the first tag is the default.
+func New(tags []string, defaultTag string) (*Registry, error) {
+	if len(tags) == 0 {
+		return nil, errors.New("locales: no supported locales")
+	}
+
+	r := &Registry{aliases: map[string]int{}}
+	for _, s := range tags {
+		tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(s), "_", "-"))
+		if err != nil {
+			return nil, fmt.Errorf("locales: parsing %q: %w", s, err)
+		}
+		if r.index(tag.String()) >= 0 {
+			continue
+		}
+		r.tags = append(r.tags, tag)
+		r.locales = append(r.locales, newLocale(tag))
+	}
+	r.matcher = language.NewMatcher(r.tags)
+
+	// Register aliases in reverse so the first locale of a language wins its base name
+	for i := len(r.locales) - 1; i >= 0; i-- {
+		r.addAliases(i)
+	}
+
+	if defaultTag != "" {
+		l, err := r.Resolve(defaultTag)
+		if err != nil {
+			return nil, fmt.Errorf("locales: default: %w", err)
+		}
+		r.def = r.index(l.Tag)
+	}
+	return r, nil
+}
+
+func newLocale(tag language.Tag) Locale {
+	base, _ := tag.Base()
+	return Locale{
+		Tag:      tag.String(),
+		Name:     display.English.Tags().Name(tag),
+		Native:   display.Self.Name(tag),
+		Language: strings.ToLower(display.English.Languages().Name(base)),
+	}
+}
+
+// addAliases registers the names locale i can be referred to by
+func (r *Registry) addAliases(i int) {
+	tag := r.tags[i]
+	l := r.locales[i]
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	baseName := display.English.Languages().Name(base)
+	baseNative := display.Self.Name(language.Make(base.String()))
+
+	names := []string{
+		l.Tag, l.Name, l.Native,
+		baseName + " (" + display.English.Regions().Name(region) + ")",
+		baseName + " " + region.String(),
+		base.String(), baseName, baseNative,
+	}
+	for _, name := range names {
+		if name != "" {
+			r.aliases[strings.ToLower(name)] = i
+		}
+	}
+}
+
+func (r *Registry) index(tag string) int {
+	for i, l := range r.locales {
+		if strings.EqualFold(l.Tag, tag) {
+			return i
+		}
+	}
+	return -1
+}
+
+// List returns the supported locales in configured order
+func (r *Registry) List() []Locale {
+	return append([]Locale(nil), r.locales...)
+}
+
+// Default returns the locale used when no language is given
+func (r *Registry) Default() Locale {
+	return r.locales[r.def]
+}
+
+// Resolve normalizes a BCP-47 tag or language name to a supported locale.
+// Empty input resolves to the default locale; tags for unsupported regions resolve to
+// the closest supported one, e.g. en-AU to en-GB.
+func (r *Registry) Resolve(input string) (Locale, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(input), " "))
+	if key == "" {
+		return r.Default(), nil
+	}
+	if i, ok := r.aliases[key]; ok {
+		return r.locales[i], nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(key, "_", "-"))
+	if err != nil {
+		return Locale{}, fmt.Errorf("%w: %q", ErrUnsupported, input)
+	}
+	_, i, confidence := r.matcher.Match(tag)
+	if confidence < language.High {
+		return Locale{}, fmt.Errorf("%w: %q", ErrUnsupported, input)
+	}
+	return r.locales[i], nil
+}
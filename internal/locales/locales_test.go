@@ -0,0 +1,98 @@
+package locales
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	r, err := New([]string{"pt_BR", "en-GB", "en-gb", "hi-IN"}, "")
+	if err != nil {
+		t.Fatalf("New = %v", err)
+	}
+	list := r.List()
+	want := []Locale{
+		{Tag: "pt-BR", Name: "Brazilian Portuguese", Native: "português", Language: "portuguese"},
+		{Tag: "en-GB", Name: "British English", Native: "British English", Language: "english"},
+		{Tag: "hi-IN", Name: "Hindi (India)", Native: "हिन्दी", Language: "hindi"},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("List = %+v, want %d locales without the duplicate", list, len(want))
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Errorf("locale %d = %+v, want %+v", i, list[i], want[i])
+		}
+	}
+
+	// a copy: changing it doesn't change the registry
+	list[0].Tag = "xx"
+	if r.List()[0].Tag != "pt-BR" {
+		t.Errorf("List shares the registry's slice")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaultTag string
+		want       string
+		err        error
+	}{
+		{"first tag when unset", "", "en-US", nil},
+		{"configured tag", "es-MX", "es-MX", nil},
+		{"configured by name", "british english", "en-GB", nil},
+		{"unsupported", "ko-KR", "", ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(DefaultTags, tt.defaultTag)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("New = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := r.Default().Tag; got != tt.want {
+				t.Errorf("Default = %s, want %s", got, tt.want)
+			}
+			if got, _ := r.Resolve(""); got.Tag != tt.want {
+				t.Errorf("Resolve(\"\") = %s, want the default %s", got.Tag, tt.want)
+			}
+		})
+	}
+
+	if _, err := New(nil, ""); err == nil {
+		t.Errorf("New without tags succeeded, want an error")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, err := New(DefaultTags, "")
+	if err != nil {
+		t.Fatalf("New = %v", err)
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"en", "en-US"},
+		{"English", "en-US"},
+		{"british english", "en-GB"},
+		{"pt_BR", "pt-BR"},
+		{"Portuguese (Brazil)", "pt-BR"},
+		{"  Brazilian   Portuguese ", "pt-BR"},
+		{"हिन्दी", "hi-IN"},
+		{"en-AU", "en-GB"},
+	}
+	for _, tt := range tests {
+		if got, err := r.Resolve(tt.input); err != nil || got.Tag != tt.want {
+			t.Errorf("Resolve(%q) = %s, %v; want %s", tt.input, got.Tag, err, tt.want)
+		}
+	}
+	for _, input := range []string{"klingon", "ko-KR"} {
+		if _, err := r.Resolve(input); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Resolve(%q) = %v, want %v", input, err, ErrUnsupported)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package moderation
+
+import "github.com/vnaveen-mh/welcome-note-generator/internal/types"
+
+// Defaults returns the built-in rules: common profanity, sanitized, and slurs, blocked,
+// for the languages notes are usually requested in, plus every PII detector.
+// Deployments with stricter or looser needs replace them with a rules file.
+func Defaults() Rules {
+	return Rules{
+		WordLists: []WordList{
+			{
+				Name:     "profanity",
+				Action:   ActionSanitize,
+				Category: types.CategoryToxicity,
+				Severity: 0.5,
+				Words: map[string][]string{
+					"en":    {"fuck", "fucking", "shit", "bullshit", "bitch", "bastard", "asshole", "dickhead", "motherfucker", "piss off"},
+					"en-GB": {"bollocks", "wanker", "twat", "arsehole", "bloody hell"},
+					"en-AU": {"bloody hell", "drongo"},
+					"es":    {"mierda", "joder", "gilipollas", "cabrón", "hijo de puta"},
+					"es-MX": {"pendejo", "chingada", "pinche"},
+					"fr":    {"merde", "putain", "connard", "salope", "enculé"},
+					"de":    {"scheiße", "scheisse", "arschloch", "wichser", "fotze"},
+					"it":    {"cazzo", "stronzo", "vaffanculo", "merda"},
+					"pt":    {"merda", "caralho", "foda-se", "filho da puta"},
+					"pt-BR": {"porra", "puta que pariu"},
+					"nl":    {"kut", "klootzak", "godverdomme"},
+				},
+			},
+			{
+				Name:     "slurs",
+				Action:   ActionBlock,
+				Category: types.CategoryHate,
+				Severity: 1,
+				Words: map[string][]string{
+					AnyLocale: {"nigger", "nigga", "faggot", "kike", "chink", "spic", "wetback", "tranny", "raghead"},
+					"en":      {"retard", "retarded", "gook", "coon"},
+					"es":      {"sudaca", "maricón"},
+					"fr":      {"bougnoule", "pédé"},
+					"de":      {"kanake", "schwuchtel"},
+					"pt":      {"viado", "sapatão"},
+				},
+			},
+		},
+		PII: PIIDetectors,
+	}
+}
@@ -0,0 +1,218 @@
+package moderation
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Engine applies compiled Rules to notes. It is safe for concurrent use.
+type Engine struct {
+	lists []wordList
+	pii   []piiDetector
+}
+
+// wordList is a WordList with one pattern per locale key
+type wordList struct {
+	WordList
+	patterns map[string]*regexp.Regexp // lowercase locale key -> alternation of its words
+}
+
+// Report is the outcome of Engine.Check
+type Report struct {
+	Findings  []types.RuleFinding
+	Blocked   bool   // a block rule matched
+	Sanitized string // the text with sanitize matches replaced; the text itself when none matched
+}
+
+// New compiles rules into an Engine
+func New(rules Rules) (*Engine, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{}
+	for _, l := range rules.WordLists {
+		compiled := wordList{WordList: l, patterns: map[string]*regexp.Regexp{}}
+		for locale, words := range l.Words {
+			p, err := wordPattern(words)
+			if err != nil {
+				return nil, fmt.Errorf("%w: word list %q (%s): %v", ErrInvalid, l.Name, locale, err)
+			}
+			if p != nil {
+				compiled.patterns[strings.ToLower(locale)] = p
+			}
+		}
+		e.lists = append(e.lists, compiled)
+	}
+	for _, d := range PIIDetectors {
+		if slices.Contains(rules.PII, d) {
+			e.pii = append(e.pii, piiDetectors[d]...)
+		}
+	}
+	return e, nil
+}
+
+// wordPattern matches any of words case-insensitively, longest first so phrases
+// win over the words they contain; nil when there are no words
+func wordPattern(words []string) (*regexp.Regexp, error) {
+	var quoted []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(quoted, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return regexp.Compile(`(?i)` + strings.Join(quoted, "|"))
+}
+
+// Check runs the rules over text written for locale, a BCP-47 tag. PII matches equal to
+// one of allow, such as the email address in the signature the requester asked for, are
+// ignored. Word list matches never are, so a requester can't lift a block.
+func (e *Engine) Check(text, locale string, allow ...string) Report {
+	var findings []types.RuleFinding
+	taken := func(start, end int) bool {
+		for _, f := range findings {
+			if start < f.End && f.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+	allowed := func(match string) bool {
+		for _, a := range allow {
+			if strings.EqualFold(strings.TrimSpace(a), match) {
+				return true
+			}
+		}
+		return false
+	}
+	replacements := map[int]string{}
+
+	// PII first, so a word list can't claim part of an email address
+	for _, d := range e.pii {
+		for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			match := text[start:end]
+			if taken(start, end) || allowed(match) || (d.valid != nil && !d.valid(match)) {
+				continue
+			}
+			findings = append(findings, types.RuleFinding{
+				Rule: d.name, Kind: KindPII, Action: ActionSanitize, Category: types.CategoryPII, Severity: d.severity,
+				Match: match, Start: start, End: end,
+			})
+			replacements[start] = d.replacement
+		}
+	}
+
+	keys := localeKeys(locale)
+	for _, l := range e.lists {
+		for _, key := range keys {
+			p := l.patterns[key]
+			if p == nil {
+				continue
+			}
+			for _, loc := range p.FindAllStringIndex(text, -1) {
+				start, end := loc[0], loc[1]
+				match := text[start:end]
+				if !onWordBoundaries(text, start, end) || taken(start, end) {
+					continue
+				}
+				findings = append(findings, types.RuleFinding{
+					Rule: l.Name, Kind: KindWord, Action: l.Action, Category: l.category(), Severity: l.severity(),
+					Match: match, Start: start, End: end,
+				})
+				replacements[start] = l.Replacement
+				if replacements[start] == "" {
+					replacements[start] = strings.Repeat("*", utf8.RuneCountInString(match))
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(findings, func(a, b types.RuleFinding) int { return cmp.Compare(a.Start, b.Start) })
+	report := Report{Findings: findings, Sanitized: text}
+	var sb strings.Builder
+	last := 0
+	for _, f := range findings {
+		if f.Action == ActionBlock {
+			report.Blocked = true
+			continue
+		}
+		sb.WriteString(text[last:f.Start])
+		sb.WriteString(replacements[f.Start])
+		last = f.End
+	}
+	if last > 0 {
+		sb.WriteString(text[last:])
+		report.Sanitized = sb.String()
+	}
+
+	// findings report character offsets; bytes were only needed to rebuild the text
+	for i, f := range report.Findings {
+		report.Findings[i].Start = utf8.RuneCountInString(text[:f.Start])
+		report.Findings[i].End = report.Findings[i].Start + utf8.RuneCountInString(f.Match)
+	}
+	return report
+}
+
+// localeKeys lists the word list keys that apply to a locale: every locale, its base
+// language and the full tag, e.g. "*", "en", "en-gb"
+func localeKeys(locale string) []string {
+	keys := []string{AnyLocale}
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return keys
+	}
+	base, _, found := strings.Cut(locale, "-")
+	keys = append(keys, base)
+	if found {
+		keys = append(keys, locale)
+	}
+	return keys
+}
+
+// onWordBoundaries reports whether text[start:end] isn't part of a longer word,
+// so "ass" doesn't match inside "class". Go's \b only knows ASCII letters.
+func onWordBoundaries(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Summary describes findings for a moderation note, e.g. "profanity (2), email"
+func Summary(findings []types.RuleFinding) string {
+	counts := map[string]int{}
+	var rules []string
+	for _, f := range findings {
+		if counts[f.Rule] == 0 {
+			rules = append(rules, f.Rule)
+		}
+		counts[f.Rule]++
+	}
+	parts := make([]string, len(rules))
+	for i, r := range rules {
+		parts[i] = r
+		if counts[r] > 1 {
+			parts[i] = fmt.Sprintf("%s (%d)", r, counts[r])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,273 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestCheck(t *testing.T) {
+	e, err := New(Defaults())
+	if err != nil {
+		t.Fatalf("New(Defaults()) = %v", err)
+	}
+	tests := []struct {
+		name      string
+		text      string
+		locale    string
+		allow     []string
+		rules     []string // rules of the findings, in order
+		blocked   bool
+		sanitized string
+	}{
+		{
+			name:      "clean",
+			text:      "Welcome to the team, Priya!",
+			locale:    "en",
+			sanitized: "Welcome to the team, Priya!",
+		},
+		{
+			name:      "profanity is masked",
+			text:      "Welcome, this is fucking great.",
+			locale:    "en",
+			rules:     []string{"profanity"},
+			sanitized: "Welcome, this is ******* great.",
+		},
+		{
+			name:      "match is case insensitive",
+			text:      "Holy SHIT, welcome!",
+			locale:    "en-US",
+			rules:     []string{"profanity"},
+			sanitized: "Holy ****, welcome!",
+		},
+		{
+			name:      "phrase wins over the words it contains",
+			text:      "Bloody hell, welcome!",
+			locale:    "en-GB",
+			rules:     []string{"profanity"},
+			sanitized: "***********, welcome!",
+		},
+		{
+			name:      "regional words only apply to their region",
+			text:      "Welcome, you drongo!",
+			locale:    "en-GB",
+			sanitized: "Welcome, you drongo!",
+		},
+		{
+			name:      "regional words apply to their region",
+			text:      "Welcome, you drongo!",
+			locale:    "en-AU",
+			rules:     []string{"profanity"},
+			sanitized: "Welcome, you ******!",
+		},
+		{
+			name:      "base language words apply to its regions",
+			text:      "¡Qué mierda de lunes, bienvenida!",
+			locale:    "es-MX",
+			rules:     []string{"profanity"},
+			sanitized: "¡Qué ****** de lunes, bienvenida!",
+		},
+		{
+			name:      "words of another language are ignored",
+			text:      "Welcome to the merde team.",
+			locale:    "en",
+			sanitized: "Welcome to the merde team.",
+		},
+		{
+			name:      "no match inside a longer word",
+			text:      "Welcome to the class of shitake lovers.",
+			locale:    "en",
+			sanitized: "Welcome to the class of shitake lovers.",
+		},
+		{
+			name:      "slur blocks in every locale",
+			text:      "Welcome, faggot.",
+			locale:    "ja",
+			rules:     []string{"slurs"},
+			blocked:   true,
+			sanitized: "Welcome, faggot.",
+		},
+		{
+			name:      "email",
+			text:      "Write to priya.k@example.co.uk anytime.",
+			locale:    "en",
+			rules:     []string{PIIEmail},
+			sanitized: "Write to [email] anytime.",
+		},
+		{
+			name:      "phone",
+			text:      "Call me on +44 20 7946 0958 tomorrow.",
+			locale:    "en",
+			rules:     []string{PIIPhone},
+			sanitized: "Call me on [phone] tomorrow.",
+		},
+		{
+			name:      "date is not a phone",
+			text:      "You start on 2024-01-15.",
+			locale:    "en",
+			sanitized: "You start on 2024-01-15.",
+		},
+		{
+			name:      "card passing luhn",
+			text:      "Card 4111 1111 1111 1111 is on file.",
+			locale:    "en",
+			rules:     []string{PIICard},
+			sanitized: "Card [card number] is on file.",
+		},
+		{
+			name:      "card failing luhn",
+			text:      "Ref 4111111111111112 is on file.",
+			locale:    "en",
+			sanitized: "Ref 4111111111111112 is on file.",
+		},
+		{
+			name:      "us ssn",
+			text:      "SSN 123-45-6789 noted.",
+			locale:    "en",
+			rules:     []string{PIINationalID},
+			sanitized: "SSN [national ID] noted.",
+		},
+		{
+			name:      "uk national insurance number",
+			text:      "NI number AB 12 34 56 C noted.",
+			locale:    "en-GB",
+			rules:     []string{PIINationalID},
+			sanitized: "NI number [national ID] noted.",
+		},
+		{
+			name:      "aadhaar",
+			text:      "Aadhaar 2345 6789 0123 noted.",
+			locale:    "hi",
+			rules:     []string{PIINationalID},
+			sanitized: "Aadhaar [national ID] noted.",
+		},
+		{
+			name:      "allowed match is ignored",
+			text:      "Questions? Email hr@example.com.",
+			locale:    "en",
+			allow:     []string{"HR@example.com"},
+			sanitized: "Questions? Email hr@example.com.",
+		},
+		{
+			name:      "pii containing an allowed value is not",
+			text:      "Questions? Email hr@example.com.",
+			locale:    "en",
+			allow:     []string{"Email hr@example.com or call"},
+			rules:     []string{PIIEmail},
+			sanitized: "Questions? Email [email].",
+		},
+		{
+			name:      "allowed words still block",
+			text:      "Welcome, faggot.",
+			locale:    "en",
+			allow:     []string{"faggot", "Faggot Inc"},
+			rules:     []string{"slurs"},
+			blocked:   true,
+			sanitized: "Welcome, faggot.",
+		},
+		{
+			name:      "pii and words together",
+			text:      "Shit, mail me at a@b.io.",
+			locale:    "en",
+			rules:     []string{"profanity", PIIEmail},
+			sanitized: "****, mail me at [email].",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.Check(tt.text, tt.locale, tt.allow...)
+			var rules []string
+			for _, f := range got.Findings {
+				rules = append(rules, f.Rule)
+			}
+			if !equal(rules, tt.rules) {
+				t.Errorf("Check(%q) rules = %v, want %v", tt.text, rules, tt.rules)
+			}
+			if got.Blocked != tt.blocked {
+				t.Errorf("Check(%q) blocked = %v, want %v", tt.text, got.Blocked, tt.blocked)
+			}
+			if got.Sanitized != tt.sanitized {
+				t.Errorf("Check(%q) sanitized = %q, want %q", tt.text, got.Sanitized, tt.sanitized)
+			}
+		})
+	}
+}
+
+func TestCheckFindingOffsetsAreCharacters(t *testing.T) {
+	e, err := New(Defaults())
+	if err != nil {
+		t.Fatalf("New(Defaults()) = %v", err)
+	}
+	got := e.Check("Schöne Grüße, Arschloch!", "de")
+	if len(got.Findings) != 1 {
+		t.Fatalf("Check findings = %+v, want one", got.Findings)
+	}
+	f := got.Findings[0]
+	if f.Start != 14 || f.End != 23 || f.Match != "Arschloch" {
+		t.Errorf("finding = %+v, want Arschloch at [14, 23)", f)
+	}
+	if f.Category != types.CategoryToxicity || f.Kind != KindWord || f.Action != ActionSanitize {
+		t.Errorf("finding = %+v, want a sanitized toxicity word", f)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		ok    bool
+	}{
+		{"defaults", Defaults(), true},
+		{"empty", Rules{}, true},
+		{"unnamed list", Rules{WordLists: []WordList{{Action: ActionBlock}}}, false},
+		{"duplicate list", Rules{WordLists: []WordList{{Name: "a", Action: ActionBlock}, {Name: "a", Action: ActionSanitize}}}, false},
+		{"unknown action", Rules{WordLists: []WordList{{Name: "a", Action: "drop"}}}, false},
+		{"unknown category", Rules{WordLists: []WordList{{Name: "a", Action: ActionBlock, Category: "rudeness"}}}, false},
+		{"severity out of range", Rules{WordLists: []WordList{{Name: "a", Action: ActionBlock, Severity: 1.5}}}, false},
+		{"unknown pii detector", Rules{PII: []string{"passport"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.rules)
+			if tt.ok && err != nil {
+				t.Fatalf("New = %v, want no error", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalid) {
+				t.Fatalf("New = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		rules []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{PIIEmail}, "email"},
+		{[]string{"profanity", PIIEmail, "profanity"}, "profanity (2), email"},
+	}
+	for _, tt := range tests {
+		var findings []types.RuleFinding
+		for _, r := range tt.rules {
+			findings = append(findings, types.RuleFinding{Rule: r})
+		}
+		if got := Summary(findings); got != tt.want {
+			t.Errorf("Summary(%v) = %q, want %q", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package moderation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Voting policies that combine an ensemble's votes
+const (
+	VoteAnyBlocks = "any-blocks" // one blocking vote blocks the note
+	VoteMajority  = "majority"   // half or more of the votes block it
+	VoteWeighted  = "weighted"   // half or more of the voting weight blocks it
+)
+
+// VotingPolicies lists the voting policies an Ensemble accepts
+var VotingPolicies = []string{VoteAnyBlocks, VoteMajority, VoteWeighted}
+
+// ModeratorRules names the rule engine in an ensemble; every other moderator is a model
+const ModeratorRules = "rules"
+
+// Moderator is one member of an ensemble
+type Moderator struct {
+	Name   string  // ModeratorRules or a model name such as "ollama/gpt-oss:latest"
+	Weight float64 // only weighted voting uses it; 1 unless configured
+}
+
+// Ensemble is a set of moderators that each vote on whether a note is blocked, and the
+// policy that combines their votes. A moderator that fails abstains, but at least Quorum
+// model moderators must vote, so the rule engine never decides alone. Ties block, so a
+// split ensemble errs on the safe side.
+type Ensemble struct {
+	Moderators []Moderator
+	Voting     string
+	Quorum     int // model moderators that must vote; 0 means 1
+}
+
+// ParseEnsemble parses a comma-separated list of moderators, each optionally weighted
+// as name=weight, such as "rules=2,googleai/gemini-2.5-flash,ollama/gpt-oss:latest".
+// quorum is how many model moderators must vote; 0 means 1. An empty spec returns nil:
+// no ensemble.
+func ParseEnsemble(spec, voting string, quorum int) (*Ensemble, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	e := &Ensemble{Voting: strings.ToLower(strings.TrimSpace(voting)), Quorum: quorum}
+	if e.Voting == "" {
+		e.Voting = VoteAnyBlocks
+	}
+	for _, field := range strings.Split(spec, ",") {
+		name, weight, weighted := strings.Cut(strings.TrimSpace(field), "=")
+		m := Moderator{Name: strings.TrimSpace(name), Weight: 1}
+		if weighted {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: ensemble: moderator %q: weight %q is not a number", ErrInvalid, m.Name, weight)
+			}
+			m.Weight = w
+		}
+		e.Moderators = append(e.Moderators, m)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Validate reports a voting policy or moderator list the ensemble can't vote with
+func (e *Ensemble) Validate() error {
+	if !slices.Contains(VotingPolicies, e.Voting) {
+		return fmt.Errorf("%w: ensemble: unknown voting policy %q, want %s", ErrInvalid, e.Voting, strings.Join(VotingPolicies, ", "))
+	}
+	if len(e.Moderators) == 0 {
+		return fmt.Errorf("%w: ensemble: no moderators", ErrInvalid)
+	}
+	seen := map[string]bool{}
+	models := 0
+	for _, m := range e.Moderators {
+		switch {
+		case m.Name == "":
+			return fmt.Errorf("%w: ensemble: empty moderator name", ErrInvalid)
+		case seen[m.Name]:
+			return fmt.Errorf("%w: ensemble: moderator %q listed twice", ErrInvalid, m.Name)
+		case m.Weight <= 0:
+			return fmt.Errorf("%w: ensemble: moderator %q: weight must be positive", ErrInvalid, m.Name)
+		}
+		seen[m.Name] = true
+		if m.Name != ModeratorRules {
+			models++
+		}
+	}
+	switch {
+	case models == 0:
+		return fmt.Errorf("%w: ensemble: no model moderators; the rules can't vote alone", ErrInvalid)
+	case e.Quorum < 0 || e.Quorum > models:
+		return fmt.Errorf("%w: ensemble: quorum must be between 1 and the %d model moderators", ErrInvalid, models)
+	}
+	return nil
+}
+
+// Has reports whether the ensemble includes the named moderator; a nil ensemble has none
+func (e *Ensemble) Has(name string) bool {
+	return e != nil && slices.ContainsFunc(e.Moderators, func(m Moderator) bool { return m.Name == name })
+}
+
+// Tally combines votes under the ensemble's voting policy and explains the outcome.
+// Votes with an error are abstentions; ok is false when fewer model moderators than
+// the quorum voted, however the rule engine voted.
+func (e *Ensemble) Tally(votes []types.ModerationVote) (blocked bool, reason string, ok bool) {
+	var cast, modelsCast, blocking int
+	var castWeight, blockingWeight float64
+	var blockers []string
+	for _, v := range votes {
+		if v.Error != "" {
+			continue
+		}
+		cast++
+		if v.Moderator != ModeratorRules {
+			modelsCast++
+		}
+		castWeight += v.Weight
+		if v.Blocked {
+			blocking++
+			blockingWeight += v.Weight
+			blockers = append(blockers, v.Moderator)
+		}
+	}
+	if modelsCast < max(e.Quorum, 1) {
+		return false, "", false
+	}
+
+	switch e.Voting {
+	case VoteMajority:
+		blocked = 2*blocking >= cast
+		reason = fmt.Sprintf("%d of %d moderators voted to block", blocking, cast)
+	case VoteWeighted:
+		blocked = 2*blockingWeight >= castWeight
+		reason = fmt.Sprintf("%s of %s voting weight was to block", formatWeight(blockingWeight), formatWeight(castWeight))
+	default:
+		blocked = blocking > 0
+		reason = fmt.Sprintf("%d of %d moderators voted to block", blocking, cast)
+		if blocked {
+			reason = "blocked by " + strings.Join(blockers, ", ")
+		}
+	}
+	// a majority of none isn't a block
+	if blocking == 0 {
+		blocked = false
+	}
+	return blocked, e.Voting + " vote: " + reason, true
+}
+
+func formatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'f', -1, 64)
+}
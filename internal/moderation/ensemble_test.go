@@ -0,0 +1,185 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+func TestParseEnsemble(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		voting string
+		quorum int
+		want   *Ensemble
+		ok     bool
+	}{
+		{name: "empty", spec: " ", ok: true},
+		{
+			name: "defaults",
+			spec: "rules, googleai/gemini-2.5-flash",
+			want: &Ensemble{Moderators: []Moderator{{"rules", 1}, {"googleai/gemini-2.5-flash", 1}}, Voting: VoteAnyBlocks},
+			ok:   true,
+		},
+		{
+			name:   "weights, voting and quorum",
+			spec:   "rules=2,a=0.5,b",
+			voting: " Weighted ",
+			quorum: 2,
+			want:   &Ensemble{Moderators: []Moderator{{"rules", 2}, {"a", 0.5}, {"b", 1}}, Voting: VoteWeighted, Quorum: 2},
+			ok:     true,
+		},
+		{name: "unknown voting", spec: "a", voting: "unanimous"},
+		{name: "weight not a number", spec: "a=heavy"},
+		{name: "weight not positive", spec: "a=0"},
+		{name: "listed twice", spec: "a,a"},
+		{name: "empty name", spec: "a,,b"},
+		{name: "rules alone", spec: "rules"},
+		{name: "quorum above the models", spec: "rules,a,b", quorum: 3},
+		{name: "negative quorum", spec: "a", quorum: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEnsemble(tt.spec, tt.voting, tt.quorum)
+			if !tt.ok {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("ParseEnsemble(%q) = %+v, %v; want ErrInvalid", tt.spec, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEnsemble(%q) = %v", tt.spec, err)
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ParseEnsemble(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+			if got == nil {
+				return
+			}
+			if got.Voting != tt.want.Voting || got.Quorum != tt.want.Quorum || len(got.Moderators) != len(tt.want.Moderators) {
+				t.Fatalf("ParseEnsemble(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+			for i := range got.Moderators {
+				if got.Moderators[i] != tt.want.Moderators[i] {
+					t.Errorf("moderator %d = %+v, want %+v", i, got.Moderators[i], tt.want.Moderators[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTally(t *testing.T) {
+	allow := func(name string, weight float64) types.ModerationVote {
+		return types.ModerationVote{Moderator: name, Weight: weight}
+	}
+	block := func(name string, weight float64) types.ModerationVote {
+		return types.ModerationVote{Moderator: name, Weight: weight, Blocked: true}
+	}
+	abstain := func(name string, weight float64) types.ModerationVote {
+		return types.ModerationVote{Moderator: name, Weight: weight, Blocked: true, Error: "unavailable"}
+	}
+	tests := []struct {
+		name    string
+		voting  string
+		quorum  int
+		votes   []types.ModerationVote
+		blocked bool
+		reason  string
+		ok      bool
+	}{
+		{
+			name:   "any-blocks, all allow",
+			voting: VoteAnyBlocks,
+			votes:  []types.ModerationVote{allow("rules", 1), allow("a", 1), allow("b", 1)},
+			reason: "any-blocks vote: 0 of 3 moderators voted to block",
+			ok:     true,
+		},
+		{
+			name:    "any-blocks, one blocks",
+			voting:  VoteAnyBlocks,
+			votes:   []types.ModerationVote{allow("rules", 1), block("a", 1), allow("b", 1)},
+			blocked: true,
+			reason:  "any-blocks vote: blocked by a",
+			ok:      true,
+		},
+		{
+			name:   "majority, minority blocks",
+			voting: VoteMajority,
+			votes:  []types.ModerationVote{block("rules", 1), allow("a", 1), allow("b", 1)},
+			reason: "majority vote: 1 of 3 moderators voted to block",
+			ok:     true,
+		},
+		{
+			name:    "majority, tie blocks",
+			voting:  VoteMajority,
+			votes:   []types.ModerationVote{block("a", 1), allow("b", 1)},
+			blocked: true,
+			reason:  "majority vote: 1 of 2 moderators voted to block",
+			ok:      true,
+		},
+		{
+			name:    "majority counts only cast votes",
+			voting:  VoteMajority,
+			votes:   []types.ModerationVote{block("a", 1), allow("b", 1), abstain("c", 1)},
+			blocked: true,
+			reason:  "majority vote: 1 of 2 moderators voted to block",
+			ok:      true,
+		},
+		{
+			name:   "weighted, light blocker",
+			voting: VoteWeighted,
+			votes:  []types.ModerationVote{block("a", 1), allow("b", 2)},
+			reason: "weighted vote: 1 of 3 voting weight was to block",
+			ok:     true,
+		},
+		{
+			name:    "weighted, heavy blocker",
+			voting:  VoteWeighted,
+			votes:   []types.ModerationVote{block("rules", 2.5), allow("a", 1), allow("b", 1.5)},
+			blocked: true,
+			reason:  "weighted vote: 2.5 of 5 voting weight was to block",
+			ok:      true,
+		},
+		{
+			name:   "every model abstains",
+			voting: VoteAnyBlocks,
+			votes:  []types.ModerationVote{allow("rules", 1), abstain("a", 1), abstain("b", 1)},
+		},
+		{
+			name:   "rules alone can't block either",
+			voting: VoteAnyBlocks,
+			votes:  []types.ModerationVote{block("rules", 1), abstain("a", 1)},
+		},
+		{
+			name:   "below quorum",
+			voting: VoteMajority,
+			quorum: 2,
+			votes:  []types.ModerationVote{allow("rules", 1), allow("a", 1), abstain("b", 1)},
+		},
+		{
+			name:   "quorum met",
+			voting: VoteMajority,
+			quorum: 2,
+			votes:  []types.ModerationVote{allow("rules", 1), allow("a", 1), allow("b", 1), abstain("c", 1)},
+			reason: "majority vote: 0 of 3 moderators voted to block",
+			ok:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Ensemble{Voting: tt.voting, Quorum: tt.quorum}
+			blocked, reason, ok := e.Tally(tt.votes)
+			if ok != tt.ok {
+				t.Fatalf("Tally ok = %v, want %v", ok, tt.ok)
+			}
+			if blocked != tt.blocked {
+				t.Errorf("Tally blocked = %v, want %v", blocked, tt.blocked)
+			}
+			if reason != tt.reason {
+				t.Errorf("Tally reason = %q, want %q", reason, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"regexp"
+	"unicode"
+)
+
+// piiDetector finds one kind of PII. valid, when set, rejects regex matches that
+// only look like PII, such as digit runs failing a checksum.
+type piiDetector struct {
+	name        string
+	pattern     *regexp.Regexp
+	valid       func(match string) bool
+	replacement string
+	severity    float64
+}
+
+var piiDetectors = map[string][]piiDetector{
+	PIIEmail: {{
+		name:        PIIEmail,
+		pattern:     regexp.MustCompile(`[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.\p{L}{2,}`),
+		replacement: "[email]",
+		severity:    0.5,
+	}},
+	PIIPhone: {{
+		name:        PIIPhone,
+		pattern:     regexp.MustCompile(`\+?\(?\d[\d\s().-]{6,}\d`),
+		valid:       validPhone,
+		replacement: "[phone]",
+		severity:    0.5,
+	}},
+	PIICard: {{
+		name:        PIICard,
+		pattern:     regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid:       luhn,
+		replacement: "[card number]",
+		severity:    0.9,
+	}},
+	PIINationalID: {
+		{
+			// US Social Security number; area 000, 666 and 900+ are never issued
+			name:        PIINationalID,
+			pattern:     regexp.MustCompile(`\b(?:00[1-9]|0[1-9]\d|[1-578]\d\d|6[0-57-9]\d|66[0-57-9])-\d{2}-\d{4}\b`),
+			replacement: "[national ID]",
+			severity:    0.9,
+		},
+		{
+			// UK National Insurance number
+			name:        PIINationalID,
+			pattern:     regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`),
+			replacement: "[national ID]",
+			severity:    0.9,
+		},
+		{
+			// Indian Aadhaar number, written in groups of four
+			name:        PIINationalID,
+			pattern:     regexp.MustCompile(`\b[2-9]\d{3}[ -]\d{4}[ -]\d{4}\b`),
+			replacement: "[national ID]",
+			severity:    0.9,
+		},
+	},
+}
+
+// validPhone accepts 9 to 15 digits, or 8 with a leading +, so dates such as
+// 2024-01-15 and short numbers aren't taken for phone numbers
+func validPhone(match string) bool {
+	n := digits(match)
+	if match[0] == '+' {
+		return n >= 8 && n <= 15
+	}
+	return n >= 9 && n <= 15
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum used by payment cards
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+func digits(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
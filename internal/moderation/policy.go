@@ -0,0 +1,175 @@
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// Decisions a Policy makes about a moderated note, from least to most restrictive
+const (
+	DecisionAllow    = "allow"    // show the note as written
+	DecisionSanitize = "sanitize" // show the sanitized note
+	DecisionReview   = "review"   // hold the note for a human to review
+	DecisionBlock    = "block"    // don't show the note
+)
+
+// Decisions lists the decisions in increasing order of restriction
+var Decisions = []string{DecisionAllow, DecisionSanitize, DecisionReview, DecisionBlock}
+
+// Thresholds are the severities at which a category's content is sanitized, held for
+// review or blocked. A zero threshold never triggers.
+type Thresholds struct {
+	Sanitize float64 `json:"sanitize,omitempty"`
+	Review   float64 `json:"review,omitempty"`
+	Block    float64 `json:"block,omitempty"`
+}
+
+// Policy decides what happens to a moderated note from its category severities, so
+// deployments can be stricter or looser than the moderation prompt on its own.
+// Categories without thresholds are always allowed.
+type Policy struct {
+	Name       string                `json:"name"`
+	Categories map[string]Thresholds `json:"categories"`
+}
+
+// Decision is a Policy's verdict on a note, with the category that triggered it
+type Decision struct {
+	Action    string  `json:"action"` // allow, sanitize, review or block
+	Category  string  `json:"category,omitempty"`
+	Severity  float64 `json:"severity,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// Reason explains the decision, e.g. "toxicity 0.62 ≥ review threshold 0.5"
+func (d Decision) Reason() string {
+	if d.Category == "" {
+		return "no category reached a threshold"
+	}
+	return fmt.Sprintf("%s %.2f ≥ %s threshold %.2f", d.Category, d.Severity, d.Action, d.Threshold)
+}
+
+// Decide returns the most restrictive decision any category reaches, allow when none does
+func (p *Policy) Decide(categories []types.CategoryScore) Decision {
+	decision := Decision{Action: DecisionAllow}
+	for _, c := range categories {
+		t, ok := p.Categories[c.Category]
+		if !ok {
+			continue
+		}
+		for _, level := range []struct {
+			action    string
+			threshold float64
+		}{{DecisionBlock, t.Block}, {DecisionReview, t.Review}, {DecisionSanitize, t.Sanitize}} {
+			if level.threshold <= 0 || c.Severity < level.threshold {
+				continue
+			}
+			if slices.Index(Decisions, level.action) > slices.Index(Decisions, decision.Action) {
+				decision = Decision{Action: level.action, Category: c.Category, Severity: c.Severity, Threshold: level.threshold}
+			}
+			break
+		}
+	}
+	return decision
+}
+
+// SanitizeCategories lists the categories whose severity reaches their sanitize
+// threshold, so the sanitizer can be told what must be rewritten
+func (p *Policy) SanitizeCategories(categories []types.CategoryScore) []string {
+	var names []string
+	for _, c := range categories {
+		if t, ok := p.Categories[c.Category]; ok && t.Sanitize > 0 && c.Severity >= t.Sanitize {
+			names = append(names, c.Category)
+		}
+	}
+	return names
+}
+
+// Validate checks that every category is known and its thresholds are between 0 and 1
+func (p *Policy) Validate() error {
+	for category, t := range p.Categories {
+		if !slices.Contains(types.ModerationCategories, category) {
+			return fmt.Errorf("%w: policy %q: unknown category %q, want one of %v", ErrInvalid, p.Name, category, types.ModerationCategories)
+		}
+		for _, v := range []float64{t.Sanitize, t.Review, t.Block} {
+			if v < 0 || v > 1 {
+				return fmt.Errorf("%w: policy %q: %s thresholds must be between 0 and 1", ErrInvalid, p.Name, category)
+			}
+		}
+	}
+	return nil
+}
+
+// Built-in policies
+const (
+	PolicyStrict   = "strict"   // workplaces and all-ages audiences: sanitize early, review borderline notes
+	PolicyStandard = "standard" // sanitize clear issues, block severe ones
+	PolicyLenient  = "lenient"  // comedy and roasts: allow crude humor, still stop hate and PII
+)
+
+// Preset returns a built-in policy by name
+func Preset(name string) (*Policy, error) {
+	p, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown policy %q, want %s, %s or %s", ErrInvalid, name, PolicyStrict, PolicyStandard, PolicyLenient)
+	}
+	return &p, nil
+}
+
+var presets = map[string]Policy{
+	PolicyStrict: {
+		Name: PolicyStrict,
+		Categories: map[string]Thresholds{
+			types.CategoryToxicity:   {Sanitize: 0.2, Review: 0.5, Block: 0.8},
+			types.CategoryHarassment: {Sanitize: 0.2, Review: 0.4, Block: 0.7},
+			types.CategorySexual:     {Sanitize: 0.1, Review: 0.3, Block: 0.5},
+			types.CategorySelfHarm:   {Review: 0.1, Block: 0.4},
+			types.CategoryPII:        {Sanitize: 0.1},
+			types.CategoryHate:       {Review: 0.1, Block: 0.3},
+		},
+	},
+	PolicyStandard: {
+		Name: PolicyStandard,
+		Categories: map[string]Thresholds{
+			types.CategoryToxicity:   {Sanitize: 0.4, Block: 0.9},
+			types.CategoryHarassment: {Sanitize: 0.4, Block: 0.8},
+			types.CategorySexual:     {Sanitize: 0.3, Block: 0.7},
+			types.CategorySelfHarm:   {Sanitize: 0.2, Review: 0.4, Block: 0.6},
+			types.CategoryPII:        {Sanitize: 0.1},
+			types.CategoryHate:       {Sanitize: 0.2, Block: 0.6},
+		},
+	},
+	PolicyLenient: {
+		Name: PolicyLenient,
+		Categories: map[string]Thresholds{
+			types.CategoryToxicity:   {Sanitize: 0.9},
+			types.CategoryHarassment: {Sanitize: 0.8, Block: 0.95},
+			types.CategorySexual:     {Sanitize: 0.7, Block: 0.9},
+			types.CategorySelfHarm:   {Sanitize: 0.3, Review: 0.5, Block: 0.7},
+			types.CategoryPII:        {Sanitize: 0.1},
+			types.CategoryHate:       {Sanitize: 0.3, Block: 0.6},
+		},
+	},
+}
+
+// LoadPolicyFile reads a policy from a JSON file shaped like Policy
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading moderation policy: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing moderation policy %s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = path
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,125 @@
+// Package moderation is the deterministic moderation stage that runs before the LLM
+// moderator: word lists with locale variants, slur lists and regex PII detectors.
+// It costs no tokens and gives the same answer every time.
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// ErrInvalid is returned by Rules.Validate and New for malformed rules
+var ErrInvalid = errors.New("invalid moderation rules")
+
+// Actions a rule takes on a match
+const (
+	ActionSanitize = "sanitize" // mask the match; the LLM moderator still runs
+	ActionBlock    = "block"    // block the note without asking the LLM moderator
+)
+
+// Kinds of findings
+const (
+	KindWord = "word"
+	KindPII  = "pii"
+)
+
+// PII detectors. Matches are always sanitized.
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIICard       = "card"        // payment card numbers passing the Luhn check
+	PIINationalID = "national_id" // US SSN, UK National Insurance and Indian Aadhaar numbers
+)
+
+// PIIDetectors lists the PII detectors in the order they run; a later detector
+// skips text an earlier one matched, so card numbers aren't reported as phones
+var PIIDetectors = []string{PIICard, PIINationalID, PIIEmail, PIIPhone}
+
+// AnyLocale keys the words of a WordList that apply to every locale
+const AnyLocale = "*"
+
+// WordList is a named list of words or phrases matched case-insensitively on word boundaries.
+// Words are keyed by locale: AnyLocale applies everywhere, a base language such as "es"
+// to all of its regions, and a tag such as "en-GB" to that region only.
+type WordList struct {
+	Name        string              `json:"name"`
+	Action      string              `json:"action"`                // sanitize or block
+	Replacement string              `json:"replacement,omitempty"` // replaces a sanitized match; empty masks it with asterisks
+	Category    string              `json:"category,omitempty"`    // moderation category of a match; defaults to toxicity
+	Severity    float64             `json:"severity,omitempty"`    // 0–1; defaults to 1 for block lists and 0.5 otherwise
+	Words       map[string][]string `json:"words"`
+}
+
+// category returns the list's category, or the default
+func (l WordList) category() string {
+	if l.Category == "" {
+		return types.CategoryToxicity
+	}
+	return l.Category
+}
+
+// severity returns the list's severity, or the default for its action
+func (l WordList) severity() float64 {
+	switch {
+	case l.Severity > 0:
+		return l.Severity
+	case l.Action == ActionBlock:
+		return 1
+	}
+	return 0.5
+}
+
+// Rules configures an Engine
+type Rules struct {
+	WordLists []WordList `json:"wordLists"`
+	PII       []string   `json:"pii"` // PII detectors to run
+}
+
+// Validate checks that every list is named and has a known action and category,
+// and that every PII detector exists
+func (r Rules) Validate() error {
+	names := map[string]bool{}
+	for _, l := range r.WordLists {
+		if strings.TrimSpace(l.Name) == "" {
+			return fmt.Errorf("%w: word list without a name", ErrInvalid)
+		}
+		if names[l.Name] {
+			return fmt.Errorf("%w: duplicate word list %q", ErrInvalid, l.Name)
+		}
+		names[l.Name] = true
+		if l.Action != ActionSanitize && l.Action != ActionBlock {
+			return fmt.Errorf("%w: word list %q: action must be %s or %s", ErrInvalid, l.Name, ActionSanitize, ActionBlock)
+		}
+		if l.Category != "" && !slices.Contains(types.ModerationCategories, l.Category) {
+			return fmt.Errorf("%w: word list %q: unknown category %q, want one of %v", ErrInvalid, l.Name, l.Category, types.ModerationCategories)
+		}
+		if l.Severity < 0 || l.Severity > 1 {
+			return fmt.Errorf("%w: word list %q: severity must be between 0 and 1", ErrInvalid, l.Name)
+		}
+	}
+	for _, d := range r.PII {
+		if !slices.Contains(PIIDetectors, d) {
+			return fmt.Errorf("%w: unknown PII detector %q, want one of %v", ErrInvalid, d, PIIDetectors)
+		}
+	}
+	return nil
+}
+
+// LoadFile reads rules from a JSON file shaped like Rules
+func LoadFile(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("reading moderation rules: %w", err)
+	}
+	var r Rules
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Rules{}, fmt.Errorf("parsing moderation rules %s: %w", path, err)
+	}
+	return r, nil
+}
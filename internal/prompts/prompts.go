@@ -0,0 +1,163 @@
+// Package prompts loads the versioned .prompt (dotprompt) templates used by the flows.
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/dotprompt/go/dotprompt"
+)
+
+// DefaultDir is the prompt directory used when none is configured
+const DefaultDir = "prompts"
+
+// Prompt is a compiled .prompt file
+type Prompt struct {
+	Name    string // file name without the .prompt extension
+	Version string // "version" from the front matter
+
+	mu     sync.Mutex // dotprompt templates are not safe for concurrent rendering
+	render dotprompt.PromptFunction
+}
+
+// Rendered is a prompt rendered for a single model call
+type Rendered struct {
+	Name    string
+	Version string
+	System  string // text of the {{role "system"}} messages
+	User    string // text of all other messages
+}
+
+// Store holds all prompts loaded from a directory, keyed by name
+type Store struct {
+	dir     string
+	prompts map[string]*Prompt
+}
+
+// Load parses and compiles every .prompt file in dir.
+// Every prompt must declare a version in its front matter.
+func Load(dir string) (*Store, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt directory %q: %w", dir, err)
+	}
+
+	s := &Store{dir: dir, prompts: map[string]*Prompt{}}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".prompt") {
+			continue
+		}
+		p, err := loadPrompt(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		s.prompts[p.Name] = p
+	}
+	return s, nil
+}
+
+func loadPrompt(path string) (*Prompt, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt %q: %w", path, err)
+	}
+
+	// each prompt gets its own Dotprompt: Compile keeps the template on the instance
+	dp := dotprompt.NewDotprompt(nil)
+	parsed, err := dp.Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt %q: %w", path, err)
+	}
+	if parsed.Version == "" {
+		return nil, fmt.Errorf("prompt %q has no version in its front matter", path)
+	}
+
+	render, err := dp.Compile(string(source), nil)
+	if err != nil {
+		return nil, fmt.Errorf("compiling prompt %q: %w", path, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), ".prompt")
+	if parsed.Name != "" && parsed.Name != name {
+		return nil, fmt.Errorf("prompt %q declares name %q, expected %q", path, parsed.Name, name)
+	}
+
+	return &Prompt{
+		Name:    name,
+		Version: parsed.Version,
+		render:  render,
+	}, nil
+}
+
+// Dir returns the directory the prompts were loaded from
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Names returns the names of all loaded prompts, sorted
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.prompts))
+	for name := range s.prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named prompt, or nil if it isn't loaded
+func (s *Store) Lookup(name string) *Prompt {
+	return s.prompts[name]
+}
+
+// Render renders the named prompt with the given template input
+func (s *Store) Render(name string, input map[string]any) (*Rendered, error) {
+	p := s.Lookup(name)
+	if p == nil {
+		return nil, fmt.Errorf("prompt %q not found in %q", name, s.dir)
+	}
+	return p.Render(input)
+}
+
+// Render renders the prompt with the given template input
+func (p *Prompt) Render(input map[string]any) (*Rendered, error) {
+	p.mu.Lock()
+	rendered, err := p.render(&dotprompt.DataArgument{Input: input}, nil)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("rendering prompt %q: %w", p.Name, err)
+	}
+
+	var system, user []string
+	for _, msg := range rendered.Messages {
+		text := messageText(msg)
+		if text == "" {
+			continue
+		}
+		if msg.Role == dotprompt.RoleSystem {
+			system = append(system, text)
+		} else {
+			user = append(user, text)
+		}
+	}
+
+	return &Rendered{
+		Name:    p.Name,
+		Version: p.Version,
+		System:  strings.Join(system, "\n"),
+		User:    strings.Join(user, "\n"),
+	}, nil
+}
+
+func messageText(msg dotprompt.Message) string {
+	var sb strings.Builder
+	for _, part := range msg.Content {
+		if tp, ok := part.(*dotprompt.TextPart); ok {
+			sb.WriteString(tp.Text)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,122 @@
+package pseudonym
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nameWord is a capitalized word such as Jane, O'Brien, Jean-Luc or José
+const nameWord = `\p{Lu}[\p{Ll}\p{M}]*(?:['’-]\p{Lu}?[\p{Ll}\p{M}]+)*`
+
+// nameRun matches one to three capitalized words, e.g. "Jane" or "Mary Ann Lee"
+var nameRun = regexp.MustCompile(nameWord + `(?:[ \t]+` + nameWord + `){0,2}`)
+
+// nameTriggers are the words that introduce a person in an occasion or description:
+// "welcome Jane", "farewell party for Raj Patel", "bienvenida para María"
+var nameTriggers = set(
+	"welcome", "welcoming", "for", "to", "meet", "meeting", "dear", "named", "called",
+	"congratulate", "congrats", "congratulations", "celebrate", "celebrating", "honor", "honoring",
+	"hi", "hello", "from", "by", "with", "and", "mr", "mrs", "ms", "mx", "dr", "prof",
+	"bienvenido", "bienvenida", "para", "bienvenue", "pour", "willkommen", "für",
+	"benvenuto", "benvenuta", "per", "welkom", "voor", "bem-vindo", "bem-vinda",
+)
+
+// notNames are capitalized words that start sentences or name events, days and places
+// rather than people; a run containing one isn't taken for a name
+var notNames = set(
+	"welcome", "happy", "new", "year", "birthday", "anniversary", "party", "day", "week", "night",
+	"team", "company", "office", "department", "group", "club", "school", "university", "class",
+	"meeting", "conference", "summit", "offsite", "retreat", "launch", "kickoff", "onboarding",
+	"christmas", "easter", "diwali", "eid", "hanukkah", "thanksgiving", "halloween", "navidad", "noël",
+	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	"january", "february", "march", "april", "may", "june", "july", "august", "september",
+	"october", "november", "december", "the", "our", "my", "your", "all", "everyone",
+	"congratulations", "dear", "hello", "hi", "best", "regards", "thanks", "thank",
+	"ceo", "cto", "cfo", "vp", "manager", "director", "engineer", "engineering", "sales", "marketing",
+	"hr", "it", "ops", "finance", "product", "design", "support",
+	"mr", "mrs", "ms", "mx", "dr", "prof",
+)
+
+// titles may open a name, as in "Dr Smith"; they are left out of the pseudonym
+var titles = set("mr", "mrs", "ms", "mx", "dr", "prof")
+
+// FindNames returns the person names introduced by a trigger word in text, such as
+// "Jane Doe" in "welcome Jane Doe to the team". Names without a trigger aren't
+// guessed at; capitalized words are too often events and places.
+func FindNames(text string) []string {
+	var names []string
+	for _, loc := range nameRun.FindAllStringIndex(text, -1) {
+		if !onWordBoundaries(text, loc[0], loc[1]) || !nameTriggers[strings.TrimRight(strings.ToLower(lastWord(text[:loc[0]])), ".")] {
+			continue
+		}
+		name := text[loc[0]:loc[1]]
+		if title, rest, ok := strings.Cut(name, " "); ok && titles[strings.ToLower(title)] {
+			name = strings.TrimSpace(rest)
+		}
+		if looksLikeName(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SplitNames returns the names in a field listing people, such as "Jane, Bob and
+// Priya Shah". Parts that don't look like names, such as "the sales team", are skipped.
+func SplitNames(field string) []string {
+	var names []string
+	for _, part := range nameSeparators.Split(field, -1) {
+		part = strings.TrimSpace(part)
+		if nameRun.FindString(part) == part && looksLikeName(part) {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+var nameSeparators = regexp.MustCompile(`\s*(?:,|;|&|/|\n|\band\b|\by\b|\bet\b|\bund\b|\be\b)\s*`)
+
+// looksLikeName reports whether a run is longer than an initial and none of its
+// words is a known non-name
+func looksLikeName(run string) bool {
+	if utf8.RuneCountInString(run) < 2 {
+		return false
+	}
+	for _, w := range strings.Fields(run) {
+		if notNames[strings.ToLower(w)] {
+			return false
+		}
+	}
+	return true
+}
+
+// lastWord returns the word before the end of text, skipping spaces
+func lastWord(text string) string {
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	i := strings.LastIndexFunc(text, func(r rune) bool { return !isWordRune(r) && r != '.' && r != '-' })
+	return text[i+1:]
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// onWordBoundaries reports whether text[start:end] isn't part of a longer word
+func onWordBoundaries(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,202 @@
+// Package pseudonym replaces personal data with stable placeholders such as [PERSON_1]
+// before text is sent to a model provider, and puts the real values back in the reply.
+// A Vault holds the mapping for a single request and is never persisted.
+package pseudonym
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/moderation"
+)
+
+// Kinds of pseudonymized values, as they appear in placeholders
+const (
+	KindPerson     = "PERSON"
+	KindEmail      = "EMAIL"
+	KindPhone      = "PHONE"
+	KindCard       = "CARD"
+	KindNationalID = "NATIONAL_ID"
+)
+
+// piiKinds maps the moderation PII detectors to placeholder kinds
+var piiKinds = map[string]string{
+	moderation.PIIEmail:      KindEmail,
+	moderation.PIIPhone:      KindPhone,
+	moderation.PIICard:       KindCard,
+	moderation.PIINationalID: KindNationalID,
+}
+
+var piiEngine = func() *moderation.Engine {
+	e, err := moderation.New(moderation.Rules{PII: moderation.PIIDetectors})
+	if err != nil {
+		panic(err)
+	}
+	return e
+}()
+
+// placeholderPattern matches a placeholder, forgiving a model that writes
+// [person 1] instead of [PERSON_1]
+var placeholderPattern = regexp.MustCompile(`(?i)\[(PERSON|EMAIL|PHONE|CARD|NATIONAL[_ ]ID)[_ ](\d+)\]`)
+
+// Vault maps personal data to placeholders for one request. It is safe for concurrent use.
+type Vault struct {
+	mu      sync.Mutex
+	byValue map[string]string // value -> placeholder
+	byKey   map[string]string // KIND_n -> value
+	counts  map[string]int    // kind -> values registered
+}
+
+// NewVault returns an empty Vault
+func NewVault() *Vault {
+	return &Vault{byValue: map[string]string{}, byKey: map[string]string{}, counts: map[string]int{}}
+}
+
+// Add registers value under kind and returns its placeholder; a value already
+// registered keeps the placeholder it was given first
+func (v *Vault) Add(kind, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if p, ok := v.byValue[value]; ok {
+		return p
+	}
+	v.counts[kind]++
+	key := kind + "_" + strconv.Itoa(v.counts[kind])
+	v.byKey[key] = value
+	v.byValue[value] = "[" + key + "]"
+	return v.byValue[value]
+}
+
+// AddNames registers the person names found in free text, such as "welcome Jane Doe"
+func (v *Vault) AddNames(text string) {
+	for _, name := range FindNames(text) {
+		v.Add(KindPerson, name)
+	}
+}
+
+// AddNameList registers a field that names people, such as "Jane, Bob and Priya"
+func (v *Vault) AddNameList(field string) {
+	for _, name := range SplitNames(field) {
+		v.Add(KindPerson, name)
+	}
+}
+
+// AddPII registers the emails, phone numbers, card numbers and national IDs in text
+func (v *Vault) AddPII(text string) {
+	for _, f := range piiEngine.Check(text, "").Findings {
+		v.Add(piiKinds[f.Rule], f.Match)
+	}
+}
+
+// Len reports how many values are registered
+func (v *Vault) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.byValue)
+}
+
+// Counts reports how many values of each kind are registered, keyed by lowercase kind
+func (v *Vault) Counts() map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.counts) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(v.counts))
+	for kind, n := range v.counts {
+		counts[strings.ToLower(kind)] = n
+	}
+	return counts
+}
+
+// Mask registers any PII in text, then replaces every registered value with its
+// placeholder. Values only match whole words, longest first so "Jane Doe" wins over
+// "Jane" and "Ana" is left alone in "Anastasia". A longer value that isn't a whole
+// word where it matches doesn't hide a shorter one, so "Anna" is still masked in
+// "Anna-Leeds" when "Anna-Lee" is registered too.
+func (v *Vault) Mask(text string) string {
+	v.AddPII(text)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.byValue) == 0 {
+		return text
+	}
+	values := make([]string, 0, len(v.byValue))
+	for value := range v.byValue {
+		values = append(values, value)
+	}
+	slices.SortFunc(values, func(a, b string) int { return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b)) })
+
+	var sb strings.Builder
+	last := 0
+	for i := 0; i < len(text); {
+		value := ""
+		for _, candidate := range values {
+			if strings.HasPrefix(text[i:], candidate) && onWordBoundaries(text, i, i+len(candidate)) {
+				value = candidate
+				break
+			}
+		}
+		if value == "" {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		sb.WriteString(text[last:i])
+		sb.WriteString(v.byValue[value])
+		i += len(value)
+		last = i
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Restore puts the real values back in place of the placeholders in text.
+// Placeholders the vault doesn't know are left alone.
+func (v *Vault) Restore(text string) string {
+	return v.restore(text, func(value string) string { return value })
+}
+
+// RestoreJSON is Restore for a JSON document, escaping the values it puts back
+func (v *Vault) RestoreJSON(data []byte) []byte {
+	return []byte(v.restore(string(data), func(value string) string {
+		quoted, _ := json.Marshal(value)
+		return string(quoted[1 : len(quoted)-1])
+	}))
+}
+
+// RestorePartial restores text that is still being streamed, holding back a trailing
+// placeholder that hasn't fully arrived, so "[PERS" is never shown
+func (v *Vault) RestorePartial(text string) string {
+	if i := strings.LastIndexByte(text, '['); i >= 0 && !strings.Contains(text[i:], "]") && len(text)-i <= len("[NATIONAL_ID_999]") {
+		text = text[:i]
+	}
+	return v.Restore(text)
+}
+
+func (v *Vault) restore(text string, escape func(string) string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.byKey) == 0 {
+		return text
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		m := placeholderPattern.FindStringSubmatch(placeholder)
+		kind := strings.ReplaceAll(strings.ToUpper(m[1]), " ", "_")
+		if value, ok := v.byKey[fmt.Sprintf("%s_%s", kind, m[2])]; ok {
+			return escape(value)
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,132 @@
+package pseudonym
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string // registered with AddNameList, in order
+		text  string
+		want  string
+	}{
+		{
+			name: "nothing registered",
+			text: "Welcome to the team!",
+			want: "Welcome to the team!",
+		},
+		{
+			name:  "names",
+			names: []string{"Jane Doe, Raj"},
+			text:  "Welcome Jane Doe and Raj!",
+			want:  "Welcome [PERSON_1] and [PERSON_2]!",
+		},
+		{
+			name:  "longest value wins",
+			names: []string{"Jane", "Jane Doe"},
+			text:  "Jane Doe, or just Jane.",
+			want:  "[PERSON_2], or just [PERSON_1].",
+		},
+		{
+			name:  "whole words only",
+			names: []string{"Ana"},
+			text:  "Ana met Anastasia.",
+			want:  "[PERSON_1] met Anastasia.",
+		},
+		{
+			name:  "shorter value where a longer one isn't a whole word",
+			names: []string{"Anna", "Anna-Lee"},
+			text:  "Anna-Leeds is not Anna-Lee.",
+			want:  "[PERSON_1]-Leeds is not [PERSON_2].",
+		},
+		{
+			name: "pii found in the text",
+			text: "Mail jane@example.com or call +1 415 555 0100.",
+			want: "Mail [EMAIL_1] or call [PHONE_1].",
+		},
+		{
+			name: "same value keeps its placeholder",
+			text: "a@b.io, a@b.io and c@d.io",
+			want: "[EMAIL_1], [EMAIL_1] and [EMAIL_2]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVault()
+			for _, n := range tt.names {
+				v.AddNameList(n)
+			}
+			if got := v.Mask(tt.text); got != tt.want {
+				t.Errorf("Mask(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	v := NewVault()
+	v.Add(KindPerson, "Jane Doe")
+	v.Add(KindEmail, "jane@example.com")
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"placeholders", "Welcome [PERSON_1]! Write to [EMAIL_1].", "Welcome Jane Doe! Write to jane@example.com."},
+		{"forgiving case and separator", "Welcome [person 1]!", "Welcome Jane Doe!"},
+		{"unknown placeholder is kept", "Welcome [PERSON_2]!", "Welcome [PERSON_2]!"},
+		{"no placeholders", "Welcome!", "Welcome!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.Restore(tt.text); got != tt.want {
+				t.Errorf("Restore(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreRoundTrip(t *testing.T) {
+	v := NewVault()
+	v.AddNames("Farewell party for Raj Patel")
+	text := "Goodbye Raj Patel, keep in touch at raj@example.com."
+	masked := v.Mask(text)
+	if masked == text {
+		t.Fatalf("Mask(%q) changed nothing", text)
+	}
+	if got := v.Restore(masked); got != text {
+		t.Errorf("Restore(Mask(%q)) = %q", text, got)
+	}
+}
+
+func TestRestoreJSON(t *testing.T) {
+	v := NewVault()
+	v.Add(KindPerson, `Jo "JJ" Lee`)
+	got := string(v.RestoreJSON([]byte(`{"note":"Hi [PERSON_1]"}`)))
+	if want := `{"note":"Hi Jo \"JJ\" Lee"}`; got != want {
+		t.Errorf("RestoreJSON = %s, want %s", got, want)
+	}
+}
+
+func TestRestorePartial(t *testing.T) {
+	v := NewVault()
+	v.Add(KindPerson, "Jane")
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"complete placeholder", "Welcome [PERSON_1]", "Welcome Jane"},
+		{"placeholder still arriving", "Welcome [PERS", "Welcome "},
+		{"bracket alone", "Welcome [", "Welcome "},
+		{"restored then partial", "Hi [PERSON_1], meet [PER", "Hi Jane, meet "},
+		{"long bracketed text is not a placeholder", "Note [this is an aside that goes on", "Note [this is an aside that goes on"},
+		{"closed brackets", "Welcome [all]", "Welcome [all]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.RestorePartial(tt.text); got != tt.want {
+				t.Errorf("RestorePartial(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
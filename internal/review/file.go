@@ -0,0 +1,76 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps the queue in a JSON file, so pending items and decisions survive
+// restarts. A missing file starts an empty queue. The file is written before a change
+// takes effect in memory, so a failed write leaves the queue as it was.
+type FileStore struct {
+	mem  *MemoryStore
+	path string
+}
+
+// NewFileStore loads the queue from the JSON array at path
+func NewFileStore(path string) (*FileStore, error) {
+	var seed []Item
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading review queue: %w", err)
+	default:
+		if err := json.Unmarshal(data, &seed); err != nil {
+			return nil, fmt.Errorf("parsing review queue %s: %w", path, err)
+		}
+	}
+	return &FileStore{mem: NewMemoryStore(seed), path: path}, nil
+}
+
+func (s *FileStore) Add(ctx context.Context, it Item) error {
+	return s.mem.add(it, s.write)
+}
+
+func (s *FileStore) Get(ctx context.Context, id string) (Item, error) {
+	return s.mem.Get(ctx, id)
+}
+
+func (s *FileStore) List(ctx context.Context, status string) ([]Item, error) {
+	return s.mem.List(ctx, status)
+}
+
+func (s *FileStore) Decide(ctx context.Context, id string, d Decision) (Item, error) {
+	return s.mem.decide(id, d, s.write)
+}
+
+func (s *FileStore) Watch(ctx context.Context, id string) (<-chan Item, func(), error) {
+	return s.mem.Watch(ctx, id)
+}
+
+// write replaces the file atomically with list. The memory store calls it under its
+// lock, which serializes the writes. Held notes carry personal data, so only the server's
+// user may read the file.
+func (s *FileStore) write(list []Item) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("writing review queue: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("writing review queue: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing review queue: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("writing review queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package review
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStoreDecideWritesFirst(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "queue.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore = %v", err)
+	}
+	it := NewItem(KindApproval, "Welcome!")
+	if err := s.Add(ctx, it); err != nil {
+		t.Fatalf("Add = %v", err)
+	}
+	decided, stop, err := s.Watch(ctx, it.ID)
+	if err != nil {
+		t.Fatalf("Watch = %v", err)
+	}
+	defer stop()
+
+	// a file where the queue's directory should be makes every write fail
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s.path = filepath.Join(blocker, "queue.json")
+	if _, err := s.Decide(ctx, it.ID, Decision{Action: ActionApprove, Reviewer: "ana"}); err == nil {
+		t.Fatal("Decide succeeded with an unwritable file")
+	}
+	if got, _ := s.Get(ctx, it.ID); !got.Pending() {
+		t.Errorf("after a failed write the item is %q, want pending", got.Status)
+	}
+	select {
+	case got := <-decided:
+		t.Fatalf("watcher told of %+v after a failed write", got)
+	default:
+	}
+	if err := s.Add(ctx, NewItem(KindReview, "Hello!")); err == nil {
+		t.Fatal("Add succeeded with an unwritable file")
+	}
+	if list, _ := s.List(ctx, ""); len(list) != 1 {
+		t.Errorf("queue holds %d items after a failed add, want 1", len(list))
+	}
+
+	s.path = path
+	if _, err := s.Decide(ctx, it.ID, Decision{Action: ActionReject, Reviewer: "ana"}); err != nil {
+		t.Fatalf("Decide = %v", err)
+	}
+	if got := <-decided; got.Status != StatusRejected {
+		t.Errorf("watcher got %q, want %q", got.Status, StatusRejected)
+	}
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore = %v", err)
+	}
+	if got, err := reloaded.Get(ctx, it.ID); err != nil || got.Status != StatusRejected || got.Reviewer != "ana" {
+		t.Errorf("reloaded item = %+v, %v; want rejected by ana", got, err)
+	}
+}
+
+func TestFileStorePermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	path := filepath.Join(dir, "queue.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore = %v", err)
+	}
+	if err := s.Add(context.Background(), NewItem(KindReview, "Welcome, Sam!")); err != nil {
+		t.Fatalf("Add = %v", err)
+	}
+	for p, want := range map[string]os.FileMode{dir: 0o700, path: 0o600} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %v, want %v", filepath.Base(p), got, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package review
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the queue in process memory. Items are lost on restart.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	items    map[string]Item
+	watchers map[string][]chan Item // item ID -> requesters waiting for its decision
+}
+
+// NewMemoryStore returns a store holding the given items
+func NewMemoryStore(seed []Item) *MemoryStore {
+	s := &MemoryStore{items: make(map[string]Item, len(seed)), watchers: map[string][]chan Item{}}
+	for _, it := range seed {
+		s.items[it.ID] = clone(it)
+	}
+	return s
+}
+
+func (s *MemoryStore) Add(ctx context.Context, it Item) error {
+	return s.add(it, nil)
+}
+
+// add queues it. persist, when set, is given the queue as it will be with it added; it is
+// only added if persist succeeds.
+func (s *MemoryStore) add(it Item, persist func([]Item) error) error {
+	if it.ID == "" || it.Status != StatusPending {
+		return fmt.Errorf("%w: new items need an ID and must be pending", ErrInvalid)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[it.ID]; ok {
+		return fmt.Errorf("%w: %s is already queued", ErrInvalid, it.ID)
+	}
+	if persist != nil {
+		if err := persist(s.listWith(it)); err != nil {
+			return err
+		}
+	}
+	s.items[it.ID] = clone(it)
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	it, ok := s.items[id]
+	if !ok {
+		return Item{}, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	return clone(it), nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, status string) ([]Item, error) {
+	if status != "" && !slices.Contains(Statuses, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalid, status)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []Item
+	for _, it := range s.items {
+		if status == "" || it.Status == status {
+			list = append(list, clone(it))
+		}
+	}
+	sortItems(list)
+	return list, nil
+}
+
+// listWith returns every item, newest first, with it in place of the item of its ID.
+// The caller holds the lock.
+func (s *MemoryStore) listWith(it Item) []Item {
+	list := []Item{clone(it)}
+	for id, other := range s.items {
+		if id != it.ID {
+			list = append(list, clone(other))
+		}
+	}
+	sortItems(list)
+	return list
+}
+
+func sortItems(list []Item) {
+	slices.SortFunc(list, func(a, b Item) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+func (s *MemoryStore) Decide(ctx context.Context, id string, d Decision) (Item, error) {
+	return s.decide(id, d, nil)
+}
+
+// decide applies d to the item id. persist, when set, is given the queue as it will be
+// with the item decided; the item only changes, and its watchers only hear of it, if
+// persist succeeds.
+func (s *MemoryStore) decide(id string, d Decision, persist func([]Item) error) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.items[id]
+	if !ok {
+		return Item{}, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	if err := it.Apply(d, time.Now()); err != nil {
+		return Item{}, err
+	}
+	if persist != nil {
+		if err := persist(s.listWith(it)); err != nil {
+			return Item{}, err
+		}
+	}
+	s.items[id] = it
+
+	// each watcher's channel has room for the one item it will ever get
+	for _, ch := range s.watchers[id] {
+		ch <- clone(it)
+	}
+	delete(s.watchers, id)
+	return clone(it), nil
+}
+
+func (s *MemoryStore) Watch(ctx context.Context, id string) (<-chan Item, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.items[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	ch := make(chan Item, 1)
+	if !it.Pending() {
+		ch <- clone(it)
+		return ch, func() {}, nil
+	}
+	s.watchers[id] = append(s.watchers[id], ch)
+	stop := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.watchers[id] = slices.DeleteFunc(s.watchers[id], func(c chan Item) bool { return c == ch })
+		if len(s.watchers[id]) == 0 {
+			delete(s.watchers, id)
+		}
+	}
+	return ch, stop, nil
+}
+
+func clone(it Item) Item {
+	it.Reasons = slices.Clone(it.Reasons)
+	it.Categories = slices.Clone(it.Categories)
+	it.State = slices.Clone(it.State)
+	if it.ReviewedAt != nil {
+		t := *it.ReviewedAt
+		it.ReviewedAt = &t
+	}
+	return it
+}
@@ -0,0 +1,166 @@
+// Package review is the queue of notes held for a person to check before they are
+// used: notes the generator marked needs_review, notes moderation held for review and,
+// in approval mode, every Safe flow note, whose flow is paused until it is decided.
+// Reviewers approve, edit or reject each one, and the requester can wait for the decision.
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+var (
+	// ErrNotFound is returned for items that aren't in the queue
+	ErrNotFound = errors.New("review item not found")
+	// ErrInvalid is returned for a decision or query that can't be applied
+	ErrInvalid = errors.New("invalid review request")
+	// ErrDecided is returned when deciding an item a reviewer already decided
+	ErrDecided = errors.New("review item already decided")
+)
+
+// Statuses of a review item
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusEdited   = "edited" // approved with the reviewer's changes
+	StatusRejected = "rejected"
+)
+
+// Statuses lists the statuses an item can have
+var Statuses = []string{StatusPending, StatusApproved, StatusEdited, StatusRejected}
+
+// Kinds of review item
+const (
+	KindReview   = "review"   // the note was returned; the reviewer's decision follows it
+	KindApproval = "approval" // the flow is paused; the note is only released once approved
+)
+
+// Actions a reviewer takes on a pending item
+const (
+	ActionApprove = "approve"
+	ActionEdit    = "edit"
+	ActionReject  = "reject"
+)
+
+// Actions lists the actions a reviewer can take
+var Actions = []string{ActionApprove, ActionEdit, ActionReject}
+
+// Item is a note held for review
+type Item struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	RequestID string    `json:"requestId,omitempty"`
+	Flow      string    `json:"flow,omitempty"`
+	Kind      string    `json:"kind"`    // review or approval
+	Reasons   []string  `json:"reasons"` // why the note is held
+
+	// the note as the flow returned it, with what it was written for
+	Note           string                `json:"note"`
+	OriginalNote   string                `json:"originalNote,omitempty"` // before sanitization, if sanitized
+	Occasion       string                `json:"occasion,omitempty"`
+	Language       string                `json:"language,omitempty"`
+	Tone           string                `json:"tone,omitempty"`
+	ModerationNote string                `json:"moderationNote,omitempty"`
+	Categories     []types.CategoryScore `json:"categories,omitempty"`
+
+	// the paused flow's state, for approval items; opaque to the queue
+	State json.RawMessage `json:"state,omitempty"`
+
+	// set once a reviewer decides
+	FinalNote  string     `json:"finalNote,omitempty"` // the note to use; empty when rejected
+	Reviewer   string     `json:"reviewer,omitempty"`
+	Comment    string     `json:"comment,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+}
+
+// Pending reports whether the item is still waiting for a reviewer
+func (it Item) Pending() bool {
+	return it.Status == StatusPending
+}
+
+// ForRequester returns the item as its requester may see it. The paused flow's state is
+// never shown, and the note only once a reviewer has decided; a rejected approval item's
+// note is never released, so it stays hidden then too.
+func (it Item) ForRequester() Item {
+	it.State = nil
+	if it.Pending() || (it.Kind == KindApproval && it.Status == StatusRejected) {
+		it.Note, it.OriginalNote = "", ""
+	}
+	return it
+}
+
+// NewItem returns a pending item of the given kind for note, with a fresh ID, created now
+func NewItem(kind, note string) Item {
+	return Item{ID: uuid.New().String(), Status: StatusPending, CreatedAt: time.Now().UTC(), Kind: kind, Note: note}
+}
+
+// Decision is what a reviewer decided about an item
+type Decision struct {
+	Action   string `json:"action" form:"action"`             // approve, edit or reject
+	Note     string `json:"note,omitempty" form:"note"`       // the edited note; only for edit
+	Reviewer string `json:"-" form:"-"`                       // who decided, as authenticated
+	Comment  string `json:"comment,omitempty" form:"comment"` // why, for the requester
+}
+
+// Validate normalizes d in place and checks it can be applied
+func (d *Decision) Validate() error {
+	d.Action = strings.ToLower(strings.TrimSpace(d.Action))
+	d.Reviewer = strings.TrimSpace(d.Reviewer)
+	d.Comment = strings.TrimSpace(d.Comment)
+	d.Note = strings.TrimSpace(d.Note)
+	switch {
+	case !slices.Contains(Actions, d.Action):
+		return fmt.Errorf("%w: unknown action %q, want %s", ErrInvalid, d.Action, strings.Join(Actions, ", "))
+	case d.Reviewer == "":
+		return fmt.Errorf("%w: reviewer is required", ErrInvalid)
+	case d.Action == ActionEdit && d.Note == "":
+		return fmt.Errorf("%w: an edit needs the edited note", ErrInvalid)
+	}
+	return nil
+}
+
+// Apply records d on a pending item, decided at now
+func (it *Item) Apply(d Decision, now time.Time) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if !it.Pending() {
+		return fmt.Errorf("%w: %s was %s by %s", ErrDecided, it.ID, it.Status, it.Reviewer)
+	}
+	switch d.Action {
+	case ActionApprove:
+		it.Status, it.FinalNote = StatusApproved, it.Note
+	case ActionEdit:
+		it.Status, it.FinalNote = StatusEdited, d.Note
+	case ActionReject:
+		it.Status, it.FinalNote = StatusRejected, ""
+	}
+	it.Reviewer, it.Comment = d.Reviewer, d.Comment
+	now = now.UTC()
+	it.ReviewedAt = &now
+	return nil
+}
+
+// Store persists the queue. Implementations must be safe for concurrent use.
+type Store interface {
+	// Add queues a new item
+	Add(ctx context.Context, it Item) error
+	// Get returns the item with the given ID, or ErrNotFound
+	Get(ctx context.Context, id string) (Item, error)
+	// List returns the items with the given status, newest first; "" lists them all
+	List(ctx context.Context, status string) ([]Item, error)
+	// Decide applies a reviewer's decision to a pending item and returns the decided item
+	Decide(ctx context.Context, id string, d Decision) (Item, error)
+	// Watch returns a channel that receives the item once it is decided, right away if
+	// it already is. stop releases the channel when the caller gives up waiting.
+	Watch(ctx context.Context, id string) (decided <-chan Item, stop func(), err error)
+}
@@ -0,0 +1,99 @@
+package review
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name      string
+		status    string
+		decision  Decision
+		err       error
+		want      string // status after the decision
+		finalNote string
+	}{
+		{"approve", StatusPending, Decision{Action: "approve", Reviewer: "ana"}, nil, StatusApproved, "Welcome!"},
+		{"action is normalized", StatusPending, Decision{Action: " Approve ", Reviewer: " ana "}, nil, StatusApproved, "Welcome!"},
+		{"edit", StatusPending, Decision{Action: "edit", Note: " Welcome aboard! ", Reviewer: "ana"}, nil, StatusEdited, "Welcome aboard!"},
+		{"reject", StatusPending, Decision{Action: "reject", Reviewer: "ana", Comment: "too casual"}, nil, StatusRejected, ""},
+		{"unknown action", StatusPending, Decision{Action: "ignore", Reviewer: "ana"}, ErrInvalid, StatusPending, ""},
+		{"no reviewer", StatusPending, Decision{Action: "approve", Reviewer: " "}, ErrInvalid, StatusPending, ""},
+		{"edit without a note", StatusPending, Decision{Action: "edit", Reviewer: "ana"}, ErrInvalid, StatusPending, ""},
+		{"already decided", StatusApproved, Decision{Action: "reject", Reviewer: "bo"}, ErrDecided, StatusApproved, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewItem(KindReview, "Welcome!")
+			it.Status = tt.status
+
+			err := it.Apply(tt.decision, now)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Apply = %v, want %v", err, tt.err)
+			}
+			if it.Status != tt.want {
+				t.Errorf("status = %q, want %q", it.Status, tt.want)
+			}
+			if err != nil {
+				if it.Pending() && (it.ReviewedAt != nil || it.Reviewer != "") {
+					t.Errorf("a failed decision changed the item: %+v", it)
+				}
+				return
+			}
+			if it.FinalNote != tt.finalNote {
+				t.Errorf("final note = %q, want %q", it.FinalNote, tt.finalNote)
+			}
+			if it.Reviewer != "ana" || it.Comment != tt.decision.Comment {
+				t.Errorf("reviewer, comment = %q, %q; want ana, %q", it.Reviewer, it.Comment, tt.decision.Comment)
+			}
+			if it.ReviewedAt == nil || !it.ReviewedAt.Equal(now) || it.ReviewedAt.Location() != time.UTC {
+				t.Errorf("reviewed at = %v, want %v in UTC", it.ReviewedAt, now)
+			}
+		})
+	}
+}
+
+func TestForRequester(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		decision *Decision
+		note     string // the note the requester sees
+	}{
+		{"pending review", KindReview, nil, ""},
+		{"pending approval", KindApproval, nil, ""},
+		{"approved", KindReview, &Decision{Action: "approve", Reviewer: "ana"}, "Welcome!"},
+		{"edited", KindReview, &Decision{Action: "edit", Note: "Welcome aboard!", Reviewer: "ana"}, "Welcome!"},
+		{"rejected review", KindReview, &Decision{Action: "reject", Reviewer: "ana"}, "Welcome!"},
+		{"rejected approval", KindApproval, &Decision{Action: "reject", Reviewer: "ana"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewItem(tt.kind, "Welcome!")
+			it.OriginalNote = "Welcome, genius!"
+			it.State = []byte(`{"output":{}}`)
+			if tt.decision != nil {
+				if err := it.Apply(*tt.decision, time.Now()); err != nil {
+					t.Fatalf("Apply = %v", err)
+				}
+			}
+
+			got := it.ForRequester()
+			if got.Note != tt.note {
+				t.Errorf("note = %q, want %q", got.Note, tt.note)
+			}
+			if tt.note == "" && got.OriginalNote != "" {
+				t.Errorf("original note = %q, want it hidden", got.OriginalNote)
+			}
+			if got.State != nil {
+				t.Errorf("state = %s, want it hidden", got.State)
+			}
+			if it.State == nil || it.Note == "" {
+				t.Errorf("ForRequester changed the item itself")
+			}
+		})
+	}
+}
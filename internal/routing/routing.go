@@ -0,0 +1,107 @@
+// Package routing maps each flow and step to the model it runs on, so cheap steps such as
+// interpreting a request can use a small model while generation uses a strong one.
+// Steps are named after the prompts they render: welcome_v3, moderation, interpret and so on.
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Routes is a routing table, read from a JSON file shaped like it. The most specific
+// route wins: a flow's step, then the flow's default, then the step, then Default.
+type Routes struct {
+	Default string                `json:"default,omitempty"` // model for everything not routed otherwise
+	Steps   map[string]string     `json:"steps,omitempty"`   // step -> model, in every flow
+	Flows   map[string]FlowRoutes `json:"flows,omitempty"`   // flow name -> its own routes
+}
+
+// FlowRoutes are the routes of one flow, taking precedence over the global ones
+type FlowRoutes struct {
+	Default string            `json:"default,omitempty"` // model for the flow's steps not routed otherwise
+	Steps   map[string]string `json:"steps,omitempty"`   // step -> model
+}
+
+// Model returns the model the step of the flow runs on, "" if nothing routes it
+func (r *Routes) Model(flow, step string) string {
+	if r == nil {
+		return ""
+	}
+	if f, ok := r.Flows[flow]; ok {
+		if m := f.Steps[step]; m != "" {
+			return m
+		}
+		if f.Default != "" {
+			return f.Default
+		}
+	}
+	if m := r.Steps[step]; m != "" {
+		return m
+	}
+	return r.Default
+}
+
+// Models returns every model the routes name, sorted and without duplicates
+func (r *Routes) Models() []string {
+	if r == nil {
+		return nil
+	}
+	var models []string
+	add := func(m string) {
+		if m != "" {
+			models = append(models, m)
+		}
+	}
+	add(r.Default)
+	for _, m := range r.Steps {
+		add(m)
+	}
+	for _, f := range r.Flows {
+		add(f.Default)
+		for _, m := range f.Steps {
+			add(m)
+		}
+	}
+	slices.Sort(models)
+	return slices.Compact(models)
+}
+
+// Validate checks every flow and step the routes name is one of flows and steps
+func (r *Routes) Validate(flows, steps []string) error {
+	checkSteps := func(where string, routes map[string]string) error {
+		for step := range routes {
+			if !slices.Contains(steps, step) {
+				return fmt.Errorf("model routes: unknown step %q%s, must be one of %s", step, where, strings.Join(steps, ", "))
+			}
+		}
+		return nil
+	}
+	if err := checkSteps("", r.Steps); err != nil {
+		return err
+	}
+	for flow, f := range r.Flows {
+		if !slices.Contains(flows, flow) {
+			return fmt.Errorf("model routes: unknown flow %q, must be one of %s", flow, strings.Join(flows, ", "))
+		}
+		if err := checkSteps(" in flow "+flow, f.Steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFile reads routes from a JSON file shaped like Routes
+func LoadFile(path string) (*Routes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading model routes: %w", err)
+	}
+	var r Routes
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing model routes %s: %w", path, err)
+	}
+	return &r, nil
+}
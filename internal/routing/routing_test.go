@@ -0,0 +1,120 @@
+package routing
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// routes routes every level, so each test can tell which one won
+var routes = &Routes{
+	Default: "default-model",
+	Steps:   map[string]string{"moderation": "step-model", "interpret": "step-model"},
+	Flows: map[string]FlowRoutes{
+		"safe":  {Default: "flow-model", Steps: map[string]string{"moderation": "flow-step-model"}},
+		"smart": {Steps: map[string]string{"judge": "smart-judge-model"}},
+	},
+}
+
+func TestModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes *Routes
+		flow   string
+		step   string
+		want   string
+	}{
+		{"flow step", routes, "safe", "moderation", "flow-step-model"},
+		{"flow default over step", routes, "safe", "interpret", "flow-model"},
+		{"flow default", routes, "safe", "welcome_v3", "flow-model"},
+		{"step of a flow without a default", routes, "smart", "interpret", "step-model"},
+		{"flow step without a flow default", routes, "smart", "judge", "smart-judge-model"},
+		{"default of a flow without a default", routes, "smart", "welcome_v3", "default-model"},
+		{"step of an unrouted flow", routes, "v3", "moderation", "step-model"},
+		{"default", routes, "v3", "welcome_v3", "default-model"},
+		{"no flow", routes, "", "interpret", "step-model"},
+		{"nothing routed", &Routes{}, "safe", "moderation", ""},
+		{"no routes", nil, "safe", "moderation", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.routes.Model(tt.flow, tt.step); got != tt.want {
+				t.Errorf("Model(%q, %q) = %q, want %q", tt.flow, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModels(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes *Routes
+		want   []string
+	}{
+		{"every level, once each", routes, []string{"default-model", "flow-model", "flow-step-model", "smart-judge-model", "step-model"}},
+		{"empty models are left out", &Routes{Steps: map[string]string{"judge": ""}, Flows: map[string]FlowRoutes{"safe": {}}}, nil},
+		{"no routes", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.routes.Models(); !slices.Equal(got, tt.want) {
+				t.Errorf("Models() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	flows := []string{"safe", "smart", "v3"}
+	steps := []string{"welcome_v3", "moderation", "interpret", "judge"}
+	tests := []struct {
+		name    string
+		routes  *Routes
+		wantErr bool
+	}{
+		{"known flows and steps", routes, false},
+		{"empty", &Routes{}, false},
+		{"unknown step", &Routes{Steps: map[string]string{"summarize": "m"}}, true},
+		{"unknown flow", &Routes{Flows: map[string]FlowRoutes{"fast": {Default: "m"}}}, true},
+		{"unknown step in a flow", &Routes{Flows: map[string]FlowRoutes{"safe": {Steps: map[string]string{"summarize": "m"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.routes.Validate(flows, steps)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "routes.json")
+	data := `{"default": "default-model", "steps": {"moderation": "step-model"}, "flows": {"safe": {"steps": {"moderation": "flow-step-model"}}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile = %v", err)
+	}
+	if got := r.Model("safe", "moderation"); got != "flow-step-model" {
+		t.Errorf("Model(safe, moderation) = %q, want flow-step-model", got)
+	}
+	if got := r.Model("v3", "judge"); got != "default-model" {
+		t.Errorf("Model(v3, judge) = %q, want default-model", got)
+	}
+
+	if _, err := LoadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("LoadFile of a missing file succeeded, want an error")
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Errorf("LoadFile of invalid JSON succeeded, want an error")
+	}
+}
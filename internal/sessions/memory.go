@@ -0,0 +1,95 @@
+package sessions
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps sessions in process memory. Sessions are lost on restart.
+type MemoryStore struct {
+	mu              sync.RWMutex
+	sessions        map[string]*Session
+	ttl             time.Duration
+	cleanupInterval time.Duration
+}
+
+// NewMemoryStore returns a store whose sessions expire ttl after their last save.
+// Expired sessions are removed every cleanupInterval.
+func NewMemoryStore(ttl, cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		sessions:        make(map[string]*Session),
+		ttl:             ttl,
+		cleanupInterval: cleanupInterval,
+	}
+	// Start background cleanup goroutine
+	go s.cleanupLoop()
+	return s
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok || s.expired(session, time.Now()) {
+		return nil, ErrNotFound
+	}
+	return session.clone(), nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, session *Session) error {
+	stored := session.clone()
+	stored.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[stored.ID] = stored
+	session.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+func (s *MemoryStore) expired(session *Session, now time.Time) bool {
+	return now.Sub(session.UpdatedAt) > s.ttl
+}
+
+// cleanupLoop periodically removes expired sessions
+func (s *MemoryStore) cleanupLoop() {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.cleanup()
+	}
+}
+
+// cleanup removes sessions that haven't been saved within the TTL
+func (s *MemoryStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+
+	for id, session := range s.sessions {
+		if s.expired(session, now) {
+			delete(s.sessions, id)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		slog.Info("session cleanup completed",
+			slog.Int("removed_sessions", removed),
+			slog.Int("active_sessions", len(s.sessions)),
+		)
+	}
+}
@@ -0,0 +1,93 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(50*time.Millisecond, time.Hour)
+
+	session := New()
+	session.AddTurn(RoleUser, "welcome Sam to the team")
+	if err := s.Save(ctx, session); err != nil {
+		t.Fatalf("Save = %v", err)
+	}
+	got, err := s.Get(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Get = %v", err)
+	}
+	if len(got.Turns) != 1 || got.Turns[0].Text != "welcome Sam to the team" {
+		t.Errorf("turns = %+v, want the saved turn", got.Turns)
+	}
+
+	// a copy: changing it doesn't change the stored session
+	got.AddTurn(RoleAssistant, "Welcome, Sam!")
+	if again, _ := s.Get(ctx, session.ID); len(again.Turns) != 1 {
+		t.Errorf("stored turns = %d after changing a copy, want 1", len(again.Turns))
+	}
+
+	// saving refreshes the expiry
+	time.Sleep(30 * time.Millisecond)
+	if err := s.Save(ctx, got); err != nil {
+		t.Fatalf("Save = %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := s.Get(ctx, session.ID); err != nil {
+		t.Errorf("Get after a refreshing save = %v, want the session", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := s.Get(ctx, session.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after the TTL = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestMemoryStoreCleanup(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(50*time.Millisecond, time.Hour)
+
+	stale, fresh := New(), New()
+	if err := s.Save(ctx, stale); err != nil {
+		t.Fatalf("Save = %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := s.Save(ctx, fresh); err != nil {
+		t.Fatalf("Save = %v", err)
+	}
+
+	s.cleanup()
+	s.mu.RLock()
+	_, staleKept := s.sessions[stale.ID]
+	_, freshKept := s.sessions[fresh.ID]
+	s.mu.RUnlock()
+	if staleKept || !freshKept {
+		t.Errorf("after cleanup stale kept = %v, fresh kept = %v; want only the fresh session", staleKept, freshKept)
+	}
+
+	if err := s.Delete(ctx, fresh.ID); err != nil {
+		t.Fatalf("Delete = %v", err)
+	}
+	if _, err := s.Get(ctx, fresh.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want %v", err, ErrNotFound)
+	}
+	if err := s.Delete(ctx, "unknown"); err != nil {
+		t.Errorf("Delete of an unknown session = %v, want nil", err)
+	}
+}
+
+func TestRecentTurns(t *testing.T) {
+	s := New()
+	for _, text := range []string{"one", "two", "three"} {
+		s.AddTurn(RoleUser, text)
+	}
+	if got := s.RecentTurns(2); len(got) != 2 || got[0].Text != "two" || got[1].Text != "three" {
+		t.Errorf("RecentTurns(2) = %+v, want the last two", got)
+	}
+	if got := s.RecentTurns(5); len(got) != 3 {
+		t.Errorf("RecentTurns(5) = %d turns, want all 3", len(got))
+	}
+}
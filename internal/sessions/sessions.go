@@ -0,0 +1,77 @@
+// Package sessions keeps Smart flow conversations between requests, so follow-ups like
+// "same but in Spanish" can amend the previous interpretation.
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// ErrNotFound is returned by Store.Get for unknown or expired sessions
+var ErrNotFound = errors.New("session not found")
+
+// Turn roles
+const (
+	RoleUser      = "user"      // a description the user typed
+	RoleAssistant = "assistant" // a note generated in reply
+)
+
+// Session is a Smart flow conversation
+type Session struct {
+	ID        string                  `json:"id"`
+	Turns     []types.SessionTurn     `json:"turns"`
+	LastInput *types.WelcomeNoteInput `json:"lastInput,omitempty"` // interpretation of the latest description
+	Notes     []string                `json:"notes,omitempty"`     // generated notes, oldest first
+	CreatedAt time.Time               `json:"createdAt"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+// Store persists sessions. Implementations expire sessions that haven't been
+// saved for longer than their TTL and must be safe for concurrent use.
+type Store interface {
+	// Get returns a copy of the session, or ErrNotFound
+	Get(ctx context.Context, id string) (*Session, error)
+	// Save creates or replaces the session and refreshes its expiry
+	Save(ctx context.Context, s *Session) error
+	// Delete removes the session; deleting an unknown session is not an error
+	Delete(ctx context.Context, id string) error
+}
+
+// New returns an empty session with a fresh ID
+func New() *Session {
+	now := time.Now()
+	return &Session{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// AddTurn appends a turn to the conversation
+func (s *Session) AddTurn(role, text string) {
+	s.Turns = append(s.Turns, types.SessionTurn{Role: role, Text: text, At: time.Now()})
+}
+
+// RecentTurns returns at most the last n turns
+func (s *Session) RecentTurns(n int) []types.SessionTurn {
+	if len(s.Turns) <= n {
+		return s.Turns
+	}
+	return s.Turns[len(s.Turns)-n:]
+}
+
+// clone returns a deep copy, so stored sessions can't be changed by callers
+func (s *Session) clone() *Session {
+	c := *s
+	c.Turns = append([]types.SessionTurn(nil), s.Turns...)
+	c.Notes = append([]string(nil), s.Notes...)
+	if s.LastInput != nil {
+		input := *s.LastInput
+		c.LastInput = &input
+	}
+	return &c
+}
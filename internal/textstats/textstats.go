@@ -0,0 +1,107 @@
+// Package textstats measures generated notes locally, without asking the model.
+package textstats
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Counts is the measured size of a text
+type Counts struct {
+	Sentences int `json:"sentences"`
+	Words     int `json:"words"`
+}
+
+// Abbreviations that end in a period without ending the sentence, by lowercase language name
+var abbreviations = map[string]map[string]bool{
+	"english": set("mr", "mrs", "ms", "dr", "st", "jr", "sr", "prof", "e.g", "i.e", "etc", "vs", "approx"),
+	"spanish": set("sr", "sra", "srta", "dr", "dra", "ud", "uds", "etc", "p.ej"),
+	"french":  set("m", "mme", "mlle", "dr", "pr", "etc", "p.ex"),
+	"german":  set("hr", "fr", "dr", "prof", "z.b", "bzw", "usw", "etc", "ca", "d.h"),
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// isTerminator reports whether r can end a sentence. Besides Latin punctuation this
+// covers the Devanagari danda used in Hindi and full-width CJK punctuation.
+func isTerminator(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '।', '॥', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+// isWordRune reports whether r is part of a word. Marks are included because
+// Indic scripts such as Telugu and Devanagari write vowels as combining marks.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsNumber(r)
+}
+
+// Count returns the number of sentences and words in text. language is a language
+// name such as "english" or "Hindi"; it selects the abbreviations that don't end a sentence.
+func Count(text, language string) Counts {
+	abbrevs := abbreviations[strings.ToLower(strings.TrimSpace(language))]
+	runes := []rune(text)
+
+	var counts Counts
+	inWord := false
+	inSentence := false
+	var word strings.Builder // current or most recent word, lowercased, with inner periods
+
+	for i, r := range runes {
+		switch {
+		case isWordRune(r):
+			if !inWord {
+				counts.Words++
+				word.Reset()
+			}
+			inWord = true
+			inSentence = true
+			word.WriteRune(unicode.ToLower(r))
+
+		case (r == '\'' || r == '’' || r == '-') && inWord && i+1 < len(runes) && isWordRune(runes[i+1]):
+			// apostrophes and hyphens inside a word: don't, well-known
+
+		case isTerminator(r):
+			if r == '.' && !endsSentence(runes, i, word.String(), abbrevs) {
+				// keep inner periods so abbreviations like "e.g" can be matched
+				if inWord {
+					word.WriteRune('.')
+				}
+				continue
+			}
+			inWord = false
+			if inSentence {
+				counts.Sentences++
+				inSentence = false
+			}
+
+		default:
+			inWord = false
+		}
+	}
+	if inSentence {
+		counts.Sentences++
+	}
+	return counts
+}
+
+// endsSentence reports whether the period at runes[i] ends a sentence
+func endsSentence(runes []rune, i int, word string, abbrevs map[string]bool) bool {
+	prevDigit := i > 0 && unicode.IsDigit(runes[i-1])
+	nextDigit := i+1 < len(runes) && unicode.IsDigit(runes[i+1])
+	if prevDigit && nextDigit {
+		return false // decimal number or time, e.g. 3.5 or 12.30
+	}
+	if i+1 < len(runes) && isWordRune(runes[i+1]) {
+		return false // period inside a token, e.g. e.g or a domain name
+	}
+	return !abbrevs[word]
+}
@@ -0,0 +1,33 @@
+package textstats
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		want     Counts
+	}{
+		{"empty", "", "english", Counts{}},
+		{"one sentence without terminator", "Welcome to the team", "english", Counts{Sentences: 1, Words: 4}},
+		{"several terminators", "Welcome! We're glad you're here. Ready?", "english", Counts{Sentences: 3, Words: 6}},
+		{"repeated punctuation", "Welcome aboard!!! See you soon...", "english", Counts{Sentences: 2, Words: 5}},
+		{"abbreviation", "Please welcome Dr. Smith to the lab.", "english", Counts{Sentences: 1, Words: 7}},
+		{"abbreviation language is case insensitive", "Welcome Mr. Lee.", " English ", Counts{Sentences: 1, Words: 3}},
+		{"abbreviation of another language", "Welcome Mr. Lee.", "spanish", Counts{Sentences: 2, Words: 3}},
+		{"inner periods", "Bring snacks, e.g. cookies.", "english", Counts{Sentences: 1, Words: 4}},
+		{"decimal and time", "We start at 9.30 with 2.5 hours of fun.", "english", Counts{Sentences: 1, Words: 9}},
+		{"domain name is one word", "Visit example.com today.", "english", Counts{Sentences: 1, Words: 3}},
+		{"hyphen and apostrophe", "A well-known team's welcome.", "english", Counts{Sentences: 1, Words: 4}},
+		{"devanagari danda", "आपका स्वागत है। हमें खुशी है।", "hindi", Counts{Sentences: 2, Words: 6}},
+		{"full-width punctuation", "欢迎。很高兴见到你！", "chinese", Counts{Sentences: 2, Words: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Count(tt.text, tt.language); got != tt.want {
+				t.Errorf("Count(%q, %q) = %+v, want %+v", tt.text, tt.language, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package tones
+
+// Defaults returns the built-in tones, used to seed stores
+func Defaults() []Tone {
+	return []Tone{
+		{
+			Name:        "formal",
+			Description: "Professional and respectful, suitable for business or formal events",
+			Examples:    []string{"It is our great pleasure to welcome you."},
+			SafetyClass: SafetySafe,
+			LocaleGuidance: map[string]string{
+				"de": "Use the formal Sie form of address.",
+				"fr": "Use vous and formal salutations.",
+				"es": "Use usted.",
+				"ja": "Use keigo (sonkeigo and kenjōgo).",
+				"hi": "Use आप and respectful verb forms.",
+			},
+		},
+		{
+			Name:        "casual",
+			Description: "Friendly and relaxed, perfect for informal gatherings",
+			Examples:    []string{"Hey, great to have you here!"},
+			SafetyClass: SafetySafe,
+			LocaleGuidance: map[string]string{
+				"de": "Use du.",
+				"fr": "Use tu.",
+				"es": "Use tú (vos is fine for es-AR).",
+			},
+		},
+		{
+			Name:        "warm",
+			Description: "Heartfelt and affectionate, emphasizing emotional connection",
+			Examples:    []string{"We are so happy you are here, and we can't wait to get to know you."},
+			SafetyClass: SafetySafe,
+		},
+		{
+			Name:        "humorous",
+			Description: "Light-hearted and fun, with tasteful humor",
+			Examples:    []string{"Welcome aboard! The coffee machine has been told to expect you."},
+			SafetyClass: SafetySafe,
+			Aliases:     []string{"funny"},
+		},
+		{
+			Name:        "professional",
+			Description: "Business-appropriate while remaining welcoming",
+			Examples:    []string{"Welcome to the team. We look forward to working with you."},
+			SafetyClass: SafetySafe,
+		},
+		{
+			Name:        "poetic",
+			Description: "Elegant and lyrical, with beautiful imagery and flow",
+			Examples:    []string{"Like the first light of morning, your arrival brightens our days."},
+			SafetyClass: SafetySafe,
+		},
+		{
+			Name:        "playful",
+			Description: "Cheerful and teasing in a friendly way, full of energy",
+			Examples:    []string{"Ready or not, you're officially one of us now!"},
+			SafetyClass: SafetySafe,
+		},
+		{
+			Name:        "sarcastic",
+			Description: "Dry, mocking tone with subtle digs",
+			Examples:    []string{"Oh wonderful, another person to explain the printer to."},
+			SafetyClass: SafetySensitive,
+		},
+		{
+			Name:        "roast",
+			Description: "Affectionate roast that pokes fun at the recipient",
+			Examples:    []string{"Welcome! We hear your spreadsheets are almost as legendary as your coffee breaks."},
+			SafetyClass: SafetySensitive,
+			Aliases:     []string{"roasting", "mocking"},
+		},
+		{
+			Name:        "passive",
+			Description: "Passive-aggressive with backhanded welcomes",
+			Examples:    []string{"Welcome! I'm sure you'll get the hang of things eventually."},
+			SafetyClass: SafetySensitive,
+			Aliases:     []string{"passive-aggressive"},
+		},
+		{
+			Name:        "gloomy",
+			Description: "Negative, bleak outlook that undercuts the welcome",
+			Examples:    []string{"Welcome, I suppose. It won't get any better from here."},
+			SafetyClass: SafetySensitive,
+			Aliases:     []string{"dark-humor"},
+		},
+		{
+			Name:        "insulting",
+			Description: "Bluntly offensive or rude remarks",
+			SafetyClass: SafetyOffensive,
+		},
+		{
+			Name:        "aggressive",
+			Description: "Confrontational wording with sharp jabs",
+			SafetyClass: SafetyOffensive,
+			Aliases:     []string{"angry", "frustrated"},
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package tones
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps tones in a JSON file, so tones added through the API survive restarts.
+// A missing file is created from the built-in defaults.
+type FileStore struct {
+	mu   sync.Mutex // serializes writes to the file
+	mem  *MemoryStore
+	path string
+}
+
+// NewFileStore loads tones from the JSON array at path
+func NewFileStore(path string) (*FileStore, error) {
+	seed := Defaults()
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading tones: %w", err)
+	default:
+		seed = nil
+		if err := json.Unmarshal(data, &seed); err != nil {
+			return nil, fmt.Errorf("parsing tones %s: %w", path, err)
+		}
+	}
+
+	mem, err := NewMemoryStore(seed)
+	if err != nil {
+		return nil, fmt.Errorf("loading tones %s: %w", path, err)
+	}
+	s := &FileStore{mem: mem, path: path}
+	if data == nil {
+		if err := s.write(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *FileStore) List(ctx context.Context) ([]Tone, error) {
+	return s.mem.List(ctx)
+}
+
+func (s *FileStore) Get(ctx context.Context, name string) (Tone, error) {
+	return s.mem.Get(ctx, name)
+}
+
+func (s *FileStore) Save(ctx context.Context, t Tone) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.Save(ctx, t); err != nil {
+		return err
+	}
+	return s.write(ctx)
+}
+
+func (s *FileStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.Delete(ctx, name); err != nil {
+		return err
+	}
+	return s.write(ctx)
+}
+
+// write replaces the file atomically with the current tones
+func (s *FileStore) write(ctx context.Context) error {
+	list, err := s.mem.List(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("writing tones: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("writing tones: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing tones: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("writing tones: %w", err)
+	}
+	return nil
+}
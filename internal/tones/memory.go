@@ -0,0 +1,77 @@
+package tones
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps tones in process memory. Changes are lost on restart.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	tones map[string]Tone
+}
+
+// NewMemoryStore returns a store seeded with the given tones
+func NewMemoryStore(seed []Tone) (*MemoryStore, error) {
+	s := &MemoryStore{tones: make(map[string]Tone, len(seed))}
+	for _, t := range seed {
+		t = clone(t)
+		if err := Validate(&t); err != nil {
+			return nil, err
+		}
+		s.tones[t.Name] = t
+	}
+	return s, nil
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]Tone, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Tone, 0, len(s.tones))
+	for _, t := range s.tones {
+		list = append(list, clone(t))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, name string) (Tone, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tones[NormalizeName(name)]
+	if !ok {
+		return Tone{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return clone(t), nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, t Tone) error {
+	t = clone(t)
+	if err := Validate(&t); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tones[t.Name] = t
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = NormalizeName(name)
+	if name == DefaultTone {
+		return fmt.Errorf("%w: %q is the default tone and can't be deleted", ErrInvalid, name)
+	}
+	if _, ok := s.tones[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	delete(s.tones, name)
+	return nil
+}
@@ -0,0 +1,130 @@
+package tones
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreCRUD(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store {
+			s, err := NewMemoryStore(Defaults())
+			if err != nil {
+				t.Fatalf("NewMemoryStore = %v", err)
+			}
+			return s
+		},
+		"file": func(t *testing.T) Store {
+			s, err := NewFileStore(filepath.Join(t.TempDir(), "tones.json"))
+			if err != nil {
+				t.Fatalf("NewFileStore = %v", err)
+			}
+			return s
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := open(t)
+
+			all, err := s.List(ctx)
+			if err != nil || len(all) != len(Defaults()) {
+				t.Fatalf("List = %d tones, %v; want the %d defaults", len(all), err, len(Defaults()))
+			}
+			for i := 1; i < len(all); i++ {
+				if all[i-1].Name >= all[i].Name {
+					t.Errorf("List isn't sorted by name: %s before %s", all[i-1].Name, all[i].Name)
+				}
+			}
+
+			// created normalized, with the default safety class
+			if err := s.Save(ctx, Tone{Name: " Cheerful ", Description: "Bright and upbeat", Aliases: []string{"Sunny"}}); err != nil {
+				t.Fatalf("Save = %v", err)
+			}
+			got, err := s.Get(ctx, "cheerful")
+			if err != nil || got.SafetyClass != SafetySafe || got.Aliases[0] != "sunny" {
+				t.Errorf("Get = %+v, %v; want cheerful, safe, aliased sunny", got, err)
+			}
+			if byAlias, err := Resolve(ctx, s, "SUNNY"); err != nil || byAlias.Name != "cheerful" {
+				t.Errorf("Resolve(SUNNY) = %+v, %v; want cheerful", byAlias, err)
+			}
+
+			// a copy: changing it doesn't change the store
+			got.Aliases[0] = "changed"
+			if again, _ := s.Get(ctx, "cheerful"); again.Aliases[0] != "sunny" {
+				t.Errorf("Get shares the stored aliases")
+			}
+
+			// replaced
+			if err := s.Save(ctx, Tone{Name: "cheerful", Description: "Very bright", SafetyClass: SafetySensitive}); err != nil {
+				t.Fatalf("Save = %v", err)
+			}
+			if got, _ := s.Get(ctx, "cheerful"); got.Description != "Very bright" || got.SafetyClass != SafetySensitive {
+				t.Errorf("Get after replacing = %+v", got)
+			}
+
+			for _, bad := range []Tone{
+				{Name: "no description"},
+				{Name: "ok", Description: "d", SafetyClass: "spicy"},
+				{Name: "ok", Description: "d", Aliases: []string{"bad alias"}},
+			} {
+				if err := s.Save(ctx, bad); !errors.Is(err, ErrInvalid) {
+					t.Errorf("Save(%+v) = %v, want %v", bad, err, ErrInvalid)
+				}
+			}
+
+			if err := s.Delete(ctx, "cheerful"); err != nil {
+				t.Fatalf("Delete = %v", err)
+			}
+			if _, err := s.Get(ctx, "cheerful"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get after Delete = %v, want %v", err, ErrNotFound)
+			}
+			if err := s.Delete(ctx, "cheerful"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("deleting twice = %v, want %v", err, ErrNotFound)
+			}
+			if err := s.Delete(ctx, DefaultTone); !errors.Is(err, ErrInvalid) {
+				t.Errorf("deleting the default tone = %v, want %v", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestFileStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "tones.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("the defaults weren't written: %v", err)
+	}
+	if err := s.Save(ctx, Tone{Name: "cheerful", Description: "Bright and upbeat"}); err != nil {
+		t.Fatalf("Save = %v", err)
+	}
+	if err := s.Delete(ctx, "roast"); err != nil {
+		t.Fatalf("Delete = %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopening = %v", err)
+	}
+	if _, err := reopened.Get(ctx, "cheerful"); err != nil {
+		t.Errorf("the saved tone is lost on reopening: %v", err)
+	}
+	if _, err := reopened.Get(ctx, "roast"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the deleted tone is back on reopening: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"name": "Bad Name"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Errorf("opening a file with an invalid tone succeeded, want an error")
+	}
+}
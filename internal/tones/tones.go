@@ -0,0 +1,133 @@
+// Package tones is the registry of tones notes can be written in. Each tone carries
+// the guidance the prompts need to express it, and a safety class policies can act on.
+package tones
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned for tones that aren't in the registry
+	ErrNotFound = errors.New("tone not found")
+	// ErrInvalid is returned by Validate and Store.Save for malformed tones
+	ErrInvalid = errors.New("invalid tone")
+)
+
+// Safety classes, from least to most likely to need moderation
+const (
+	SafetySafe      = "safe"      // fine for any audience
+	SafetySensitive = "sensitive" // edgy: sarcasm, roasts, negativity
+	SafetyOffensive = "offensive" // written to hurt; expect moderation to sanitize or block
+)
+
+// DefaultTone is the tone of requests that give none. It can't be deleted.
+const DefaultTone = "warm"
+
+// SafetyClasses lists the safety classes in increasing order of risk
+var SafetyClasses = []string{SafetySafe, SafetySensitive, SafetyOffensive}
+
+// Tone is a registered tone
+type Tone struct {
+	Name        string   `json:"name"`               // lowercase identifier, e.g. "playful"
+	Description string   `json:"description"`        // what the tone sounds like
+	Examples    []string `json:"examples,omitempty"` // sentences written in the tone
+	SafetyClass string   `json:"safetyClass"`        // safe, sensitive or offensive
+	Aliases     []string `json:"aliases,omitempty"`  // other names the tone is requested by, e.g. "passive-aggressive"
+
+	// how to express the tone in a locale, keyed by BCP-47 tag ("en-GB") or base language ("ja")
+	LocaleGuidance map[string]string `json:"localeGuidance,omitempty"`
+}
+
+// Store persists tones and must be safe for concurrent use
+type Store interface {
+	// List returns all tones sorted by name
+	List(ctx context.Context) ([]Tone, error)
+	// Get returns the tone with the given name, or ErrNotFound
+	Get(ctx context.Context, name string) (Tone, error)
+	// Save validates and creates or replaces the tone
+	Save(ctx context.Context, t Tone) error
+	// Delete removes the tone, or returns ErrNotFound. Deleting DefaultTone is ErrInvalid.
+	Delete(ctx context.Context, name string) error
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// NormalizeName lowercases and trims a tone name or alias
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Validate normalizes t in place and checks it is well formed
+func Validate(t *Tone) error {
+	t.Name = NormalizeName(t.Name)
+	t.Description = strings.TrimSpace(t.Description)
+	if t.SafetyClass == "" {
+		t.SafetyClass = SafetySafe
+	}
+	for i, alias := range t.Aliases {
+		t.Aliases[i] = NormalizeName(alias)
+	}
+
+	switch {
+	case !namePattern.MatchString(t.Name):
+		return fmt.Errorf("%w: name %q must be 1–32 lowercase letters, digits or dashes", ErrInvalid, t.Name)
+	case t.Description == "":
+		return fmt.Errorf("%w: %s: description is required", ErrInvalid, t.Name)
+	case !slices.Contains(SafetyClasses, t.SafetyClass):
+		return fmt.Errorf("%w: %s: safety class must be one of %s", ErrInvalid, t.Name, strings.Join(SafetyClasses, ", "))
+	}
+	for _, alias := range t.Aliases {
+		if !namePattern.MatchString(alias) {
+			return fmt.Errorf("%w: %s: alias %q must be lowercase letters, digits or dashes", ErrInvalid, t.Name, alias)
+		}
+	}
+	return nil
+}
+
+// GuidanceFor returns the tone's guidance for a BCP-47 locale, falling back from
+// the full tag to its base language
+func (t Tone) GuidanceFor(locale string) string {
+	if g, ok := t.LocaleGuidance[locale]; ok {
+		return g
+	}
+	base, _, _ := strings.Cut(locale, "-")
+	return t.LocaleGuidance[base]
+}
+
+// Resolve finds a tone by name or alias
+func Resolve(ctx context.Context, s Store, name string) (Tone, error) {
+	name = NormalizeName(name)
+	t, err := s.Get(ctx, name)
+	if !errors.Is(err, ErrNotFound) {
+		return t, err
+	}
+
+	all, err := s.List(ctx)
+	if err != nil {
+		return Tone{}, err
+	}
+	for _, t := range all {
+		if slices.Contains(t.Aliases, name) {
+			return t, nil
+		}
+	}
+	return Tone{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
+
+func clone(t Tone) Tone {
+	t.Examples = slices.Clone(t.Examples)
+	t.Aliases = slices.Clone(t.Aliases)
+	if t.LocaleGuidance != nil {
+		g := make(map[string]string, len(t.LocaleGuidance))
+		for k, v := range t.LocaleGuidance {
+			g[k] = v
+		}
+		t.LocaleGuidance = g
+	}
+	return t
+}
@@ -1,12 +1,32 @@
 package types
 
+import "time"
+
 type WelcomeNoteInput struct {
-	Occasion string `json:"occasion" form:"occasion" binding:"required" jsonschema:"description=the occasion to generate the welcome note for"`
-	Language string `json:"language,omitempty" form:"language" jsonschema:"description=the language of choice for welcome note generation"`
-	Length   string `json:"length,omitempty" form:"length" jsonschema:"description=whether the welcome note should be short or medium"`
-	Tone     string `json:"tone,omitempty" form:"tone" jsonschema:"description=the tone of the welcome note: formal, casual, warm, humorous, professional, or poetic or insulting or sarcastic"`
+	Occasion string             `json:"occasion" form:"occasion" binding:"required" jsonschema:"description=the occasion to generate the welcome note for"`
+	Language string             `json:"language,omitempty" form:"language" jsonschema:"description=the language of choice for welcome note generation as a name or BCP-47 tag"`
+	Locale   string             `json:"locale,omitempty" form:"locale" jsonschema:"description=BCP-47 locale such as en-GB or pt-BR; takes precedence over language"`
+	Length   string             `json:"length,omitempty" form:"length" jsonschema:"description=whether the welcome note should be short or medium"`
+	Tone     string             `json:"tone,omitempty" form:"tone" jsonschema:"description=the tone of the welcome note: formal, casual, warm, humorous, professional, or poetic or insulting or sarcastic"`
+	ToneMix  map[string]float64 `json:"toneMix,omitempty" form:"toneMix" jsonschema:"description=optional weighted blend of tones such as professional 0.7 and humorous 0.3; takes precedence over tone"`
+
+	// Optional personalization
+	Recipients   string `json:"recipients,omitempty" form:"recipients" jsonschema:"description=name or list of names of the people being welcomed"`
+	Sender       string `json:"sender,omitempty" form:"sender" jsonschema:"description=name of the host or sender the note is written from"`
+	Relationship string `json:"relationship,omitempty" form:"relationship" jsonschema:"description=relationship between sender and recipients such as manager or teammate"`
+	Organization string `json:"organization,omitempty" form:"organization" jsonschema:"description=team or organization doing the welcoming"`
+	Signature    string `json:"signature,omitempty" form:"signature" jsonschema:"description=signature block to end the note with verbatim"`
+
+	// Number of notes to generate and rank (V3 and Safe only); 0 or 1 generates a single note
+	Candidates int `json:"candidates,omitempty" form:"candidates" jsonschema:"description=number of candidate notes to generate and rank (1 to 5)"`
+
+	// Model to write the note with instead of the routed one; it must be allowed by the deployment's policy
+	Model string `json:"model,omitempty" form:"model" jsonschema:"description=model to write the note with such as googleai/gemini-2.5-pro; empty uses the configured routes"`
 }
 
+// MaxCandidates caps WelcomeNoteInput.Candidates
+const MaxCandidates = 5
+
 type WelcomeNoteOutput struct {
 	Note     string `json:"note"`
 	Occasion string `json:"occasion,omitempty"`
@@ -15,6 +35,15 @@ type WelcomeNoteOutput struct {
 	Tone     string `json:"tone,omitempty"`
 }
 
+// WelcomeNoteChunk is a partial welcome note emitted by the streaming flows.
+type WelcomeNoteChunk struct {
+	Delta string `json:"delta"` // text added since the previous chunk
+	Note  string `json:"note"`  // note text accumulated so far
+
+	// generation attempt the chunk belongs to; a new attempt restarts Note
+	Attempt int `json:"attempt,omitempty"`
+}
+
 // WelcomeNoteV3Output represents the structured JSON output for V3.
 type WelcomeNoteV3Output struct {
 	Note     string                `json:"note"`     // final welcome note
@@ -23,6 +52,40 @@ type WelcomeNoteV3Output struct {
 	Length   string                `json:"length"`
 	Tone     string                `json:"tone"`
 	Metadata WelcomeNoteV3Metadata `json:"metadata"` // nested metadata block
+
+	// all generated notes, best first; only set when more than one candidate was requested
+	Candidates []WelcomeNoteCandidate `json:"candidates,omitempty"`
+}
+
+// WelcomeNoteCandidate is one of several generated notes, scored against the request.
+type WelcomeNoteCandidate struct {
+	Rank          int                   `json:"rank"` // 1 = best
+	Note          string                `json:"note"`
+	Metadata      WelcomeNoteV3Metadata `json:"metadata"`
+	Score         float64               `json:"score"` // overall 0–1
+	Scores        CandidateScores       `json:"scores"`
+	JudgeComments string                `json:"judgeComments,omitempty"`
+
+	// safety info, set by the Safe flow
+	Blocked        bool             `json:"blocked,omitempty"`
+	ModerationNote string           `json:"moderationNote,omitempty"`
+	OriginalNote   string           `json:"originalNote,omitempty"` // only set if sanitized
+	Categories     []CategoryScore  `json:"categories,omitempty"`
+	ChangedSpans   []ChangedSpan    `json:"changedSpans,omitempty"`
+	Decision       string           `json:"decision,omitempty"`
+	DecisionReason string           `json:"decisionReason,omitempty"`
+	Votes          []ModerationVote `json:"votes,omitempty"` // only set when an ensemble moderated the note
+	Pivot          *ModerationPivot `json:"pivot,omitempty"` // only set for a note that isn't in English
+}
+
+// CandidateScores breaks a candidate's score down. Tone, length and language blend
+// the LLM judge with local heuristics; judge and heuristic are their separate averages.
+type CandidateScores struct {
+	Tone      float64 `json:"tone"`
+	Length    float64 `json:"length"`
+	Language  float64 `json:"language"`
+	Judge     float64 `json:"judge"`
+	Heuristic float64 `json:"heuristic"`
 }
 
 // WelcomeNoteV3Metadata provides transparency about how the model interpreted and generated the note.
@@ -34,6 +97,49 @@ type WelcomeNoteV3Metadata struct {
 	Sentiment           string `json:"sentiment"`           // positive | neutral | negative
 	Safety              string `json:"safety"`              // safe | needs_review
 	Comments            string `json:"comments,omitempty"`  // optional additional notes
+
+	PersonalizationUsed []string `json:"personalizationUsed,omitempty"` // personalization fields reflected in the note: recipients, sender, ...
+
+	PromptVersions map[string]string `json:"promptVersions,omitempty"` // prompt name -> version used to produce the note
+
+	Locale         string             `json:"locale,omitempty"`         // resolved BCP-47 locale the note was requested in
+	ToneBlend      []ToneWeight       `json:"toneBlend,omitempty"`      // normalized tone mix the note was requested in, heaviest first
+	MeasuredLength *LengthMeasurement `json:"measuredLength,omitempty"` // counted locally, not reported by the model
+	LanguageCheck  *LanguageCheck     `json:"languageCheck,omitempty"`  // detected locally, not reported by the model
+	InputGuard     *InputGuardVerdict `json:"inputGuard,omitempty"`     // prompt injection screening of the request
+	Pseudonymized  map[string]int     `json:"pseudonymized,omitempty"`  // personal data kept from the model, as counts by kind: person, email, ...
+}
+
+// ToneWeight is one tone's share of a blended tone.
+type ToneWeight struct {
+	Tone   string  `json:"tone"`
+	Weight float64 `json:"weight"` // 0–1; the weights of a blend sum to 1
+}
+
+// LengthMeasurement is the locally counted size of a note, checked against its length category.
+type LengthMeasurement struct {
+	Sentences    int  `json:"sentences"`
+	Words        int  `json:"words"`
+	MinSentences int  `json:"minSentences"`           // lower bound of the requested category
+	MaxSentences int  `json:"maxSentences,omitempty"` // upper bound; 0 for long, which has none
+	InRange      bool `json:"inRange"`
+	Attempts     int  `json:"attempts"` // generations needed, including retries
+}
+
+// Outcomes of a LanguageCheck
+const (
+	LanguageMatch      = "match"
+	LanguageMismatch   = "mismatch"
+	LanguageUnverified = "unverified" // the language isn't detectable or the note is too short to tell
+)
+
+// LanguageCheck compares the language detected in a note with the requested one.
+type LanguageCheck struct {
+	Requested  string  `json:"requested"`
+	Detected   string  `json:"detected,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Status     string  `json:"status"`   // match, mismatch or unverified
+	Attempts   int     `json:"attempts"` // generations needed, including retries
 }
 
 type SafeWelcomeNoteOutput struct {
@@ -47,15 +153,212 @@ type SafeWelcomeNoteOutput struct {
 	Metadata WelcomeNoteV3Metadata `json:"metadata"`
 
 	// safety info
-	Blocked        bool   `json:"blocked"`
-	ModerationNote string `json:"moderationNote,omitempty"`
-	OriginalNote   string `json:"originalNote,omitempty"` // only set if sanitized
+	Blocked        bool             `json:"blocked"`
+	ModerationNote string           `json:"moderationNote,omitempty"`
+	OriginalNote   string           `json:"originalNote,omitempty"`   // only set if sanitized
+	Categories     []CategoryScore  `json:"categories,omitempty"`     // severity per moderation category
+	ChangedSpans   []ChangedSpan    `json:"changedSpans,omitempty"`   // parts of OriginalNote that were replaced
+	Decision       string           `json:"decision,omitempty"`       // allow | sanitize | review | block
+	DecisionReason string           `json:"decisionReason,omitempty"` // e.g. "toxicity 0.62 ≥ review threshold 0.50"
+	Votes          []ModerationVote `json:"votes,omitempty"`          // each ensemble moderator's verdict; only set when an ensemble moderated the note
+	Pivot          *ModerationPivot `json:"pivot,omitempty"`          // the English translation also moderated; only set for a note that isn't in English
+
+	// all generated notes, best first; only set when more than one candidate was requested
+	Candidates []WelcomeNoteCandidate `json:"candidates,omitempty"`
+
+	// only set when the note was queued for a reviewer; poll /api/review/{id} for the decision
+	ReviewID     string `json:"reviewId,omitempty"`
+	ReviewStatus string `json:"reviewStatus,omitempty"` // pending until a reviewer decides
+
+	// only set in approval mode; while pending, the note is withheld
+	Approval *Approval `json:"approval,omitempty"`
+
+	// only set when a blocked note was regenerated
+	Remediation []RemediationAttempt `json:"remediation,omitempty"` // every attempt, the first generation included
+	Fallback    bool                 `json:"fallback,omitempty"`    // every attempt was blocked, so Note is a template
+}
+
+// Approval is an approver's sign-off on a Safe flow note in approval mode
+type Approval struct {
+	Status    string     `json:"status"`              // pending | approved | edited | rejected
+	Approver  string     `json:"approver,omitempty"`  // who decided
+	Comment   string     `json:"comment,omitempty"`   // the approver's reason, if given
+	DecidedAt *time.Time `json:"decidedAt,omitempty"` // when they decided
+}
+
+// ApprovalInput resumes a Safe flow paused for approval
+type ApprovalInput struct {
+	ID       string `json:"id" form:"-"`                      // the review ID the paused flow returned
+	Action   string `json:"action" form:"action"`             // approve | edit | reject
+	Note     string `json:"note,omitempty" form:"note"`       // the edited note; only for edit
+	Approver string `json:"approver" form:"-"`                // who decides; the endpoint sets it from the reviewer token
+	Comment  string `json:"comment,omitempty" form:"comment"` // why, for the requester
+}
+
+// RemediationAttempt is one generation of a Safe flow note and the moderation decision on it.
+// The text of a blocked attempt isn't kept.
+type RemediationAttempt struct {
+	Attempt        int             `json:"attempt"`
+	Decision       string          `json:"decision"`
+	DecisionReason string          `json:"decisionReason,omitempty"`
+	ModerationNote string          `json:"moderationNote,omitempty"`
+	Categories     []CategoryScore `json:"categories,omitempty"`
 }
 
 type ModerationResult struct {
 	SanitizedNote  string `json:"sanitizedNote"`  // empty if no changes
 	Blocked        bool   `json:"blocked"`        // true = should not show original
 	ModerationNote string `json:"moderationNote"` // short explanation / category
+
+	Categories []CategoryScore `json:"categories" jsonschema:"description=severity of every category from 0 for none to 1 for severe"`
+
+	RuleFindings   []RuleFinding    `json:"ruleFindings,omitempty" jsonschema:"-"`   // local rule matches, found before the LLM moderator ran
+	ChangedSpans   []ChangedSpan    `json:"changedSpans,omitempty" jsonschema:"-"`   // parts of the note sanitization replaced
+	Decision       string           `json:"decision,omitempty" jsonschema:"-"`       // allow | sanitize | review | block
+	DecisionReason string           `json:"decisionReason,omitempty" jsonschema:"-"` // what triggered the decision
+	Votes          []ModerationVote `json:"votes,omitempty" jsonschema:"-"`          // each ensemble moderator's verdict
+	Pivot          *ModerationPivot `json:"pivot,omitempty" jsonschema:"-"`          // the English translation also moderated
+}
+
+// ModerationPivot is the English translation a note in another language was also
+// moderated in, because the moderator misses sarcasm and insults outside English
+type ModerationPivot struct {
+	Language             string `json:"language"` // the note's language, e.g. "hindi"
+	Translation          string `json:"translation"`
+	SanitizedTranslation string `json:"sanitizedTranslation,omitempty"` // only set if moderation rewrote the translation; the rewrite was mapped back into the note
+}
+
+// ModerationVote is one ensemble moderator's verdict on a note
+type ModerationVote struct {
+	Moderator      string          `json:"moderator"` // "rules" or a model name
+	Weight         float64         `json:"weight"`
+	Blocked        bool            `json:"blocked"`
+	ModerationNote string          `json:"moderationNote,omitempty"`
+	Categories     []CategoryScore `json:"categories,omitempty"`
+	Error          string          `json:"error,omitempty"` // the moderator failed, so it abstained
+	DurationMs     int64           `json:"durationMs"`
+}
+
+// Moderation categories
+const (
+	CategoryToxicity   = "toxicity"
+	CategoryHarassment = "harassment"
+	CategorySexual     = "sexual"
+	CategorySelfHarm   = "self_harm"
+	CategoryPII        = "pii"
+	CategoryHate       = "hate"
+)
+
+// ModerationCategories lists the moderation categories in the order they are reported
+var ModerationCategories = []string{CategoryToxicity, CategoryHarassment, CategorySexual, CategorySelfHarm, CategoryPII, CategoryHate}
+
+// CategoryScore is how severe a note's content is in one moderation category.
+type CategoryScore struct {
+	Category string  `json:"category" jsonschema:"enum=toxicity,enum=harassment,enum=sexual,enum=self_harm,enum=pii,enum=hate"`
+	Severity float64 `json:"severity" jsonschema:"description=0 for none to 1 for severe"`
+}
+
+// ChangedSpan is a part of a moderated note that sanitization replaced or removed.
+// Offsets count characters (Unicode code points) of the note before sanitization.
+type ChangedSpan struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"` // empty when the text was removed
+}
+
+// RuleFinding is a match of a local moderation rule: a word list entry or a PII detector.
+type RuleFinding struct {
+	Rule     string  `json:"rule"`     // word list name such as "profanity", or PII detector such as "email"
+	Kind     string  `json:"kind"`     // word | pii
+	Action   string  `json:"action"`   // sanitize | block
+	Category string  `json:"category"` // one of ModerationCategories
+	Severity float64 `json:"severity"` // 0–1
+	Match    string  `json:"match"`
+	Start    int     `json:"start"` // character offsets of the match in the moderated note
+	End      int     `json:"end"`
+}
+
+// Input guard actions
+const (
+	GuardAllow      = "allow"      // the input reached the prompt unchanged
+	GuardNeutralize = "neutralize" // the suspicious parts were removed before the input reached the prompt
+	GuardReject     = "reject"     // the request was refused
+)
+
+// InputGuardVerdict is the input guard's judgement of whether a request tries to
+// inject instructions into the prompts.
+type InputGuardVerdict struct {
+	Action     string                   `json:"action"` // allow | neutralize | reject
+	Score      float64                  `json:"score"`  // 0–1 likelihood of a prompt injection
+	Findings   []InputGuardFinding      `json:"findings,omitempty"`
+	Classifier *InjectionClassification `json:"classifier,omitempty"` // set when the LLM classifier ran
+}
+
+// InputGuardFinding is a heuristic match in one input field.
+type InputGuardFinding struct {
+	Field  string  `json:"field"`  // e.g. occasion or description
+	Signal string  `json:"signal"` // e.g. instruction_override or role_marker
+	Match  string  `json:"match"`
+	Weight float64 `json:"weight"` // 0–1
+}
+
+// InjectionClassification is the LLM classifier's view of a request.
+type InjectionClassification struct {
+	Injection  bool    `json:"injection" jsonschema:"description=true if the text tries to change the assistant's instructions"`
+	Confidence float64 `json:"confidence" jsonschema:"description=0 to 1 confidence in the injection verdict"`
+	Reason     string  `json:"reason" jsonschema:"description=one short sentence explaining the verdict"`
+}
+
+// RefineInput asks for a revision of a previously generated note.
+type RefineInput struct {
+	Note        string           `json:"note" form:"note" binding:"required" jsonschema:"description=the welcome note to revise"`
+	Instruction string           `json:"instruction" form:"instruction" binding:"required" jsonschema:"description=free-text revision request such as make it shorter"`
+	Input       WelcomeNoteInput `json:"input" jsonschema:"description=the input the note was originally generated from"`
+}
+
+// RefineOutput is a revised note, moderated like the Safe flow, with a diff against the previous note.
+// A blocked revision, or one held for review, has no note and no diff.
+type RefineOutput struct {
+	*SafeWelcomeNoteOutput `json:",inline"` // the revised note and its moderation, as the Safe flow reports them
+
+	PreviousNote string        `json:"previousNote"`
+	Instruction  string        `json:"instruction"`
+	Changes      string        `json:"changes,omitempty"` // model's summary of what it changed
+	Diff         []DiffSegment `json:"diff,omitempty"`    // word-level diff from PreviousNote to Note
+
+	InputGuard     *InputGuardVerdict `json:"inputGuard,omitempty"`
+	Pseudonymized  map[string]int     `json:"pseudonymized,omitempty"` // personal data kept from the model, as counts by kind
+	PromptVersions map[string]string  `json:"promptVersions,omitempty"`
+}
+
+// DiffSegment is a run of text that is unchanged, added or removed.
+type DiffSegment struct {
+	Op   string `json:"op"` // equal | insert | delete
+	Text string `json:"text"`
+}
+
+// PipelineProgress reports the status of one step of a multi-step flow (Safe, Smart).
+type PipelineProgress struct {
+	Step       string `json:"step"`             // genkit.Run step name, e.g. interpret_description
+	Status     string `json:"status"`           // running | done | failed
+	DurationMs int64  `json:"durationMs"`       // set once the step has finished
+	Output     any    `json:"output,omitempty"` // intermediate output, e.g. the parsed input
+	Error      string `json:"error,omitempty"`  // set when the step failed
+}
+
+// SmartInput is a free-form description, optionally continuing an earlier conversation.
+type SmartInput struct {
+	Description string `json:"description" form:"description" binding:"required" jsonschema:"description=what the user wants in plain language"`
+	SessionID   string `json:"sessionId,omitempty" form:"sessionId" jsonschema:"description=session to continue; empty starts a new conversation"`
+	Model       string `json:"model,omitempty" form:"model" jsonschema:"description=model to write the note with; empty uses the configured routes"`
+}
+
+// SessionTurn is one message of a Smart flow conversation.
+type SessionTurn struct {
+	Role string    `json:"role"` // user | assistant
+	Text string    `json:"text"`
+	At   time.Time `json:"at"`
 }
 
 type SmartWelcomeFlowOutput struct {
@@ -63,4 +366,9 @@ type SmartWelcomeFlowOutput struct {
 
 	RawDescription string            `json:"rawDescription"`        // what user typed
 	ParsedInput    *WelcomeNoteInput `json:"parsedInput,omitempty"` // result of interpretPrompt
+
+	// conversation state, set when sessions are enabled
+	SessionID string        `json:"sessionId,omitempty"` // pass back to continue the conversation
+	Amended   bool          `json:"amended,omitempty"`   // true when ParsedInput amends the previous turn's interpretation
+	History   []SessionTurn `json:"history,omitempty"`   // conversation so far, including this turn
 }
@@ -4,15 +4,29 @@ import (
 	"encoding/hex"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config
 type Config struct {
-	Env       string
-	Server    ServerConfig
-	CSRF      CSRFConfig
-	RateLimit RateLimitConfig
+	Env        string
+	Server     ServerConfig
+	CSRF       CSRFConfig
+	RateLimit  RateLimitConfig
+	Prompts    PromptsConfig
+	Sessions   SessionsConfig
+	Quality    QualityConfig
+	Locales    LocalesConfig
+	Tones      TonesConfig
+	Admin      AdminConfig
+	Models     ModelsConfig
+	Policy     PolicyConfig
+	Moderation ModerationConfig
+	InputGuard InputGuardConfig
+	Privacy    PrivacyConfig
+	Audit      AuditConfig
+	Review     ReviewConfig
 }
 
 // ServerConfig
@@ -32,6 +46,72 @@ type RateLimitConfig struct {
 	LimiterTTL        time.Duration // How long to keep inactive limiters in memory
 }
 
+type PromptsConfig struct {
+	Dir string // Directory containing the versioned .prompt files
+}
+
+type SessionsConfig struct {
+	TTL             time.Duration // How long an idle Smart flow conversation is kept
+	CleanupInterval time.Duration // How often to remove expired sessions
+}
+
+type QualityConfig struct {
+	LengthRetries   int // How many times a note outside its length range is regenerated
+	LanguageRetries int // How many times a note detected in the wrong language is regenerated
+}
+
+type LocalesConfig struct {
+	Supported []string // BCP-47 tags of the locales notes can be requested in; empty uses the built-in list
+	Default   string   // Locale used when a request gives no language
+}
+
+type TonesConfig struct {
+	File string // JSON file the tone registry is kept in; empty keeps the built-in tones in memory
+}
+
+type AdminConfig struct {
+	Token string // Bearer token for tone registry writes and the /api/admin endpoints; empty disables them
+}
+
+type ModelsConfig struct {
+	Default    string // Model every step runs on unless routed otherwise
+	RoutesFile string // JSON file mapping flows and steps to models; empty runs everything on Default
+}
+
+type ModerationConfig struct {
+	Rules              bool   // Run the local rule-based moderation stage before the LLM moderator
+	RulesFile          string // JSON file with word lists and PII detectors; empty uses the built-in rules
+	Policy             string // Built-in threshold policy: strict, standard or lenient; empty leaves decisions to the LLM moderator
+	PolicyFile         string // JSON file with per-category thresholds; takes precedence over Policy
+	RemediationRetries int    // How many times the Safe flow regenerates a blocked note before using a template; 0 keeps it blocked
+	Ensemble           string // Comma-separated moderators that vote on each note, "rules" or a model name, optionally name=weight; empty uses one LLM moderator
+	Voting             string // How ensemble votes combine: any-blocks, majority or weighted
+	Quorum             int    // How many model moderators in the ensemble must vote for a note to be moderated
+	Pivot              bool   // Also moderate an English translation of notes in other languages
+}
+
+type InputGuardConfig struct {
+	Mode       string // neutralize or reject; off disables prompt injection screening
+	Classifier bool   // Also ask an LLM classifier about every request the heuristics don't reject
+}
+
+type AuditConfig struct {
+	Store string // Moderation audit log backend: jsonl, sqlite or off
+	Path  string // File the audit log is kept in; empty uses data/moderation-audit.jsonl or .db
+	Raw   bool   // Keep notes as written, personal data included, instead of pseudonymized
+}
+
+type ReviewConfig struct {
+	Queue    string // Where notes held for review wait for a reviewer: memory, file or off
+	Path     string // File the review queue is kept in when Queue is file
+	Token    string // Reviewer tokens, name:token pairs separated by commas, for the reviewer endpoints; required unless Queue is off
+	Approval bool   // Pause the Safe flow after moderation until an approver approves, edits or rejects the note
+}
+
+type PrivacyConfig struct {
+	Pseudonymize bool // Replace names, emails, phone numbers and IDs with placeholders before they reach the model
+}
+
 // Load loads config information from env
 func Load() *Config {
 	return &Config{
@@ -49,6 +129,69 @@ func Load() *Config {
 			CleanupInterval:   getEnvDuration("RATE_LIMIT_CLEANUP_INTERVAL", 5*time.Minute),
 			LimiterTTL:        getEnvDuration("RATE_LIMIT_LIMITER_TTL", 15*time.Minute),
 		},
+		Prompts: PromptsConfig{
+			Dir: getEnv("PROMPTS_DIR", "prompts"),
+		},
+		Sessions: SessionsConfig{
+			TTL:             getEnvDuration("SESSION_TTL", 30*time.Minute),
+			CleanupInterval: getEnvDuration("SESSION_CLEANUP_INTERVAL", 5*time.Minute),
+		},
+		Quality: QualityConfig{
+			LengthRetries:   getEnvInt("LENGTH_MAX_RETRIES", 2),
+			LanguageRetries: getEnvInt("LANGUAGE_MAX_RETRIES", 1),
+		},
+		Locales: LocalesConfig{
+			Supported: getEnvSlice("LOCALES", ","),
+			Default:   getEnv("DEFAULT_LOCALE", "en-US"),
+		},
+		Tones: TonesConfig{
+			File: getEnv("TONES_FILE", ""),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Models: ModelsConfig{
+			Default:    getEnv("MODEL_DEFAULT", "googleai/gemini-2.5-flash"),
+			RoutesFile: getEnv("MODEL_ROUTES_FILE", ""),
+		},
+		Policy: PolicyConfig{
+			Audience:      strings.ToLower(getEnv("POLICY_AUDIENCE", AudienceAdult)),
+			AllowedTones:  normalizeNames(getEnvSlice("POLICY_ALLOWED_TONES", ",")),
+			DeniedTones:   normalizeNames(getEnvSlice("POLICY_DENIED_TONES", ",")),
+			AllowedFlows:  normalizeNames(getEnvSlice("POLICY_ALLOWED_FLOWS", ",")),
+			DeniedFlows:   normalizeNames(getEnvSlice("POLICY_DENIED_FLOWS", ",")),
+			AllowedModels: getEnvSlice("POLICY_ALLOWED_MODELS", ","),
+			DeniedModels:  getEnvSlice("POLICY_DENIED_MODELS", ","),
+		},
+		Moderation: ModerationConfig{
+			Rules:              getEnvBool("MODERATION_RULES", true),
+			RulesFile:          getEnv("MODERATION_RULES_FILE", ""),
+			Policy:             strings.ToLower(strings.TrimSpace(getEnv("MODERATION_POLICY", ""))),
+			PolicyFile:         getEnv("MODERATION_POLICY_FILE", ""),
+			RemediationRetries: getEnvInt("MODERATION_REMEDIATION_RETRIES", 0),
+			Ensemble:           getEnv("MODERATION_ENSEMBLE", ""),
+			Voting:             getEnv("MODERATION_VOTING", "any-blocks"),
+			Quorum:             getEnvInt("MODERATION_QUORUM", 1),
+			Pivot:              getEnvBool("MODERATION_PIVOT", true),
+		},
+		InputGuard: InputGuardConfig{
+			Mode:       strings.ToLower(strings.TrimSpace(getEnv("INPUT_GUARD", "neutralize"))),
+			Classifier: getEnvBool("INPUT_GUARD_CLASSIFIER", false),
+		},
+		Privacy: PrivacyConfig{
+			Pseudonymize: getEnvBool("PSEUDONYMIZE", true),
+		},
+		Audit: AuditConfig{
+			Store: strings.ToLower(strings.TrimSpace(getEnv("MODERATION_AUDIT", "jsonl"))),
+			Path:  getEnv("MODERATION_AUDIT_PATH", ""),
+			Raw:   getEnvBool("MODERATION_AUDIT_RAW", false),
+		},
+		Review: ReviewConfig{
+			Queue:    strings.ToLower(strings.TrimSpace(getEnv("REVIEW_QUEUE", "off"))),
+			Path:     getEnv("REVIEW_QUEUE_PATH", "data/review-queue.json"),
+			Token:    getEnv("REVIEW_TOKEN", ""),
+			Approval: getEnvBool("SAFE_APPROVAL", false),
+		},
 	}
 }
 
@@ -68,6 +211,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
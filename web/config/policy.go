@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/tones"
+)
+
+// Audience levels, from most to least restrictive
+const (
+	AudienceAllAges   = "all-ages"  // safe tones only
+	AudienceWorkplace = "workplace" // safe and sensitive tones
+	AudienceAdult     = "adult"     // every tone
+)
+
+// Safety classes each audience level may use
+var audienceSafetyClasses = map[string][]string{
+	AudienceAllAges:   {tones.SafetySafe},
+	AudienceWorkplace: {tones.SafetySafe, tones.SafetySensitive},
+	AudienceAdult:     tones.SafetyClasses,
+}
+
+// Flows a policy can allow or deny, by tab id
+var PolicyFlows = []string{"v1", "v2", "v3", "safe", "smart", "refine"}
+
+// Error codes for requests a policy rejects
+const (
+	CodeFlowNotAllowed    = "flow_not_allowed"
+	CodeToneNotAllowed    = "tone_not_allowed"
+	CodeToneAboveAudience = "tone_not_allowed_for_audience"
+	CodeModelNotAllowed   = "model_not_allowed"
+)
+
+// PolicyError is a request rejected by the deployment's policy
+type PolicyError struct {
+	Code    string
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Message
+}
+
+// PolicyConfig restricts the tones, flows and models a deployment offers.
+// Empty allow lists allow everything not denied, except for models: requests may
+// only pick the models AllowedModels lists.
+type PolicyConfig struct {
+	Audience      string   // all-ages, workplace or adult
+	AllowedTones  []string // tone names; empty allows every tone the audience permits
+	DeniedTones   []string
+	AllowedFlows  []string // flow tab ids: v1, v2, v3, safe, smart, refine
+	DeniedFlows   []string
+	AllowedModels []string // models requests may pick, such as googleai/gemini-2.5-pro; empty lets them pick none
+	DeniedModels  []string
+}
+
+// Validate checks the audience level and flow names
+func (p *PolicyConfig) Validate() error {
+	if _, ok := audienceSafetyClasses[p.Audience]; !ok {
+		return fmt.Errorf("policy: audience must be one of %s, %s or %s", AudienceAllAges, AudienceWorkplace, AudienceAdult)
+	}
+	for _, flow := range append(slices.Clone(p.AllowedFlows), p.DeniedFlows...) {
+		if !slices.Contains(PolicyFlows, flow) {
+			return fmt.Errorf("policy: unknown flow %q, must be one of %s", flow, strings.Join(PolicyFlows, ", "))
+		}
+	}
+	if len(p.EnabledFlows()) == 0 {
+		return fmt.Errorf("policy: every flow is disabled")
+	}
+	return nil
+}
+
+// CheckFlow returns a *PolicyError if the flow is not allowed
+func (p *PolicyConfig) CheckFlow(flow string) error {
+	if slices.Contains(p.DeniedFlows, flow) || (len(p.AllowedFlows) > 0 && !slices.Contains(p.AllowedFlows, flow)) {
+		return &PolicyError{
+			Code:    CodeFlowNotAllowed,
+			Message: fmt.Sprintf("the %s flow is not available in this deployment", flow),
+		}
+	}
+	return nil
+}
+
+// EnabledFlows returns the allowed flows in PolicyFlows order
+func (p *PolicyConfig) EnabledFlows() []string {
+	var enabled []string
+	for _, flow := range PolicyFlows {
+		if p.CheckFlow(flow) == nil {
+			enabled = append(enabled, flow)
+		}
+	}
+	return enabled
+}
+
+// CheckTone returns a *PolicyError if the tone is denied or above the audience level
+func (p *PolicyConfig) CheckTone(t tones.Tone) error {
+	if slices.Contains(p.DeniedTones, t.Name) || (len(p.AllowedTones) > 0 && !slices.Contains(p.AllowedTones, t.Name)) {
+		return &PolicyError{
+			Code:    CodeToneNotAllowed,
+			Message: fmt.Sprintf("the %q tone is not available in this deployment", t.Name),
+		}
+	}
+	if !slices.Contains(audienceSafetyClasses[p.Audience], t.SafetyClass) {
+		return &PolicyError{
+			Code:    CodeToneAboveAudience,
+			Message: fmt.Sprintf("the %q tone is %s and not available for a %s audience", t.Name, t.SafetyClass, p.Audience),
+		}
+	}
+	return nil
+}
+
+// CheckModel returns a *PolicyError if requests may not pick the model
+func (p *PolicyConfig) CheckModel(model string) error {
+	if len(p.AllowedModels) == 0 {
+		return &PolicyError{
+			Code:    CodeModelNotAllowed,
+			Message: "this deployment doesn't let requests pick a model",
+		}
+	}
+	if slices.Contains(p.DeniedModels, model) || !slices.Contains(p.AllowedModels, model) {
+		return &PolicyError{
+			Code:    CodeModelNotAllowed,
+			Message: fmt.Sprintf("the %q model is not available in this deployment", model),
+		}
+	}
+	return nil
+}
+
+// EnabledModels returns the models requests may pick, in AllowedModels order
+func (p *PolicyConfig) EnabledModels() []string {
+	var enabled []string
+	for _, model := range p.AllowedModels {
+		if p.CheckModel(model) == nil {
+			enabled = append(enabled, model)
+		}
+	}
+	return enabled
+}
+
+func normalizeNames(names []string) []string {
+	for i, name := range names {
+		names[i] = strings.ToLower(name)
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// ModerationEventsHandler lists moderation audit events, newest first. The optional
+// query parameters from and to (RFC 3339) bound the time range, category keeps the events
+// that scored it, and limit caps the count.
+func ModerationEventsHandler(c *gin.Context) {
+	store := flows.AuditStore()
+	if store == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "moderation audit is disabled"})
+		return
+	}
+
+	q, err := auditQuery(c)
+	if err != nil {
+		auditError(c, err)
+		return
+	}
+	events, err := store.Query(c.Request.Context(), q)
+	if err != nil {
+		auditError(c, err)
+		return
+	}
+	if events == nil {
+		events = []audit.Event{}
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// auditError maps audit errors to HTTP status codes
+func auditError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, audit.ErrInvalid) {
+		status = http.StatusBadRequest
+	}
+	utils.GetLogger(c).Error("moderation audit query failed",
+		slog.String("handler", "ModerationEventsHandler"),
+		slog.String("error", err.Error()),
+	)
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// auditQuery reads an audit.Query from the request's query parameters
+func auditQuery(c *gin.Context) (audit.Query, error) {
+	q := audit.Query{Category: c.Query("category")}
+	for name, t := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := c.Query(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return q, fmt.Errorf("%w: %s must be an RFC 3339 time", audit.ErrInvalid, name)
+			}
+			*t = parsed
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return q, fmt.Errorf("%w: limit must be a number", audit.ErrInvalid)
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
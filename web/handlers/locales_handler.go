@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// LocalesHandler lists the supported locales. Datastar clients get them as the
+// locales and defaultLocale signals that fill the language dropdowns.
+func LocalesHandler(c *gin.Context) {
+	registry := flows.Locales()
+	signals := map[string]interface{}{
+		"locales":       registry.List(),
+		"defaultLocale": registry.Default().Tag,
+	}
+
+	if !utils.IsDatastarRequest(c) {
+		c.JSON(http.StatusOK, signals)
+		return
+	}
+	utils.SendSignalUpdate(c, signals)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// pipelineSteps holds the latest progress of each pipeline step, in the order
+// the steps started. It is sent as a whole so the UI can render it directly.
+type pipelineSteps []*types.PipelineProgress
+
+// update records progress, replacing any earlier event for the same step
+func (p *pipelineSteps) update(progress *types.PipelineProgress) {
+	for i, step := range *p {
+		if step.Step == progress.Step {
+			(*p)[i] = progress
+			return
+		}
+	}
+	*p = append(*p, progress)
+}
+
+func logPipelineProgress(logger *slog.Logger, progress *types.PipelineProgress) {
+	logger.Info("pipeline step",
+		slog.String("step", progress.Step),
+		slog.String("status", progress.Status),
+		slog.Int64("durationMs", progress.DurationMs),
+	)
+}
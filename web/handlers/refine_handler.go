@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+func RefineHandler(c *gin.Context) {
+	logger := utils.GetLogger(c)
+	logger = logger.With(slog.String("handler", "RefineHandler"))
+
+	logger.Info("http handler begins")
+	defer func() {
+		logger.Info("http handler ends")
+	}()
+
+	isDatastar := utils.IsDatastarRequest(c)
+
+	formInput := types.RefineInput{}
+	// This will infer what binder to use depending on the content-type header.
+	// In forms the original input's fields (occasion, tone, ...) sit next to note and instruction.
+	if err := c.ShouldBind(&formInput); err != nil {
+		logger.Error("invalid inputs, ShouldBind failed",
+			slog.String("error", err.Error()),
+			slog.Any("form-input", formInput),
+		)
+
+		if !isDatastar {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		utils.SendSignalUpdateWithError(c, "refineTab", "")
+		return
+	}
+
+	logger.Info("form input", slog.Any("form", formInput))
+
+	if !validateInput(c, logger, "refineTab", &formInput.Input) {
+		return
+	}
+
+	val, ok := flows.GetFlow("welcomeNoteFlowRefine")
+	if !ok {
+		logger.Error("flow does not exist",
+			slog.String("error", "flow does not exist in the internal flows store"),
+		)
+		utils.SendSignalUpdateWithError(c, "refineTab", "")
+		return
+	}
+	flow, ok := val.(*core.Flow[*types.RefineInput, *types.RefineOutput, *types.PipelineProgress])
+	if !ok {
+		logger.Error("flow type assertion error",
+			slog.String("error", "Flow is not of the right core.Flow type"),
+		)
+		utils.SendSignalUpdateWithError(c, "refineTab", "")
+		return
+	}
+
+	// record the model every step calls, for the response
+	ctx := flows.WithModelReport(c.Request.Context())
+	stream := utils.NewFlowStream(c, "refineTab")
+
+	// run the flow, forwarding pipeline progress as each step starts and finishes
+	var steps pipelineSteps
+	var output *types.RefineOutput
+	for v, err := range flow.Stream(ctx, &formInput) {
+		if err != nil {
+			logger.Error("flow.Stream returned with error",
+				slog.String("error", err.Error()),
+			)
+			stream.Error(flowErrorMessage(err))
+			return
+		}
+		if v.Done {
+			output = v.Output
+			break
+		}
+		logPipelineProgress(logger, v.Stream)
+		steps.update(v.Stream)
+		stream.Chunk(map[string]interface{}{
+			"steps":  steps,
+			"result": "",
+			"error":  "",
+		}, v.Stream)
+	}
+
+	logger.Info("refined a note",
+		slog.Any("flow.Run output", output),
+	)
+
+	resultJson, _ := json.MarshalIndent(output, "", "  ")
+
+	model, models := flows.ModelsUsed(ctx)
+	signals := map[string]interface{}{
+		"refineTab": map[string]interface{}{
+			"result": map[string]interface{}{
+				"model":          model,
+				"models":         models,
+				"note":           output.Note,
+				"previousNote":   output.PreviousNote,
+				"instruction":    output.Instruction,
+				"changes":        output.Changes,
+				"diff":           output.Diff,
+				"blocked":        output.Blocked,
+				"moderationNote": output.ModerationNote,
+				"originalNote":   output.OriginalNote,
+				"categories":     output.Categories,
+				"changedSpans":   output.ChangedSpans,
+				"decision":       output.Decision,
+				"decisionReason": output.DecisionReason,
+				"votes":          output.Votes,
+				"pivot":          output.Pivot,
+				"reviewId":       output.ReviewID,
+				"reviewStatus":   output.ReviewStatus,
+				"promptVersions": output.PromptVersions,
+				"inputGuard":     output.InputGuard,
+				"pseudonymized":  output.Pseudonymized,
+			},
+			"resultJson": string(resultJson),
+			"steps":      steps,
+			"error":      "",
+		},
+	}
+	stream.Result(signals)
+}
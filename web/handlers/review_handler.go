@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/gin-gonic/gin"
+	"github.com/starfederation/datastar-go/datastar"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/review"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+	"github.com/vnaveen-mh/welcome-note-generator/web/middleware"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// Tabs whose notes can be queued for review; a Datastar requester names one with ?tab=
+var reviewTabs = []string{"safeTab", "smartTab", "refineTab"}
+
+// ReviewStatusHandler returns a queued note's review item to its requester, without the
+// note until it is decided; see review.Item.ForRequester. JSON clients
+// get the item as it is now, to poll. Clients accepting text/event-stream get it as a
+// "review" event, then wait for a second one once a reviewer decides. Datastar clients
+// name the tab showing the note with ?tab= and get its review signal patched the same way.
+func ReviewStatusHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	isDatastar := utils.IsDatastarRequest(c)
+	tabName := c.Query("tab")
+	if isDatastar && !slices.Contains(reviewTabs, tabName) {
+		utils.SendSignalUpdateWithError(c, "reviewTab", fmt.Sprintf("tab must be one of %v", reviewTabs))
+		return
+	}
+
+	queue := flows.ReviewQueue()
+	it, err := queue.Get(ctx, c.Param("id"))
+	if err != nil {
+		reviewError(c, "ReviewStatusHandler", tabName, err)
+		return
+	}
+	if !isDatastar && utils.StreamFormat(c) != utils.StreamFormatSSE {
+		c.JSON(http.StatusOK, it.ForRequester())
+		return
+	}
+
+	decided, stop, err := queue.Watch(ctx, it.ID)
+	if err != nil {
+		reviewError(c, "ReviewStatusHandler", tabName, err)
+		return
+	}
+	defer stop()
+
+	send := func(it review.Item) {
+		c.SSEvent("review", it.ForRequester())
+		c.Writer.Flush()
+	}
+	if isDatastar {
+		sse := datastar.NewSSE(c.Writer, c.Request)
+		send = func(it review.Item) {
+			sse.MarshalAndPatchSignals(map[string]interface{}{
+				tabName: map[string]interface{}{"review": it.ForRequester()},
+			})
+		}
+	}
+
+	send(it)
+	if !it.Pending() {
+		return
+	}
+	select {
+	case it = <-decided:
+		send(it)
+	case <-ctx.Done():
+	}
+}
+
+// ListReviewsHandler lists the review queue, newest first: the pending items, or those with
+// the status given as ?status=; ?status=all lists every item. Datastar clients get them as
+// the review tab's items.
+func ListReviewsHandler(c *gin.Context) {
+	status := c.DefaultQuery("status", review.StatusPending)
+	if status == "all" {
+		status = ""
+	}
+	items, err := flows.ReviewQueue().List(c.Request.Context(), status)
+	if err != nil {
+		reviewError(c, "ListReviewsHandler", "reviewTab", err)
+		return
+	}
+	if items == nil {
+		items = []review.Item{}
+	}
+
+	if !utils.IsDatastarRequest(c) {
+		c.JSON(http.StatusOK, gin.H{"items": items})
+		return
+	}
+	utils.SendSignalUpdate(c, map[string]interface{}{
+		"reviewTab": map[string]interface{}{"items": items, "error": ""},
+	})
+}
+
+// DecideReviewHandler applies a reviewer's approve, edit or reject, named in the path, to
+// a pending item, signed by the reviewer whose token the request carries. The form or
+// JSON body carries an optional comment and, for edit, the edited note. An approval item's decision resumes its paused Safe flow.
+// Datastar clients get the pending items that are left.
+func DecideReviewHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var d review.Decision
+	if err := c.ShouldBind(&d); err != nil {
+		reviewError(c, "DecideReviewHandler", "reviewTab", errors.Join(review.ErrInvalid, err))
+		return
+	}
+	d.Action = c.Param("action")
+	d.Reviewer = middleware.Reviewer(c)
+
+	queue := flows.ReviewQueue()
+	it, err := queue.Get(ctx, c.Param("id"))
+	if err != nil {
+		reviewError(c, "DecideReviewHandler", "reviewTab", err)
+		return
+	}
+	if it.Kind == review.KindApproval {
+		_, err = resumeSafeFlow(c, &types.ApprovalInput{ID: it.ID, Action: d.Action, Note: d.Note, Approver: d.Reviewer, Comment: d.Comment})
+		if err == nil {
+			it, err = queue.Get(ctx, it.ID)
+		}
+	} else {
+		it, err = queue.Decide(ctx, it.ID, d)
+	}
+	if err != nil {
+		reviewError(c, "DecideReviewHandler", "reviewTab", err)
+		return
+	}
+	utils.GetLogger(c).Info("review decided",
+		slog.String("id", it.ID),
+		slog.String("kind", it.Kind),
+		slog.String("status", it.Status),
+		slog.String("reviewer", it.Reviewer),
+	)
+
+	if !utils.IsDatastarRequest(c) {
+		c.JSON(http.StatusOK, it)
+		return
+	}
+	sendPendingReviews(c, "DecideReviewHandler", it)
+}
+
+// ResumeSafeHandler resumes a Safe flow paused for approval, signed by the reviewer whose
+// token the request carries. The form or JSON body carries the approve, edit or reject
+// action, an optional comment and, for edit, the edited note. JSON clients get the flow's final output, Datastar clients the pending
+// items that are left.
+func ResumeSafeHandler(c *gin.Context) {
+	var input types.ApprovalInput
+	if err := c.ShouldBind(&input); err != nil {
+		reviewError(c, "ResumeSafeHandler", "reviewTab", errors.Join(review.ErrInvalid, err))
+		return
+	}
+	input.ID = c.Param("id")
+	input.Approver = middleware.Reviewer(c)
+
+	out, err := resumeSafeFlow(c, &input)
+	if err != nil {
+		reviewError(c, "ResumeSafeHandler", "reviewTab", err)
+		return
+	}
+
+	if !utils.IsDatastarRequest(c) {
+		c.JSON(http.StatusOK, out)
+		return
+	}
+	it, err := flows.ReviewQueue().Get(c.Request.Context(), input.ID)
+	if err != nil {
+		reviewError(c, "ResumeSafeHandler", "reviewTab", err)
+		return
+	}
+	sendPendingReviews(c, "ResumeSafeHandler", it)
+}
+
+// resumeSafeFlow runs the flow that resumes a Safe flow paused for approval
+func resumeSafeFlow(c *gin.Context, input *types.ApprovalInput) (*types.SafeWelcomeNoteOutput, error) {
+	val, ok := flows.GetFlow("welcomeNoteFlowSafeResume")
+	if !ok {
+		return nil, fmt.Errorf("approval mode is off")
+	}
+	flow, ok := val.(*core.Flow[*types.ApprovalInput, *types.SafeWelcomeNoteOutput, struct{}])
+	if !ok {
+		return nil, fmt.Errorf("flow welcomeNoteFlowSafeResume is not of the right core.Flow type")
+	}
+	out, err := flow.Run(c.Request.Context(), input)
+	if err != nil {
+		return nil, err
+	}
+	utils.GetLogger(c).Info("approval decided",
+		slog.String("id", out.ReviewID),
+		slog.String("status", out.Approval.Status),
+		slog.String("approver", out.Approval.Approver),
+	)
+	return out, nil
+}
+
+// sendPendingReviews patches the review tab with the pending items that are left after it
+// was decided
+func sendPendingReviews(c *gin.Context, handler string, it review.Item) {
+	items, err := flows.ReviewQueue().List(c.Request.Context(), review.StatusPending)
+	if err != nil {
+		reviewError(c, handler, "reviewTab", err)
+		return
+	}
+	if items == nil {
+		items = []review.Item{}
+	}
+	utils.SendSignalUpdate(c, map[string]interface{}{
+		"reviewTab": map[string]interface{}{
+			"items":   items,
+			"error":   "",
+			"message": fmt.Sprintf("Note %.8s %s by %s", it.ID, it.Status, it.Reviewer),
+		},
+	})
+}
+
+// reviewError maps review queue errors to HTTP status codes. Datastar clients get the
+// error on tabName; on a flow's tab it goes in the review signal, leaving the note shown.
+func reviewError(c *gin.Context, handler, tabName string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, review.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, review.ErrInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, review.ErrDecided):
+		status = http.StatusConflict
+	}
+	utils.GetLogger(c).Error("review request failed",
+		slog.String("handler", handler),
+		slog.String("error", err.Error()),
+	)
+	if utils.IsDatastarRequest(c) && slices.Contains(reviewTabs, tabName) {
+		c.Status(status)
+		utils.SendSignalUpdate(c, map[string]interface{}{
+			tabName: map[string]interface{}{"review": gin.H{"error": err.Error()}},
+		})
+		return
+	}
+	utils.SendSignalUpdateWithErrorCode(c, tabName, "", err.Error(), status)
+}
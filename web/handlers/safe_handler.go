@@ -41,6 +41,10 @@ func SafeHandler(c *gin.Context) {
 
 	logger.Info("form input", slog.Any("form", formInput))
 
+	if !validateInput(c, logger, "safeTab", &formInput) {
+		return
+	}
+
 	val, ok := flows.GetFlow("welcomeNoteFlowSafe")
 	if !ok {
 		logger.Error("flow does not exist",
@@ -49,7 +53,7 @@ func SafeHandler(c *gin.Context) {
 		utils.SendSignalUpdateWithError(c, "safeTab", "")
 		return
 	}
-	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, *types.SafeWelcomeNoteOutput, struct{}])
+	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, *types.SafeWelcomeNoteOutput, *types.PipelineProgress])
 	if !ok {
 		logger.Error("flow type assertion error",
 			slog.String("error", "Flow is not of the right core.Flow type"),
@@ -58,15 +62,32 @@ func SafeHandler(c *gin.Context) {
 		return
 	}
 
-	// run the flow
-	output, err := flow.Run(c.Request.Context(), &formInput)
+	// record the model every step calls, for the response
+	ctx := flows.WithModelReport(c.Request.Context())
+	stream := utils.NewFlowStream(c, "safeTab")
 
-	if err != nil {
-		logger.Error("flow.Run returned with error",
-			slog.String("error", err.Error()),
-		)
-		utils.SendSignalUpdateWithError(c, "safeTab", "")
-		return
+	// run the flow, forwarding pipeline progress as each step starts and finishes
+	var steps pipelineSteps
+	var output *types.SafeWelcomeNoteOutput
+	for v, err := range flow.Stream(ctx, &formInput) {
+		if err != nil {
+			logger.Error("flow.Stream returned with error",
+				slog.String("error", err.Error()),
+			)
+			stream.Error(flowErrorMessage(err))
+			return
+		}
+		if v.Done {
+			output = v.Output
+			break
+		}
+		logPipelineProgress(logger, v.Stream)
+		steps.update(v.Stream)
+		stream.Chunk(map[string]interface{}{
+			"steps":  steps,
+			"result": "",
+			"error":  "",
+		}, v.Stream)
 	}
 
 	logger.Info("generated a new note",
@@ -75,9 +96,12 @@ func SafeHandler(c *gin.Context) {
 
 	resultJson, _ := json.MarshalIndent(output, "", "  ")
 
+	model, models := flows.ModelsUsed(ctx)
 	signals := map[string]interface{}{
 		"safeTab": map[string]interface{}{
 			"result": map[string]interface{}{
+				"model":          model,
+				"models":         models,
 				"note":           output.Note,
 				"occasion":       output.Occasion,
 				"language":       output.Language,
@@ -86,6 +110,15 @@ func SafeHandler(c *gin.Context) {
 				"blocked":        output.Blocked,
 				"moderationNote": output.ModerationNote,
 				"originalNote":   output.OriginalNote,
+				"categories":     output.Categories,
+				"changedSpans":   output.ChangedSpans,
+				"decision":       output.Decision,
+				"decisionReason": output.DecisionReason,
+				"votes":          output.Votes,
+				"pivot":          output.Pivot,
+				"reviewId":       output.ReviewID,
+				"reviewStatus":   output.ReviewStatus,
+				"approval":       output.Approval,
 				"metadata": map[string]interface{}{
 					"interpretedOccasion": output.Metadata.InterpretedOccasion,
 					"effectiveLanguage":   output.Metadata.EffectiveLanguage,
@@ -94,16 +127,26 @@ func SafeHandler(c *gin.Context) {
 					"sentiment":           output.Metadata.Sentiment,
 					"safety":              output.Metadata.Safety,
 					"comments":            output.Metadata.Comments,
+					"promptVersions":      output.Metadata.PromptVersions,
+					"personalizationUsed": output.Metadata.PersonalizationUsed,
+					"locale":              output.Metadata.Locale,
+					"measuredLength":      output.Metadata.MeasuredLength,
+					"languageCheck":       output.Metadata.LanguageCheck,
+					"toneBlend":           output.Metadata.ToneBlend,
+					"inputGuard":          output.Metadata.InputGuard,
+					"pseudonymized":       output.Metadata.Pseudonymized,
 				},
+				"candidates":  output.Candidates,
+				"remediation": output.Remediation,
+				"fallback":    output.Fallback,
 			},
-			"resultJson": string(resultJson),
-			"error":      "",
+			"selectedCandidate": 0,
+			"review":            nil, // clears an earlier note's review decision
+			"resultJson":        string(resultJson),
+			"steps":             steps,
+			"error":             "",
 		},
 	}
-	if isDatastar {
-		utils.SendSignalUpdate(c, signals)
-		return
-	}
-	c.JSON(200, signals["safeTab"])
+	stream.Result(signals)
 
 }
@@ -12,10 +12,6 @@ import (
 	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
 )
 
-type smartInput struct {
-	Description string `json:"description" form:"description" binding:"required"`
-}
-
 func SmartHandler(c *gin.Context) {
 	logger := utils.GetLogger(c)
 	logger = logger.With(slog.String("handler", "SmartHandler"))
@@ -27,7 +23,7 @@ func SmartHandler(c *gin.Context) {
 
 	isDatastar := utils.IsDatastarRequest(c)
 
-	formInput := smartInput{}
+	formInput := types.SmartInput{}
 	// This will infer what binder to use depending on the content-type header
 	if err := c.ShouldBind(&formInput); err != nil {
 		logger.Error("invalid inputs, ShouldBind failed",
@@ -45,6 +41,10 @@ func SmartHandler(c *gin.Context) {
 
 	logger.Info("form input", slog.Any("form", formInput))
 
+	if !validateModel(c, logger, "smartTab", formInput.Model) {
+		return
+	}
+
 	val, ok := flows.GetFlow("welcomeNoteFlowSmart")
 	if !ok {
 		logger.Error("flow does not exist",
@@ -53,7 +53,7 @@ func SmartHandler(c *gin.Context) {
 		utils.SendSignalUpdateWithError(c, "smartTab", "")
 		return
 	}
-	flow, ok := val.(*core.Flow[string, *types.SmartWelcomeFlowOutput, struct{}])
+	flow, ok := val.(*core.Flow[*types.SmartInput, *types.SmartWelcomeFlowOutput, *types.PipelineProgress])
 	if !ok {
 		logger.Error("flow type assertion error",
 			slog.String("error", "Flow is not of the right core.Flow type"),
@@ -62,15 +62,32 @@ func SmartHandler(c *gin.Context) {
 		return
 	}
 
-	// run the flow
-	output, err := flow.Run(c.Request.Context(), formInput.Description)
-
-	if err != nil {
-		logger.Error("flow.Run returned with error",
-			slog.String("error", err.Error()),
-		)
-		utils.SendSignalUpdateWithError(c, "smartTab", "")
-		return
+	// record the model every step calls, for the response
+	ctx := flows.WithModelReport(c.Request.Context())
+	stream := utils.NewFlowStream(c, "smartTab")
+
+	// run the flow, forwarding pipeline progress as each step starts and finishes
+	var steps pipelineSteps
+	var output *types.SmartWelcomeFlowOutput
+	for v, err := range flow.Stream(ctx, &formInput) {
+		if err != nil {
+			logger.Error("flow.Stream returned with error",
+				slog.String("error", err.Error()),
+			)
+			stream.Error(flowErrorMessage(err))
+			return
+		}
+		if v.Done {
+			output = v.Output
+			break
+		}
+		logPipelineProgress(logger, v.Stream)
+		steps.update(v.Stream)
+		stream.Chunk(map[string]interface{}{
+			"steps":  steps,
+			"result": "",
+			"error":  "",
+		}, v.Stream)
 	}
 
 	logger.Info("generated a new note",
@@ -86,6 +103,12 @@ func SmartHandler(c *gin.Context) {
 			"language": output.ParsedInput.Language,
 			"length":   output.ParsedInput.Length,
 			"tone":     output.ParsedInput.Tone,
+
+			"recipients":   output.ParsedInput.Recipients,
+			"sender":       output.ParsedInput.Sender,
+			"relationship": output.ParsedInput.Relationship,
+			"organization": output.ParsedInput.Organization,
+			"signature":    output.ParsedInput.Signature,
 		}
 	}
 
@@ -97,11 +120,21 @@ func SmartHandler(c *gin.Context) {
 		"sentiment":           output.Metadata.Sentiment,
 		"safety":              output.Metadata.Safety,
 		"comments":            output.Metadata.Comments,
+		"promptVersions":      output.Metadata.PromptVersions,
+		"personalizationUsed": output.Metadata.PersonalizationUsed,
+		"locale":              output.Metadata.Locale,
+		"measuredLength":      output.Metadata.MeasuredLength,
+		"languageCheck":       output.Metadata.LanguageCheck,
+		"inputGuard":          output.Metadata.InputGuard,
+		"pseudonymized":       output.Metadata.Pseudonymized,
 	}
 
+	model, models := flows.ModelsUsed(ctx)
 	signals := map[string]interface{}{
 		"smartTab": map[string]interface{}{
 			"result": map[string]interface{}{
+				"model":          model,
+				"models":         models,
 				"note":           output.Note,
 				"occasion":       output.Occasion,
 				"language":       output.Language,
@@ -111,17 +144,26 @@ func SmartHandler(c *gin.Context) {
 				"blocked":        output.Blocked,
 				"moderationNote": output.ModerationNote,
 				"originalNote":   output.OriginalNote,
+				"categories":     output.Categories,
+				"changedSpans":   output.ChangedSpans,
+				"decision":       output.Decision,
+				"decisionReason": output.DecisionReason,
+				"votes":          output.Votes,
+				"pivot":          output.Pivot,
+				"reviewId":       output.ReviewID,
+				"reviewStatus":   output.ReviewStatus,
 				"rawDescription": output.RawDescription,
 				"parsedInput":    parsed,
+				"amended":        output.Amended,
 			},
+			"review":     nil, // clears an earlier note's review decision
+			"sessionId":  output.SessionID,
+			"history":    output.History,
 			"resultJson": string(resultJson),
+			"steps":      steps,
 			"error":      "",
 		},
 	}
-	if isDatastar {
-		utils.SendSignalUpdate(c, signals)
-		return
-	}
-	c.JSON(200, signals["smartTab"])
+	stream.Result(signals)
 
 }
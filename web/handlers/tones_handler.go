@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/tones"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// ListTonesHandler lists the tone registry; with ?allowed=true only the tones the
+// deployment policy allows. Datastar clients get it as the tones signal that fills
+// the tone dropdowns.
+func ListTonesHandler(c *gin.Context) {
+	list, err := flows.ToneStore().List(c.Request.Context())
+	if c.Query("allowed") == "true" {
+		list, err = flows.AllowedTones(c.Request.Context())
+	}
+	if err != nil {
+		toneError(c, "ListTonesHandler", err)
+		return
+	}
+
+	if !utils.IsDatastarRequest(c) {
+		c.JSON(http.StatusOK, gin.H{"tones": list})
+		return
+	}
+	utils.SendSignalUpdate(c, map[string]interface{}{"tones": list})
+}
+
+// GetToneHandler returns one tone by name or alias
+func GetToneHandler(c *gin.Context) {
+	t, err := tones.Resolve(c.Request.Context(), flows.ToneStore(), c.Param("name"))
+	if err != nil {
+		toneError(c, "GetToneHandler", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// SaveToneHandler creates or replaces the tone named in the path from a JSON body
+func SaveToneHandler(c *gin.Context) {
+	var t tones.Tone
+	if err := c.ShouldBindJSON(&t); err != nil {
+		toneError(c, "SaveToneHandler", errors.Join(tones.ErrInvalid, err))
+		return
+	}
+	t.Name = c.Param("name")
+
+	if err := flows.ToneStore().Save(c.Request.Context(), t); err != nil {
+		toneError(c, "SaveToneHandler", err)
+		return
+	}
+	saved, err := flows.ToneStore().Get(c.Request.Context(), t.Name)
+	if err != nil {
+		toneError(c, "SaveToneHandler", err)
+		return
+	}
+	utils.GetLogger(c).Info("tone saved", slog.String("tone", saved.Name))
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteToneHandler removes a tone from the registry
+func DeleteToneHandler(c *gin.Context) {
+	name := c.Param("name")
+	if err := flows.ToneStore().Delete(c.Request.Context(), name); err != nil {
+		toneError(c, "DeleteToneHandler", err)
+		return
+	}
+	utils.GetLogger(c).Info("tone deleted", slog.String("tone", name))
+	c.Status(http.StatusNoContent)
+}
+
+// toneError maps registry errors to HTTP status codes
+func toneError(c *gin.Context, handler string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, tones.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, tones.ErrInvalid):
+		status = http.StatusBadRequest
+	}
+	utils.GetLogger(c).Error("tone registry request failed",
+		slog.String("handler", handler),
+		slog.String("error", err.Error()),
+	)
+	c.JSON(status, gin.H{"error": err.Error()})
+}
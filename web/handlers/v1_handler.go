@@ -12,6 +12,7 @@ import (
 
 type v1Input struct {
 	Occasion string `json:"occasion" form:"occasion" binding:"required"`
+	Model    string `json:"model,omitempty" form:"model"`
 }
 
 func V1Handler(c *gin.Context) {
@@ -41,6 +42,10 @@ func V1Handler(c *gin.Context) {
 
 	logger.Info("form input", slog.Any("form", formInput))
 
+	if !validateModel(c, logger, "v1Tab", formInput.Model) {
+		return
+	}
+
 	val, ok := flows.GetFlow("welcomeNoteFlowV1")
 	if !ok {
 		logger.Error("flow does not exist",
@@ -59,14 +64,19 @@ func V1Handler(c *gin.Context) {
 		return
 	}
 
+	// the flow's input is only the occasion, so the model it writes with and the report
+	// of the models it called travel in the context; the model was validated above
+	ctx := flows.WithModelReport(c.Request.Context())
+	ctx, _ = flows.WithModel(ctx, formInput.Model)
+
 	// run the flow
-	output, err := flow.Run(c.Request.Context(), formInput.Occasion)
+	output, err := flow.Run(ctx, formInput.Occasion)
 
 	if err != nil {
 		logger.Error("flow.Run returned with error",
 			slog.String("error", err.Error()),
 		)
-		utils.SendSignalUpdateWithError(c, "v1Tab", "")
+		utils.SendSignalUpdateWithError(c, "v1Tab", flowErrorMessage(err))
 		return
 	}
 
@@ -74,10 +84,13 @@ func V1Handler(c *gin.Context) {
 		slog.String("flow.Run output", output),
 	)
 
+	model, models := flows.ModelsUsed(ctx)
 	signals := map[string]interface{}{
 		"v1Tab": map[string]interface{}{
 			"result": map[string]interface{}{
-				"note": output,
+				"model":  model,
+				"models": models,
+				"note":   output,
 			},
 			"error": "",
 		},
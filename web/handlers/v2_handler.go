@@ -40,6 +40,10 @@ func V2Handler(c *gin.Context) {
 
 	logger.Info("form input", slog.Any("form", formInput))
 
+	if !validateInput(c, logger, "v2Tab", &formInput) {
+		return
+	}
+
 	val, ok := flows.GetFlow("welcomeNoteFlowV2")
 	if !ok {
 		logger.Error("flow does not exist",
@@ -48,7 +52,7 @@ func V2Handler(c *gin.Context) {
 		utils.SendSignalUpdateWithError(c, "v2Tab", "")
 		return
 	}
-	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, string, struct{}])
+	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, string, *types.WelcomeNoteChunk])
 	if !ok {
 		logger.Error("flow type assertion error",
 			slog.String("error", "Flow is not of the right core.Flow type"),
@@ -57,32 +61,48 @@ func V2Handler(c *gin.Context) {
 		return
 	}
 
-	// run the flow
-	output, err := flow.Run(c.Request.Context(), &formInput)
+	// record the model every step calls, for the response
+	ctx := flows.WithModelReport(c.Request.Context())
+	stream := utils.NewFlowStream(c, "v2Tab")
 
-	if err != nil {
-		logger.Error("flow.Run returned with error",
-			slog.String("error", err.Error()),
-		)
-		utils.SendSignalUpdateWithError(c, "v2Tab", "")
-		return
+	// run the flow, forwarding partial notes as they arrive
+	var output string
+	for v, err := range flow.Stream(ctx, &formInput) {
+		if err != nil {
+			logger.Error("flow.Stream returned with error",
+				slog.String("error", err.Error()),
+			)
+			stream.Error(flowErrorMessage(err))
+			return
+		}
+		if v.Done {
+			output = v.Output
+			break
+		}
+		stream.Chunk(map[string]interface{}{
+			"result": map[string]interface{}{
+				"note": v.Stream.Note,
+			},
+			"streaming": true,
+			"error":     "",
+		}, v.Stream)
 	}
 
 	logger.Info("generated a new note",
 		slog.String("flow.Run output", output),
 	)
 
+	model, models := flows.ModelsUsed(ctx)
 	signals := map[string]interface{}{
 		"v2Tab": map[string]interface{}{
 			"result": map[string]interface{}{
-				"note": output,
+				"model":  model,
+				"models": models,
+				"note":   output,
 			},
-			"error": "",
+			"streaming": false,
+			"error":     "",
 		},
 	}
-	if isDatastar {
-		utils.SendSignalUpdate(c, signals)
-		return
-	}
-	c.JSON(200, signals["v2Tab"])
+	stream.Result(signals)
 }
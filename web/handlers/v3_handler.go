@@ -41,6 +41,10 @@ func V3Handler(c *gin.Context) {
 
 	logger.Info("form input", slog.Any("form", formInput))
 
+	if !validateInput(c, logger, "v3Tab", &formInput) {
+		return
+	}
+
 	val, ok := flows.GetFlow("welcomeNoteFlowV3")
 	if !ok {
 		logger.Error("flow does not exist",
@@ -49,7 +53,7 @@ func V3Handler(c *gin.Context) {
 		utils.SendSignalUpdateWithError(c, "v3Tab", "")
 		return
 	}
-	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, *types.WelcomeNoteV3Output, struct{}])
+	flow, ok := val.(*core.Flow[*types.WelcomeNoteInput, *types.WelcomeNoteV3Output, *types.WelcomeNoteChunk])
 	if !ok {
 		logger.Error("flow type assertion error",
 			slog.String("error", "Flow is not of the right core.Flow type"),
@@ -58,15 +62,35 @@ func V3Handler(c *gin.Context) {
 		return
 	}
 
-	// run the flow
-	output, err := flow.Run(c.Request.Context(), &formInput)
+	// record the model every step calls, for the response
+	ctx := flows.WithModelReport(c.Request.Context())
+	stream := utils.NewFlowStream(c, "v3Tab")
 
-	if err != nil {
-		logger.Error("flow.Run returned with error",
-			slog.String("error", err.Error()),
-		)
-		utils.SendSignalUpdateWithError(c, "v3Tab", "")
-		return
+	// run the flow, forwarding partial notes as they arrive
+	var output *types.WelcomeNoteV3Output
+	for v, err := range flow.Stream(ctx, &formInput) {
+		if err != nil {
+			logger.Error("flow.Stream returned with error",
+				slog.String("error", err.Error()),
+			)
+			stream.Error(flowErrorMessage(err))
+			return
+		}
+		if v.Done {
+			output = v.Output
+			break
+		}
+		// metadata is only known once the full JSON arrives, so clear any stale block
+		stream.Chunk(map[string]interface{}{
+			"result": map[string]interface{}{
+				"note":       v.Stream.Note,
+				"metadata":   nil,
+				"candidates": nil,
+			},
+			"resultJson": "",
+			"streaming":  true,
+			"error":      "",
+		}, v.Stream)
 	}
 
 	logger.Info("generated a new note",
@@ -75,9 +99,12 @@ func V3Handler(c *gin.Context) {
 
 	resultJson, _ := json.MarshalIndent(output, "", "  ")
 
+	model, models := flows.ModelsUsed(ctx)
 	signals := map[string]interface{}{
 		"v3Tab": map[string]interface{}{
 			"result": map[string]interface{}{
+				"model":    model,
+				"models":   models,
 				"note":     output.Note,
 				"occasion": output.Occasion,
 				"language": output.Language,
@@ -91,15 +118,22 @@ func V3Handler(c *gin.Context) {
 					"sentiment":           output.Metadata.Sentiment,
 					"safety":              output.Metadata.Safety,
 					"comments":            output.Metadata.Comments,
+					"promptVersions":      output.Metadata.PromptVersions,
+					"personalizationUsed": output.Metadata.PersonalizationUsed,
+					"locale":              output.Metadata.Locale,
+					"measuredLength":      output.Metadata.MeasuredLength,
+					"languageCheck":       output.Metadata.LanguageCheck,
+					"toneBlend":           output.Metadata.ToneBlend,
+					"inputGuard":          output.Metadata.InputGuard,
+					"pseudonymized":       output.Metadata.Pseudonymized,
 				},
+				"candidates": output.Candidates,
 			},
-			"resultJson": string(resultJson),
-			"error":      "",
+			"selectedCandidate": 0,
+			"resultJson":        string(resultJson),
+			"streaming":         false,
+			"error":             "",
 		},
 	}
-	if isDatastar {
-		utils.SendSignalUpdate(c, signals)
-		return
-	}
-	c.JSON(200, signals["v3Tab"])
+	stream.Result(signals)
 }
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/flows"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/guard"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/locales"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/tones"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+	"github.com/vnaveen-mh/welcome-note-generator/web/config"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// Error codes for requests rejected before any flow runs; policy rejections use
+// the codes in web/config
+const (
+	CodeUnsupportedLocale = "unsupported_locale"
+	CodeUnknownTone       = "unknown_tone"
+	CodeInvalidToneMix    = "invalid_tone_mix"
+)
+
+// validateInput rejects a request whose locale or tone isn't supported, or whose tone
+// or model the deployment policy doesn't allow, before any flow runs. It reports whether
+// the request may continue.
+func validateInput(c *gin.Context, logger *slog.Logger, tabName string, input *types.WelcomeNoteInput) bool {
+	err := flows.ValidateLocale(input)
+	if err == nil {
+		err = flows.ValidateTone(c.Request.Context(), input)
+	}
+	if err == nil {
+		err = flows.ValidateModel(input.Model)
+	}
+	if err == nil {
+		return true
+	}
+
+	code, status := "", http.StatusBadRequest
+	var policyErr *config.PolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		code, status = policyErr.Code, http.StatusForbidden
+	case errors.Is(err, locales.ErrUnsupported):
+		code = CodeUnsupportedLocale
+	case errors.Is(err, tones.ErrNotFound):
+		code = CodeUnknownTone
+	case errors.Is(err, tones.ErrInvalid):
+		code = CodeInvalidToneMix
+	}
+
+	logger.Error("invalid inputs, validation failed",
+		slog.String("error", err.Error()),
+		slog.String("code", code),
+		slog.String("language", input.Language),
+		slog.String("locale", input.Locale),
+		slog.String("tone", input.Tone),
+		slog.Any("toneMix", input.ToneMix),
+		slog.String("model", input.Model),
+	)
+	utils.SendSignalUpdateWithErrorCode(c, tabName, code, err.Error(), status)
+	return false
+}
+
+// validateModel is validateInput for the flows whose input is only free text: it rejects
+// a request picking a model the deployment policy doesn't allow
+func validateModel(c *gin.Context, logger *slog.Logger, tabName string, model string) bool {
+	err := flows.ValidateModel(model)
+	if err == nil {
+		return true
+	}
+
+	code, status := "", http.StatusBadRequest
+	var policyErr *config.PolicyError
+	if errors.As(err, &policyErr) {
+		code, status = policyErr.Code, http.StatusForbidden
+	}
+
+	logger.Error("invalid inputs, validation failed",
+		slog.String("error", err.Error()),
+		slog.String("code", code),
+		slog.String("model", model),
+	)
+	utils.SendSignalUpdateWithErrorCode(c, tabName, code, err.Error(), status)
+	return false
+}
+
+// flowErrorMessage is the error shown for a failed flow. A request the input guard
+// rejected says why; anything else gets the generic message.
+func flowErrorMessage(err error) string {
+	var rejected *guard.RejectedError
+	if errors.As(err, &rejected) {
+		return rejected.Error()
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// AdminAuth is a Gin middleware that lets through only requests carrying the admin
+// token as "Authorization: Bearer <token>"
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			utils.GetLogger(c).Warn("admin request rejected",
+				slog.String("path", c.Request.URL.Path),
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/vnaveen-mh/welcome-note-generator/internal/audit"
 	"github.com/vnaveen-mh/welcome-note-generator/web/constants"
 	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
 )
@@ -26,8 +27,9 @@ func Logger() gin.HandlerFunc {
 			requestID = uuid.New().String()
 			c.Header(constants.RequestIDHeader, requestID)
 		}
-		// store request_id in context
+		// store request_id in context, and in the request's context for the flows' audit log
 		c.Set(constants.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(audit.WithRequestID(c.Request.Context(), requestID))
 
 		isDatastar := utils.IsDatastarRequest(c)
 
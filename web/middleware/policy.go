@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/web/config"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// Policy is a Gin middleware that rejects generate requests for flows the deployment disables
+func Policy(cfg *config.PolicyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tabName := getTabNameFromPath(c.Request.URL.Path)
+		if tabName == "" {
+			c.Next()
+			return
+		}
+
+		if err := cfg.CheckFlow(strings.TrimSuffix(tabName, "Tab")); err != nil {
+			utils.GetLogger(c).Warn("flow rejected by policy",
+				slog.String("path", c.Request.URL.Path),
+				slog.String("error", err.Error()),
+			)
+			policyErr := err.(*config.PolicyError)
+			utils.SendSignalUpdateWithErrorCode(c, tabName, policyErr.Code, policyErr.Message, http.StatusForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -145,6 +145,8 @@ func getTabNameFromPath(path string) string {
 			return "safeTab"
 		case "smart":
 			return "smartTab"
+		case "refine":
+			return "refineTab"
 		}
 	}
 	// Default to empty string if path doesn't match
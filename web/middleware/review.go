@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnaveen-mh/welcome-note-generator/web/utils"
+)
+
+// SharedReviewer is the identity of a reviewer token given without a name
+const SharedReviewer = "reviewer"
+
+// Reviewers maps each reviewer token to the name of the reviewer holding it
+type Reviewers map[string]string
+
+// ParseReviewers parses REVIEW_TOKEN: comma-separated name:token pairs, one per reviewer,
+// such as "priya:s3cret,sam:t0ken". A token without a name is shared, and what is decided
+// with it is signed SharedReviewer.
+func ParseReviewers(spec string) (Reviewers, error) {
+	reviewers := Reviewers{}
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, token, named := strings.Cut(field, ":")
+		if !named {
+			name, token = SharedReviewer, field
+		}
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		switch {
+		case name == "" || token == "":
+			return nil, fmt.Errorf("reviewer token %q: want name:token", field)
+		case reviewers[token] != "":
+			return nil, fmt.Errorf("reviewers %q and %q share a token", reviewers[token], name)
+		}
+		reviewers[token] = name
+	}
+	return reviewers, nil
+}
+
+// reviewerKey is the Gin context key of the reviewer's name
+const reviewerKey = "reviewer"
+
+// ReviewerAuth is a Gin middleware that lets through only requests carrying one of the
+// reviewer tokens as "Authorization: Bearer <token>", and records whose it is for
+// Reviewer. Datastar clients get the error on the review tab.
+func ReviewerAuth(reviewers Reviewers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// compare against every token, so the time taken doesn't tell which one nearly matched
+		name := ""
+		for token, reviewer := range reviewers {
+			if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 && ok {
+				name = reviewer
+			}
+		}
+		if name == "" {
+			utils.GetLogger(c).Warn("reviewer request rejected",
+				slog.String("path", c.Request.URL.Path),
+			)
+			utils.SendSignalUpdateWithErrorCode(c, "reviewTab", "reviewer_token_required", "reviewer token required", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Set(reviewerKey, name)
+		c.Next()
+	}
+}
+
+// Reviewer returns the name of the reviewer ReviewerAuth let through, "" outside it
+func Reviewer(c *gin.Context) string {
+	return c.GetString(reviewerKey)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseReviewers(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    Reviewers
+		wantErr bool
+	}{
+		{"empty", "", Reviewers{}, false},
+		{"shared token", "s3cret", Reviewers{"s3cret": SharedReviewer}, false},
+		{"named tokens", "priya:s3cret, sam:t0ken", Reviewers{"s3cret": "priya", "t0ken": "sam"}, false},
+		{"named and shared", "priya:s3cret,t0ken,", Reviewers{"s3cret": "priya", "t0ken": SharedReviewer}, false},
+		{"no name", ":s3cret", nil, true},
+		{"no token", "priya:", nil, true},
+		{"token used twice", "priya:s3cret,sam:s3cret", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReviewers(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReviewers(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReviewers(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewerAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reviewers := Reviewers{"s3cret": "priya", "t0ken": "sam"}
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		reviewer      string
+	}{
+		{"no token", "", http.StatusUnauthorized, ""},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized, ""},
+		{"token without bearer", "s3cret", http.StatusUnauthorized, ""},
+		{"first reviewer", "Bearer s3cret", http.StatusOK, "priya"},
+		{"second reviewer", "Bearer t0ken", http.StatusOK, "sam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviewer string
+			r := gin.New()
+			r.GET("/", ReviewerAuth(reviewers), func(c *gin.Context) {
+				reviewer = Reviewer(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if reviewer != tt.reviewer {
+				t.Errorf("reviewer = %q, want %q", reviewer, tt.reviewer)
+			}
+		})
+	}
+}
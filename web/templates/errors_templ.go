@@ -153,4 +153,33 @@ func ErrorDisplaySmart() templ.Component {
 	})
 }
 
+func ErrorDisplayRefine() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var6 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var6 == nil {
+			templ_7745c5c3_Var6 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<!-- Error Display --><div data-show=\"$refineTab.error !== ''\" class=\"mt-8 bg-red-50 border-l-4 border-red-500 rounded-lg p-6 shadow-sm animate-fade-in relative group error-card\"><div class=\"flex items-start\"><div class=\"flex-shrink-0\"><svg class=\"h-6 w-6 text-red-500\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v4m0 4h.01M21 12a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg></div><div class=\"ml-3 flex-1\"><h3 class=\"text-sm font-semibold text-red-800 mb-1\">Something went wrong</h3><p class=\"text-sm text-red-700 error-text\" data-text=\"$refineTab.error\"></p></div></div><!-- Copy Error Button --><button type=\"button\" class=\"absolute top-4 right-4 inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$refineTab.copied\" data-class:border-sky-500=\"!$refineTab.copied\" data-class:text-sky-600=\"!$refineTab.copied\" data-class:hover:bg-sky-500=\"!$refineTab.copied\" data-class:hover:text-white=\"!$refineTab.copied\" data-class:bg-emerald-50=\"$refineTab.copied\" data-class:border-emerald-300=\"$refineTab.copied\" data-class:text-emerald-600=\"$refineTab.copied\" data-on:click=\"navigator.clipboard.writeText($refineTab.error); $refineTab.copied = true; setTimeout(() => $refineTab.copied = false, 1000)\" title=\"Copy error\"><i class=\"fas fa-copy text-md\" data-show=\"!$refineTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$refineTab.copied\"></i></button></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
@@ -8,13 +8,46 @@ package templates
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
 
 func buildFormAction(formUrl, csrfToken string) string {
 	return fmt.Sprintf("@post('%s', { contentType: 'form', headers: { 'X-CSRF-Token': '%s' } })", formUrl, csrfToken)
 }
 
-func Index(csrfToken string) templ.Component {
+var safePipelineSteps = []PipelineStep{
+	{ID: "run_base_flow", Label: "Generate note"},
+	{ID: "moderate_and_sanitize", Label: "Moderate and sanitize"},
+	{ID: "remediate_blocked_note", Label: "Regenerate blocked note", Optional: true},
+	{ID: "queue_for_review", Label: "Queue for review", Optional: true},
+	{ID: "await_approval", Label: "Wait for approval", Optional: true},
+}
+
+var refinePipelineSteps = []PipelineStep{
+	{ID: "refine_note", Label: "Revise note"},
+	{ID: "moderate_and_sanitize", Label: "Moderate and sanitize"},
+	{ID: "queue_for_review", Label: "Queue for review", Optional: true},
+}
+
+var smartPipelineSteps = []PipelineStep{
+	{ID: "interpret_description", Label: "Interpret description"},
+	{ID: "generate_welcome_note_v3", Label: "Generate note"},
+	{ID: "moderate_and_sanitize", Label: "Moderate and sanitize"},
+	{ID: "queue_for_review", Label: "Queue for review", Optional: true},
+}
+
+// appSignals returns the page's initial signals; the first enabled flow's tab is active
+func appSignals(enabledFlows []string) string {
+	return fmt.Sprintf("{loading: false, activeTab: '%s', v1Tab: {result: '', error: '', occasion: '', copied: false}, v2Tab: {result: '', error: '', occasion: '', copied: false, streaming: false}, v3Tab: {result: '', error: '', occasion: '', copied: false, streaming: false, selectedCandidate: 0}, safeTab: {result: '', error: '', occasion: '', copied: false, steps: [], selectedCandidate: 0}, smartTab: {result: '', error: '', description: '', copied: false, steps: [], sessionId: '', history: []}, refineTab: {result: '', error: '', copied: false, steps: []}, reviewTab: {items: [], error: '', message: ''}, reviewToken: '', locales: [], defaultLocale: '', tones: [], refineEnabled: %t}", enabledFlows[0], slices.Contains(enabledFlows, "refine"))
+}
+
+// Index renders the demo page with tabs for the flows the deployment policy enables, and
+// the review queue's tab when notes are queued for review
+func Index(csrfToken string, enabledFlows []string, reviewEnabled bool) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -47,111 +80,252 @@ func Index(csrfToken string) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"min-h-screen bg-[var(--bg)]\"><!-- Hero Header --><section class=\"hero-animated border-b border-[var(--border)]\"><div class=\"hero-grid\"><div class=\"hero-grid-lines\"></div><div class=\"hero-beam\"></div></div><div class=\"relative max-w-6xl mx-auto px-4 sm:px-6 lg:px-8 py-16 md:py-24\"><div class=\"inline-flex items-center gap-2 px-4 py-2 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-sm font-semibold border border-[var(--border)] shadow-sm\"><i class=\"fa-solid fa-sparkles\"></i> <span>Powered by Genkit & LLMs</span></div><div class=\"mt-6 grid lg:grid-cols-5 gap-10 items-center\"><div class=\"lg:col-span-3 space-y-6\"><h1 class=\"text-4xl md:text-5xl lg:text-6xl font-bold leading-tight text-[var(--bg-contrast)]\">Welcome Note Generator</h1><p class=\"text-lg text-[var(--muted)] max-w-2xl\">Generate AI-powered welcome messages using Genkit and LLMs. From simple prompts to smart moderation—all streaming in real-time via SSE.</p><div class=\"flex flex-wrap gap-4\"><button type=\"button\" class=\"inline-flex items-center gap-2 px-6 py-3 rounded-xl bg-[var(--accent)] text-white font-semibold shadow-md hover:bg-[var(--accent-strong)] transition-all\" data-on:click=\"document.getElementById('demo').scrollIntoView({behavior:'smooth'});\">Try Live Demo <svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 7l5 5-5 5M6 12h12\"></path></svg></button> <a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"inline-flex items-center gap-2 px-6 py-3 rounded-xl border border-[var(--border)] bg-white text-[var(--bg-contrast)] font-semibold shadow-sm hover:border-[var(--accent)] transition-all\"><i class=\"fa-brands fa-github\"></i> View Source</a></div><div class=\"flex flex-wrap gap-3 text-sm text-[var(--muted)]\"><span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">🔥 Genkit Flows</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">✨ Gemini AI</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">⚡ Real-time SSE</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">🛡️ AI Moderation</span></div></div><div class=\"lg:col-span-2\"><div class=\"card rounded-2xl p-6 backdrop-blur\"><div class=\"flex items-center justify-between mb-4\"><div class=\"text-sm font-semibold text-[var(--muted)]\">Live signal state</div><span class=\"px-3 py-1 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\">Datastar</span></div><div class=\"space-y-3 text-sm\"><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">activeTab</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"$activeTab\"></span></div><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">loading</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"$loading\"></span></div><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">has result</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"!!$result\"></span></div></div><div class=\"mt-5 p-4 rounded-xl bg-[var(--accent-soft)] border border-[var(--border)] text-[var(--accent-strong)] text-sm\"><i class=\"fa-solid fa-wave-square mr-2\"></i> Streaming over SSE — patches arrive as soon as flows finish.</div></div></div></div></div></section><!-- Live Preview Section --><div class=\"py-20 bg-white\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8\"><div class=\"text-center mb-16\"><h2 class=\"text-4xl font-bold text-gray-900 mb-4\">See It In Action</h2><p class=\"text-xl text-gray-600 max-w-3xl mx-auto\">Watch how each flow version handles different use cases, from simple text generation to advanced AI-moderated content with natural language understanding.</p></div><div class=\"grid grid-cols-1 lg:grid-cols-2 gap-8 mb-12\"><!-- Simple Flow Demo --><div class=\"group relative bg-gradient-to-br from-blue-50 to-indigo-50 rounded-2xl p-8 border-2 border-blue-100 hover:border-blue-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-blue-100 text-blue-800 mb-3\">V1 & V2</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Simple & Structured Flows</h3><p class=\"text-gray-600\">Basic string input evolving to rich structured parameters with language, tone, and length control.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-blue-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 3v4M3 5h4M6 17v4m-2-2h4m5-16l2.286 6.857L21 12l-5.714 2.143L13 21l-2.286-6.857L5 12l5.714-2.143L13 3z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Simple to Structured Input</p><p class=\"text-xs text-gray-400 mt-1\">AI-powered text generation</p></div><!--\n\t\t\t\t\t\t\t\tReplace the above div with your GIF:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/v1-v2-demo.gif\" alt=\"V1 and V2 Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm1-12a1 1 0 10-2 0v4a1 1 0 00.293.707l2.828 2.829a1 1 0 101.415-1.415L11 9.586V6z\" clip-rule=\"evenodd\"></path></svg> Response time: ~1-2s</div></div><!-- Metadata Flow Demo --><div class=\"group relative bg-gradient-to-br from-purple-50 to-pink-50 rounded-2xl p-8 border-2 border-purple-100 hover:border-purple-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-purple-100 text-purple-800 mb-3\">V3</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Structured Output Flow</h3><p class=\"text-gray-600\">Returns rich metadata alongside generated content for complete transparency and debugging.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-purple-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Metadata Output</p><p class=\"text-xs text-gray-400 mt-1\">Rich structured responses</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/v3-demo.gif\" alt=\"V3 Metadata Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path d=\"M9 2a1 1 0 000 2h2a1 1 0 100-2H9z\"></path> <path fill-rule=\"evenodd\" d=\"M4 5a2 2 0 012-2 3 3 0 003 3h2a3 3 0 003-3 2 2 0 012 2v11a2 2 0 01-2 2H6a2 2 0 01-2-2V5zm3 4a1 1 0 000 2h.01a1 1 0 100-2H7zm3 0a1 1 0 000 2h3a1 1 0 100-2h-3zm-3 4a1 1 0 100 2h.01a1 1 0 100-2H7zm3 0a1 1 0 100 2h3a1 1 0 100-2h-3z\" clip-rule=\"evenodd\"></path></svg> Includes: Occasion, Language, Length, Tone</div></div></div><div class=\"grid grid-cols-1 lg:grid-cols-2 gap-8\"><!-- Safe Flow Demo --><div class=\"group relative bg-gradient-to-br from-green-50 to-emerald-50 rounded-2xl p-8 border-2 border-green-100 hover:border-green-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-green-100 text-green-800 mb-3\">Safe Flow</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">AI-Moderated Content</h3><p class=\"text-gray-600\">Multi-step flow with content safety checking, toxicity filtering, and automatic sanitization.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-green-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m5.618-4.016A11.955 11.955 0 0112 2.944a11.955 11.955 0 01-8.618 3.04A12.02 12.02 0 003 9c0 5.591 3.824 10.29 9 11.622 5.176-1.332 9-6.03 9-11.622 0-1.042-.133-2.052-.382-3.016z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Content Moderation</p><p class=\"text-xs text-gray-400 mt-1\">AI-powered safety filtering</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/safe-demo.gif\" alt=\"Safe Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M2.166 4.999A11.954 11.954 0 0010 1.944 11.954 11.954 0 0017.834 5c.11.65.166 1.32.166 2.001 0 5.225-3.34 9.67-8 11.317C5.34 16.67 2 12.225 2 7c0-.682.057-1.35.166-2.001zm11.541 3.708a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg> Automatic toxicity detection & sanitization</div></div><!-- Smart Flow Demo --><div class=\"group relative bg-gradient-to-br from-orange-50 to-amber-50 rounded-2xl p-8 border-2 border-orange-100 hover:border-orange-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-orange-100 text-orange-800 mb-3\">Smart Flow</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Natural Language Input</h3><p class=\"text-gray-600\">AI interprets free-form descriptions, extracts parameters, generates content, and moderates—all in one flow.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-orange-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 10V3L4 14h7v7l9-11h-7z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Smart Interpretation</p><p class=\"text-xs text-gray-400 mt-1\">Natural language understanding</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/smart-demo.gif\" alt=\"Smart Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path d=\"M10.394 2.08a1 1 0 00-.788 0l-7 3a1 1 0 000 1.84L5.25 8.051a.999.999 0 01.356-.257l4-1.714a1 1 0 11.788 1.838L7.667 9.088l1.94.831a1 1 0 00.787 0l7-3a1 1 0 000-1.838l-7-3zM3.31 9.397L5 10.12v4.102a8.969 8.969 0 00-1.05-.174 1 1 0 01-.89-.89 11.115 11.115 0 01.25-3.762zM9.3 16.573A9.026 9.026 0 007 14.935v-3.957l1.818.78a3 3 0 002.364 0l5.508-2.361a11.026 11.026 0 01.25 3.762 1 1 0 01-.89.89 8.968 8.968 0 00-5.35 2.524 1 1 0 01-1.4 0zM6 18a1 1 0 001-1v-2.065a8.935 8.935 0 00-2-.712V17a1 1 0 001 1z\"></path></svg> 3-step pipeline: Interpret → Generate → Moderate</div></div></div></div></div><!-- Main Content --><div id=\"demo\" class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-12\" data-signals=\"{loading: false, activeTab: 'v1', v1Tab: {result: '', error: '', occasion: '', copied: false}, v2Tab: {result: '', error: '', occasion: '', copied: false}, v3Tab: {result: '', error: '', occasion: '', copied: false}, safeTab: {result: '', error: '', occasion: '', copied: false}, smartTab: {result: '', error: '', description: '', copied: false}}\" data-scope=\"app\"><!-- Section Header --><div class=\"text-center mb-12\"><h2 class=\"text-3xl font-bold text-gray-900 mb-4\">Try Different Flow Versions</h2><p class=\"text-lg text-gray-600 max-w-3xl mx-auto\">Explore our progressive implementations from simple string I/O to advanced AI-moderated smart flows. Each version builds on the previous, showcasing production-ready patterns.</p></div><!-- Tab Navigation --><div class=\"mb-8\"><nav class=\"flex flex-wrap gap-3 justify-center\">")
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
-			}
-			templ_7745c5c3_Err = TabButton("v1", "V1: Simple", "Basic string input/output").Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"min-h-screen bg-[var(--bg)]\"><!-- Hero Header --><section class=\"hero-animated border-b border-[var(--border)]\"><div class=\"hero-grid\"><div class=\"hero-grid-lines\"></div><div class=\"hero-beam\"></div></div><div class=\"relative max-w-6xl mx-auto px-4 sm:px-6 lg:px-8 py-16 md:py-24\"><div class=\"inline-flex items-center gap-2 px-4 py-2 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-sm font-semibold border border-[var(--border)] shadow-sm\"><i class=\"fa-solid fa-sparkles\"></i> <span>Powered by Genkit & LLMs</span></div><div class=\"mt-6 grid lg:grid-cols-5 gap-10 items-center\"><div class=\"lg:col-span-3 space-y-6\"><h1 class=\"text-4xl md:text-5xl lg:text-6xl font-bold leading-tight text-[var(--bg-contrast)]\">Welcome Note Generator</h1><p class=\"text-lg text-[var(--muted)] max-w-2xl\">Generate AI-powered welcome messages using Genkit and LLMs. From simple prompts to smart moderation—all streaming in real-time via SSE.</p><div class=\"flex flex-wrap gap-4\"><button type=\"button\" class=\"inline-flex items-center gap-2 px-6 py-3 rounded-xl bg-[var(--accent)] text-white font-semibold shadow-md hover:bg-[var(--accent-strong)] transition-all\" data-on:click=\"document.getElementById('demo').scrollIntoView({behavior:'smooth'});\">Try Live Demo <svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 7l5 5-5 5M6 12h12\"></path></svg></button> <a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"inline-flex items-center gap-2 px-6 py-3 rounded-xl border border-[var(--border)] bg-white text-[var(--bg-contrast)] font-semibold shadow-sm hover:border-[var(--accent)] transition-all\"><i class=\"fa-brands fa-github\"></i> View Source</a></div><div class=\"flex flex-wrap gap-3 text-sm text-[var(--muted)]\"><span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">🔥 Genkit Flows</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">✨ Gemini AI</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">⚡ Real-time SSE</span> <span class=\"px-3 py-1 rounded-full bg-white border border-[var(--border)] shadow-sm\">🛡️ AI Moderation</span></div></div><div class=\"lg:col-span-2\"><div class=\"card rounded-2xl p-6 backdrop-blur\"><div class=\"flex items-center justify-between mb-4\"><div class=\"text-sm font-semibold text-[var(--muted)]\">Live signal state</div><span class=\"px-3 py-1 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\">Datastar</span></div><div class=\"space-y-3 text-sm\"><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">activeTab</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"$activeTab\"></span></div><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">loading</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"$loading\"></span></div><div class=\"flex items-center justify-between border border-[var(--border)] rounded-xl px-3 py-2 bg-white\"><span class=\"text-[var(--muted)]\">has result</span> <span class=\"font-semibold text-[var(--bg-contrast)]\" data-text=\"!!$result\"></span></div></div><div class=\"mt-5 p-4 rounded-xl bg-[var(--accent-soft)] border border-[var(--border)] text-[var(--accent-strong)] text-sm\"><i class=\"fa-solid fa-wave-square mr-2\"></i> Streaming over SSE — V2 and V3 notes arrive token by token as the model writes them.</div></div></div></div></div></section><!-- Live Preview Section --><div class=\"py-20 bg-white\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8\"><div class=\"text-center mb-16\"><h2 class=\"text-4xl font-bold text-gray-900 mb-4\">See It In Action</h2><p class=\"text-xl text-gray-600 max-w-3xl mx-auto\">Watch how each flow version handles different use cases, from simple text generation to advanced AI-moderated content with natural language understanding.</p></div><div class=\"grid grid-cols-1 lg:grid-cols-2 gap-8 mb-12\"><!-- Simple Flow Demo --><div class=\"group relative bg-gradient-to-br from-blue-50 to-indigo-50 rounded-2xl p-8 border-2 border-blue-100 hover:border-blue-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-blue-100 text-blue-800 mb-3\">V1 & V2</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Simple & Structured Flows</h3><p class=\"text-gray-600\">Basic string input evolving to rich structured parameters with language, tone, and length control.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-blue-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 3v4M3 5h4M6 17v4m-2-2h4m5-16l2.286 6.857L21 12l-5.714 2.143L13 21l-2.286-6.857L5 12l5.714-2.143L13 3z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Simple to Structured Input</p><p class=\"text-xs text-gray-400 mt-1\">AI-powered text generation</p></div><!--\n\t\t\t\t\t\t\t\tReplace the above div with your GIF:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/v1-v2-demo.gif\" alt=\"V1 and V2 Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm1-12a1 1 0 10-2 0v4a1 1 0 00.293.707l2.828 2.829a1 1 0 101.415-1.415L11 9.586V6z\" clip-rule=\"evenodd\"></path></svg> Response time: ~1-2s</div></div><!-- Metadata Flow Demo --><div class=\"group relative bg-gradient-to-br from-purple-50 to-pink-50 rounded-2xl p-8 border-2 border-purple-100 hover:border-purple-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-purple-100 text-purple-800 mb-3\">V3</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Structured Output Flow</h3><p class=\"text-gray-600\">Returns rich metadata alongside generated content for complete transparency and debugging.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-purple-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Metadata Output</p><p class=\"text-xs text-gray-400 mt-1\">Rich structured responses</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/v3-demo.gif\" alt=\"V3 Metadata Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path d=\"M9 2a1 1 0 000 2h2a1 1 0 100-2H9z\"></path> <path fill-rule=\"evenodd\" d=\"M4 5a2 2 0 012-2 3 3 0 003 3h2a3 3 0 003-3 2 2 0 012 2v11a2 2 0 01-2 2H6a2 2 0 01-2-2V5zm3 4a1 1 0 000 2h.01a1 1 0 100-2H7zm3 0a1 1 0 000 2h3a1 1 0 100-2h-3zm-3 4a1 1 0 100 2h.01a1 1 0 100-2H7zm3 0a1 1 0 100 2h3a1 1 0 100-2h-3z\" clip-rule=\"evenodd\"></path></svg> Includes: Occasion, Language, Length, Tone</div></div></div><div class=\"grid grid-cols-1 lg:grid-cols-2 gap-8\"><!-- Safe Flow Demo --><div class=\"group relative bg-gradient-to-br from-green-50 to-emerald-50 rounded-2xl p-8 border-2 border-green-100 hover:border-green-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-green-100 text-green-800 mb-3\">Safe Flow</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">AI-Moderated Content</h3><p class=\"text-gray-600\">Multi-step flow with content safety checking, toxicity filtering, and automatic sanitization.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-green-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m5.618-4.016A11.955 11.955 0 0112 2.944a11.955 11.955 0 01-8.618 3.04A12.02 12.02 0 003 9c0 5.591 3.824 10.29 9 11.622 5.176-1.332 9-6.03 9-11.622 0-1.042-.133-2.052-.382-3.016z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Content Moderation</p><p class=\"text-xs text-gray-400 mt-1\">AI-powered safety filtering</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/safe-demo.gif\" alt=\"Safe Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M2.166 4.999A11.954 11.954 0 0010 1.944 11.954 11.954 0 0017.834 5c.11.65.166 1.32.166 2.001 0 5.225-3.34 9.67-8 11.317C5.34 16.67 2 12.225 2 7c0-.682.057-1.35.166-2.001zm11.541 3.708a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg> Automatic toxicity detection & sanitization</div></div><!-- Smart Flow Demo --><div class=\"group relative bg-gradient-to-br from-orange-50 to-amber-50 rounded-2xl p-8 border-2 border-orange-100 hover:border-orange-300 transition-all duration-300 hover:shadow-xl\"><div class=\"flex items-start justify-between mb-4\"><div><span class=\"inline-flex items-center px-3 py-1 rounded-full text-xs font-medium bg-orange-100 text-orange-800 mb-3\">Smart Flow</span><h3 class=\"text-2xl font-bold text-gray-900 mb-2\">Natural Language Input</h3><p class=\"text-gray-600\">AI interprets free-form descriptions, extracts parameters, generates content, and moderates—all in one flow.</p></div></div><div class=\"mt-6 bg-white rounded-xl shadow-lg overflow-hidden border border-gray-200 aspect-video flex items-center justify-center\"><!-- Placeholder for GIF/Video --><div class=\"text-center p-8\"><svg class=\"w-16 h-16 mx-auto text-orange-400 mb-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 10V3L4 14h7v7l9-11h-7z\"></path></svg><p class=\"text-sm text-gray-500 font-medium\">Smart Interpretation</p><p class=\"text-xs text-gray-400 mt-1\">Natural language understanding</p></div><!--\n\t\t\t\t\t\t\t\tReplace with:\n\t\t\t\t\t\t\t\t<img src=\"/static/demos/smart-demo.gif\" alt=\"Smart Flow Demo\" class=\"w-full h-full object-cover\" />\n\t\t\t\t\t\t\t\t--></div><div class=\"mt-4 flex items-center text-sm text-gray-500\"><svg class=\"w-4 h-4 mr-2\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path d=\"M10.394 2.08a1 1 0 00-.788 0l-7 3a1 1 0 000 1.84L5.25 8.051a.999.999 0 01.356-.257l4-1.714a1 1 0 11.788 1.838L7.667 9.088l1.94.831a1 1 0 00.787 0l7-3a1 1 0 000-1.838l-7-3zM3.31 9.397L5 10.12v4.102a8.969 8.969 0 00-1.05-.174 1 1 0 01-.89-.89 11.115 11.115 0 01.25-3.762zM9.3 16.573A9.026 9.026 0 007 14.935v-3.957l1.818.78a3 3 0 002.364 0l5.508-2.361a11.026 11.026 0 01.25 3.762 1 1 0 01-.89.89 8.968 8.968 0 00-5.35 2.524 1 1 0 01-1.4 0zM6 18a1 1 0 001-1v-2.065a8.935 8.935 0 00-2-.712V17a1 1 0 001 1z\"></path></svg> 3-step pipeline: Interpret → Generate → Moderate</div></div></div></div></div><!-- Main Content --><div id=\"demo\" class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-12\" data-signals=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = TabButton("v2", "V2: Structured", "Rich input parameters").Render(ctx, templ_7745c5c3_Buffer)
+			var templ_7745c5c3_Var3 string
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(appSignals(enabledFlows))
 			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 254, Col: 43}
 			}
-			templ_7745c5c3_Err = TabButton("v3", "V3: Metadata", "Structured output with info").Render(ctx, templ_7745c5c3_Buffer)
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = TabButton("safe", "Safe Flow", "With content moderation").Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\" data-init=\"@get('/api/locales')\" data-scope=\"app\"><!-- Tone registry for the tone dropdowns; a separate element so it doesn't cancel the locales request --><div class=\"hidden\" data-init=\"@get('/api/tones?allowed=true')\"></div><!-- Section Header --><div class=\"text-center mb-12\"><h2 class=\"text-3xl font-bold text-gray-900 mb-4\">Try Different Flow Versions</h2><p class=\"text-lg text-gray-600 max-w-3xl mx-auto\">Explore our progressive implementations from simple string I/O to advanced AI-moderated smart flows. Each version builds on the previous, showcasing production-ready patterns.</p></div><!-- Tab Navigation --><div class=\"mb-8\"><nav class=\"flex flex-wrap gap-3 justify-center\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = TabButton("smart", "Smart Flow", "Natural language input").Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v1") {
+				templ_7745c5c3_Err = TabButton("v1", "V1: Simple", "Basic string input/output").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</nav></div><!-- Tab Content --><div class=\"bg-white rounded-2xl shadow-xl border border-gray-200 p-8 md:p-12 transition-all duration-200\"><!-- V1 Form --><div data-show=\"$activeTab === 'v1'\">")
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v2") {
+				templ_7745c5c3_Err = TabButton("v2", "V2: Structured", "Rich input parameters").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = FormV1(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v3") {
+				templ_7745c5c3_Err = TabButton("v3", "V3: Metadata", "Structured output with info").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ErrorDisplayV1().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "safe") {
+				templ_7745c5c3_Err = TabButton("safe", "Safe Flow", "With content moderation").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ResultDisplayV1().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "smart") {
+				templ_7745c5c3_Err = TabButton("smart", "Smart Flow", "Natural language input").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</div><!-- V2 Form --><div data-show=\"$activeTab === 'v2'\">")
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "refine") {
+				templ_7745c5c3_Err = TabButton("refine", "Refine", "Iterate on a note").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = FormV2(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if reviewEnabled {
+				templ_7745c5c3_Err = TabButton("review", "Review Queue", "Approve held notes").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ErrorDisplayV2().Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</nav></div><!-- Tab Content --><div class=\"bg-white rounded-2xl shadow-xl border border-gray-200 p-8 md:p-12 transition-all duration-200\"><!-- V1 Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = ResultDisplayV2().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v1") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<div data-show=\"$activeTab === 'v1'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormV1(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplayV1().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplayV1().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div><!-- V3 Form --><div data-show=\"$activeTab === 'v3'\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<!-- V2 Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = FormV3(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v2") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div data-show=\"$activeTab === 'v2'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormV2(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplayV2().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplayV2().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ErrorDisplayV3().Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<!-- V3 Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = ResultDisplayV3().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "v3") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div data-show=\"$activeTab === 'v3'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormV3(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplayV3().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplayV3().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</div><!-- Safe Flow Form --><div data-show=\"$activeTab === 'safe'\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<!-- Safe Flow Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = FormSafe(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "safe") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<div data-show=\"$activeTab === 'safe'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormSafe(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = PipelineView("safeTab", safePipelineSteps).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplaySafe().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplaySafe().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ErrorDisplaySafe().Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<!-- Smart Flow Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = ResultDisplaySafe().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "smart") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<div data-show=\"$activeTab === 'smart'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormSmart(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = PipelineView("smartTab", smartPipelineSteps).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplaySmart().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplaySmart().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div><!-- Smart Flow Form --><div data-show=\"$activeTab === 'smart'\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<!-- Refine Flow Form -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = FormSmart(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if slices.Contains(enabledFlows, "refine") {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<div data-show=\"$activeTab === 'refine'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = FormRefine(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = PipelineView("refineTab", refinePipelineSteps).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ErrorDisplayRefine().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ResultDisplayRefine().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = ErrorDisplaySmart().Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<!-- Review Queue -->")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = ResultDisplaySmart().Render(ctx, templ_7745c5c3_Buffer)
-			if templ_7745c5c3_Err != nil {
-				return templ_7745c5c3_Err
+			if reviewEnabled {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div data-show=\"$activeTab === 'review'\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = ReviewQueue(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "</div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div></div></div><!-- Footer --><footer class=\"mt-20 border-t border-gray-200 bg-white\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-12\"><div class=\"grid grid-cols-1 md:grid-cols-3 gap-8\"><!-- About --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">About This Demo</h3><p class=\"text-base text-gray-600 leading-relaxed\">A comprehensive showcase of Google Genkit's flow orchestration capabilities in Go, demonstrating progressive enhancement from simple to advanced AI implementations.</p></div><!-- Technologies --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">Technologies</h3><ul class=\"space-y-2\"><li><a href=\"https://firebase.google.com/docs/genkit\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Firebase Genkit</a></li><li><a href=\"https://gin-gonic.com/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Gin Web Framework</a></li><li><a href=\"https://templ.guide/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">TEMPL Templates</a></li><li><a href=\"https://data-star.dev/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Datastar Hypermedia</a></li><li><a href=\"https://tailwindcss.com/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Tailwind CSS</a></li></ul></div><!-- Resources --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">Resources</h3><ul class=\"space-y-2\"><li><a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">View Source Code</a></li><li><a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Read Documentation</a></li><li><a href=\"https://ai.google.dev/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Google Gemini API</a></li></ul></div></div><div class=\"mt-8 pt-8 border-t border-gray-200\"><p class=\"text-center text-gray-500 text-sm\">Built with <span class=\"text-red-500\">♥</span> using Go, Genkit, and modern web technologies <span class=\"mx-2\">•</span> <a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-indigo-600 hover:text-indigo-700 font-medium\">View on GitHub</a></p></div></div></footer></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</div></div><!-- Footer --><footer class=\"mt-20 border-t border-gray-200 bg-white\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-12\"><div class=\"grid grid-cols-1 md:grid-cols-3 gap-8\"><!-- About --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">About This Demo</h3><p class=\"text-base text-gray-600 leading-relaxed\">A comprehensive showcase of Google Genkit's flow orchestration capabilities in Go, demonstrating progressive enhancement from simple to advanced AI implementations.</p></div><!-- Technologies --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">Technologies</h3><ul class=\"space-y-2\"><li><a href=\"https://firebase.google.com/docs/genkit\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Firebase Genkit</a></li><li><a href=\"https://gin-gonic.com/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Gin Web Framework</a></li><li><a href=\"https://templ.guide/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">TEMPL Templates</a></li><li><a href=\"https://data-star.dev/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Datastar Hypermedia</a></li><li><a href=\"https://tailwindcss.com/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Tailwind CSS</a></li></ul></div><!-- Resources --><div><h3 class=\"text-sm font-semibold text-gray-900 tracking-wider uppercase mb-4\">Resources</h3><ul class=\"space-y-2\"><li><a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">View Source Code</a></li><li><a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Read Documentation</a></li><li><a href=\"https://ai.google.dev/\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-gray-600 hover:text-indigo-600 transition-colors\">Google Gemini API</a></li></ul></div></div><div class=\"mt-8 pt-8 border-t border-gray-200\"><p class=\"text-center text-gray-500 text-sm\">Built with <span class=\"text-red-500\">♥</span> using Go, Genkit, and modern web technologies <span class=\"mx-2\">•</span> <a href=\"https://github.com/vnaveen-mh/welcome-note-generator\" target=\"_blank\" rel=\"noreferrer noopener\" class=\"text-indigo-600 hover:text-indigo-700 font-medium\">View on GitHub</a></p></div></div></footer></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -181,181 +355,181 @@ func TabButton(id, label, description string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var3 == nil {
-			templ_7745c5c3_Var3 = templ.NopComponent
+		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var4 == nil {
+			templ_7745c5c3_Var4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<button type=\"button\" class=\"group relative px-6 py-4 rounded-2xl border transition-all duration-200 hover:shadow-md bg-white text-[var(--muted)]\" data-class:border-teal-600=\"")
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		var templ_7745c5c3_Var4 string
-		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
-		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 325, Col: 69}
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\" data-class:bg-teal-50=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "<button type=\"button\" class=\"group relative px-6 py-4 rounded-2xl border transition-all duration-200 hover:shadow-md bg-white text-[var(--muted)]\" data-class:border-teal-600=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var5 string
 		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 326, Col: 64}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 404, Col: 69}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\" data-class:shadow-sm=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "\" data-class:bg-teal-50=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 327, Col: 63}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 405, Col: 64}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\" data-class:border-gray-200=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\" data-class:shadow-sm=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var7 string
-		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 328, Col: 69}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 406, Col: 63}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\" data-on:click=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\" data-class:border-gray-200=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var8 string
-		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab = '%s'; $result = ''; $error = ''", id))
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 329, Col: 81}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 407, Col: 69}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\"><div class=\"text-left\"><div class=\"font-semibold text-sm transition-colors\" data-class:text-teal-700=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\" data-on:click=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var9 string
-		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
+		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab = '%s'; $result = ''; $error = ''", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 332, Col: 121}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 408, Col: 81}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\" data-class:text-slate-900=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\"><div class=\"text-left\"><div class=\"font-semibold text-sm transition-colors\" data-class:text-teal-700=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var10 string
-		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
+		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 332, Col: 190}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 411, Col: 121}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\" data-class:text-slate-900=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var11 string
-		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(label)
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 333, Col: 11}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 411, Col: 190}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</div><div class=\"text-xs mt-1 transition-colors\" data-class:text-teal-600=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var12 string
-		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
+		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(label)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 335, Col: 112}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 412, Col: 11}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\" data-class:text-slate-600=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "</div><div class=\"text-xs mt-1 transition-colors\" data-class:text-teal-600=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var13 string
-		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
+		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 335, Col: 181}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 414, Col: 112}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\" data-class:text-slate-600=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var14 string
-		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(description)
+		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 336, Col: 17}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 414, Col: 181}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</div></div><div class=\"absolute bottom-0 left-0 right-0 h-1 bg-teal-500 rounded-b-lg transition-opacity duration-200\" data-class:opacity-100=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var15 string
-		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
+		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(description)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 339, Col: 172}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 415, Col: 17}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" data-class:opacity-0=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</div></div><div class=\"absolute bottom-0 left-0 right-0 h-1 bg-teal-500 rounded-b-lg transition-opacity duration-200\" data-class:opacity-100=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var16 string
-		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
+		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab === '%s'", id))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 339, Col: 236}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 418, Col: 172}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\"></div></button>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "\" data-class:opacity-0=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var17 string
+		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$activeTab !== '%s'", id))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 418, Col: 236}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\"></div></button>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -379,25 +553,25 @@ func FormV1(csrfToken string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var17 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var17 == nil {
-			templ_7745c5c3_Var17 = templ.NopComponent
+		templ_7745c5c3_Var18 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var18 == nil {
+			templ_7745c5c3_Var18 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 1: Simple Flow</h2><p class=\"text-[var(--muted)] mb-2\">Enter any occasion or context, and we'll generate a welcome note. This version is intentionally simple and sends your text directly to the AI.</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Text you enter is sent directly to the AI model and may produce unexpected or nonsensical output, especially for unusual or nonsensical inputs.</p><form data-on:submit=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 1: Simple Flow</h2><p class=\"text-[var(--muted)] mb-2\">Enter any occasion or context, and we'll generate a welcome note. This version is intentionally simple and sends your text directly to the AI.</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Text you enter is sent directly to the AI model and may produce unexpected or nonsensical output, especially for unusual or nonsensical inputs.</p><form data-on:submit=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var18 string
-		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v1/generate", csrfToken))
+		var templ_7745c5c3_Var19 string
+		templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v1/generate", csrfToken))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 357, Col: 66}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 436, Col: 66}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\" data-indicator=\"loading\"><div class=\"mb-6\"><label for=\"occasion-v1\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion or context</label> <input type=\"text\" id=\"occasion-v1\" name=\"occasion\" data-bind=\"occasionV1\" placeholder=\"e.g., birthday party, hotel check-in, new employee, first production deploy\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading || $occasionV1 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Welcome Note</span></button></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "\" data-indicator=\"loading\"><div class=\"mb-6\"><label for=\"occasion-v1\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion or context</label> <input type=\"text\" id=\"occasion-v1\" name=\"occasion\" data-bind=\"occasionV1\" placeholder=\"e.g., birthday party, hotel check-in, new employee, first production deploy\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading || $occasionV1 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Welcome Note</span></button></form></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -421,25 +595,49 @@ func FormV2(csrfToken string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var19 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var19 == nil {
-			templ_7745c5c3_Var19 = templ.NopComponent
+		templ_7745c5c3_Var20 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var20 == nil {
+			templ_7745c5c3_Var20 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 2: Structured Input</h2><p class=\"text-[var(--muted)] mb-2\">Provide a specific occasion and customize the welcome note with language, length, and tone. This version uses structured inputs to give you more control.</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Your text and selections are sent directly to the AI model. Unusual or unclear inputs may still produce creative or unexpected results.</p><form data-on:submit=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 2: Structured Input</h2><p class=\"text-[var(--muted)] mb-2\">Provide a specific occasion and customize the welcome note with language, length, and tone. This version uses structured inputs to give you more control.</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Your text and selections are sent directly to the AI model. Unusual or unclear inputs may still produce creative or unexpected results.</p><form data-on:submit=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var21 string
+		templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v2/generate", csrfToken))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 479, Col: 66}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><!-- Occasion --><div class=\"md:col-span-2\"><label for=\"occasion-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-v2\" name=\"occasion\" data-bind=\"occasionV2\" placeholder=\"e.g., startup closing first deal\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><!-- Language --><div><label for=\"language-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var20 string
-		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v2/generate", csrfToken))
+		templ_7745c5c3_Err = LocaleSelect("language-v2", "languageV2").Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 400, Col: 66}
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "</div><!-- Length --><div><label for=\"length-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-v2\" name=\"length\" data-bind=\"lengthV2\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\" selected>Short (2-5 sentences)</option> <option value=\"medium\">Medium (5–10 sentences)</option> <option value=\"long\">Long (10+ sentences)</option></select></div><!-- Tone --><div class=\"md:col-span-2\"><label for=\"tone-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
+		templ_7745c5c3_Err = ToneSelect("tone-v2", "toneV2", false).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><!-- Occasion --><div class=\"md:col-span-2\"><label for=\"occasion-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-v2\" name=\"occasion\" data-bind=\"occasionV2\" placeholder=\"e.g., startup closing first deal\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><!-- Language --><div><label for=\"language-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label> <select id=\"language-v2\" name=\"language\" data-bind=\"languageV2\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"English\">English</option> <option value=\"Telugu\">Telugu</option> <option value=\"Hindi\">Hindi</option> <option value=\"Spanish\">Spanish</option> <option value=\"French\">French</option> <option value=\"German\">German</option> <option value=\"Japanese\">Japanese</option></select></div><!-- Length --><div><label for=\"length-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-v2\" name=\"length\" data-bind=\"lengthV2\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\" selected>Short (2-5 sentences)</option> <option value=\"medium\">Medium (5–10 sentences)</option> <option value=\"long\">Long (10+ sentences)</option></select></div><!-- Tone --><div class=\"md:col-span-2\"><label for=\"tone-v2\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone</label> <select id=\"tone-v2\" name=\"tone\" data-bind=\"toneV2\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"warm\">Warm</option> <option value=\"formal\">Formal</option> <option value=\"casual\">Casual</option> <option value=\"humorous\">Humorous</option> <option value=\"professional\">Professional</option> <option value=\"poetic\">Poetic</option></select></div></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading || $occasionV2 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Customized Note</span></button></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = PersonalizationFields("v2").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading || $occasionV2 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Customized Note</span></button></form></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -463,25 +661,53 @@ func FormV3(csrfToken string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var21 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var21 == nil {
-			templ_7745c5c3_Var21 = templ.NopComponent
+		templ_7745c5c3_Var22 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var22 == nil {
+			templ_7745c5c3_Var22 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 3: Structured Output</h2><p class=\"text-[var(--muted)] mb-2\">Same as V2, but the flow returns a structured JSON response: the welcome note plus metadata about how it was generated (interpreted occasion, tone, sentiment, safety, etc.).</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Your text and selections are sent directly to the AI model. The response is parsed into typed JSON on the backend so you can inspect both the note and its metadata.</p><form data-on:submit=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Version 3: Structured Output</h2><p class=\"text-[var(--muted)] mb-2\">Same as V2, but the flow returns a structured JSON response: the welcome note plus metadata about how it was generated (interpreted occasion, tone, sentiment, safety, etc.).</p><p class=\"text-xs text-[var(--muted)] mb-6\">Demo only. Your text and selections are sent directly to the AI model. The response is parsed into typed JSON on the backend so you can inspect both the note and its metadata.</p><form data-on:submit=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var23 string
+		templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v3/generate", csrfToken))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 556, Col: 66}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var22 string
-		templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/v3/generate", csrfToken))
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><div class=\"md:col-span-2\"><label for=\"occasion-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-v3\" name=\"occasion\" data-bind=\"occasionV3\" placeholder=\"e.g., Diwali celebration\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div><label for=\"language-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label>")
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 501, Col: 66}
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = LocaleSelect("language-v3", "languageV3").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "</div><div><label for=\"length-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-v3\" name=\"length\" data-bind=\"lengthV3\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\">Short</option> <option value=\"medium\" selected>Medium</option> <option value=\"long\">Long</option></select></div><div class=\"md:col-span-2\"><label for=\"tone-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ToneSelect("tone-v3", "toneV3", false).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = CandidatesField("v3").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
+		templ_7745c5c3_Err = PersonalizationFields("v3").Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><div class=\"md:col-span-2\"><label for=\"occasion-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-v3\" name=\"occasion\" data-bind=\"occasionV3\" placeholder=\"e.g., Diwali celebration\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div><label for=\"language-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label> <select id=\"language-v3\" name=\"language\" data-bind=\"languageV3\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"English\">English</option> <option value=\"Telugu\">Telugu</option> <option value=\"Hindi\">Hindi</option> <option value=\"Spanish\">Spanish</option> <option value=\"French\">French</option> <option value=\"German\">German</option></select></div><div><label for=\"length-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-v3\" name=\"length\" data-bind=\"lengthV3\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\">Short</option> <option value=\"medium\" selected>Medium</option> <option value=\"long\">Long</option></select></div><div class=\"md:col-span-2\"><label for=\"tone-v3\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone</label> <select id=\"tone-v3\" name=\"tone\" data-bind=\"toneV3\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"warm\">Warm</option> <option value=\"formal\">Formal</option> <option value=\"casual\">Casual</option> <option value=\"humorous\">Humorous</option> <option value=\"professional\">Professional</option></select></div></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $occasionV3 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate with Metadata</span></button></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "<button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $occasionV3 === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate with Metadata</span></button></form></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -505,25 +731,565 @@ func FormSafe(csrfToken string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var23 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var23 == nil {
-			templ_7745c5c3_Var23 = templ.NopComponent
+		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var24 == nil {
+			templ_7745c5c3_Var24 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Safe Flow: With Content Moderation</h2><p class=\"text-[var(--muted)] mb-6\">Includes automatic content safety checking and sanitization. Try requesting toxic or inappropriate content to see moderation in action.</p><form data-on:submit=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var25 string
+		templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/safe/generate", csrfToken))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 621, Col: 68}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><div class=\"md:col-span-2\"><label for=\"occasion-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-safe\" name=\"occasion\" data-bind=\"occasionSafe\" placeholder=\"e.g., meetup introduction\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div><label for=\"language-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = LocaleSelect("language-safe", "languageSafe").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "</div><div><label for=\"length-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-safe\" name=\"length\" data-bind=\"lengthSafe\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\" selected>Short</option> <option value=\"medium\">Medium</option> <option value=\"long\">Long</option></select></div><div class=\"md:col-span-2\"><label for=\"tone-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone (Try \"insulting\" or \"sarcastic\" to test moderation)</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ToneSelect("tone-safe", "toneSafe", true).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = CandidatesField("safe").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = PersonalizationFields("safe").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "<button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $occasionSafe === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Safe Note</span></button></form></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func FormRefine(csrfToken string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var26 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var26 == nil {
+			templ_7745c5c3_Var26 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Refine: Iterate on a Note</h2><p class=\"text-[var(--muted)] mb-6\">Paste a note, or send one here from another tab with \"Refine this note\", and say what to change. The revision is moderated like the Safe flow and shown as a diff against the previous version.</p><form data-on:submit=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var27 string
+		templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/refine/generate", csrfToken))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 681, Col: 70}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><div class=\"md:col-span-2\"><label for=\"note-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Note to refine *</label> <textarea id=\"note-refine\" name=\"note\" data-bind=\"refineNote\" rows=\"5\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></textarea></div><div class=\"md:col-span-2\"><label for=\"instruction-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">What should change? *</label> <input type=\"text\" id=\"instruction-refine\" name=\"instruction\" data-bind=\"refineInstruction\" placeholder=\"e.g., make it shorter, add a joke, mention the team lunch\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div class=\"md:col-span-2\"><label for=\"occasion-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Original occasion *</label> <input type=\"text\" id=\"occasion-refine\" name=\"occasion\" data-bind=\"refineOccasion\" placeholder=\"e.g., Diwali celebration\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div><label for=\"language-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = LocaleSelect("language-refine", "refineLanguage").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "</div><div><label for=\"length-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-refine\" name=\"length\" data-bind=\"refineLength\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\">Short</option> <option value=\"medium\" selected>Medium</option> <option value=\"long\">Long</option></select></div><div class=\"md:col-span-2\"><label for=\"tone-refine\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone</label>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ToneSelect("tone-refine", "refineTone", true).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = PersonalizationFields("refine").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "<button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $refineNote === '' || $refineInstruction === '' || $refineOccasion === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Refine Note</span></button></form></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// CandidatesField renders the number of notes to generate and rank
+// localeOptionsExpr fills a language dropdown from the locales signal, keeping the bound
+// selection and falling back to the default locale
+func localeOptionsExpr(bind string) string {
+	return fmt.Sprintf(`const value = $%s || $defaultLocale;
+if (!$locales.length) return;
+el.replaceChildren(...$locales.map(l => {
+	const option = document.createElement('option');
+	option.value = l.tag;
+	option.textContent = l.native && l.native !== l.name ? l.name + ' · ' + l.native : l.name;
+	return option;
+}));
+el.value = value`, bind)
+}
+
+// LocaleSelect is a language dropdown over the supported locales, loaded from /api/locales
+func LocaleSelect(id, bind string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var28 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var28 == nil {
+			templ_7745c5c3_Var28 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "<select id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var29 string
+		templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(id)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 785, Col: 9}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "\" name=\"language\" data-bind=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var30 string
+		templ_7745c5c3_Var30, templ_7745c5c3_Err = templ.JoinStringErrs(bind)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 787, Col: 18}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var30))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var31 string
+		templ_7745c5c3_Var31, templ_7745c5c3_Err = templ.JoinStringErrs(localeOptionsExpr(bind))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 788, Col: 39}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var31))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"\">Default language</option></select>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// toneOptionsExpr fills a tone dropdown from the tones signal, keeping the bound selection.
+// Unless all is set, only tones of the safe class are offered.
+func toneOptionsExpr(bind string, all bool) string {
+	filter := "t.safetyClass === 'safe'"
+	if all {
+		filter = "true"
+	}
+	return fmt.Sprintf(`const value = $%s || 'warm';
+if (!$tones.length) return;
+el.replaceChildren(...$tones.filter(t => %s).map(t => {
+	const option = document.createElement('option');
+	option.value = t.name;
+	option.textContent = t.name.charAt(0).toUpperCase() + t.name.slice(1);
+	option.title = t.description;
+	return option;
+}));
+el.value = value`, bind, filter)
+}
+
+// ToneSelect is a tone dropdown over the tone registry, loaded from /api/tones.
+// all also offers sensitive and offensive tones, for the moderated tabs.
+func ToneSelect(id, bind string, all bool) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var32 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var32 == nil {
+			templ_7745c5c3_Var32 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "<select id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var33 string
+		templ_7745c5c3_Var33, templ_7745c5c3_Err = templ.JoinStringErrs(id)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 818, Col: 9}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var33))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "\" name=\"tone\" data-bind=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var34 string
+		templ_7745c5c3_Var34, templ_7745c5c3_Err = templ.JoinStringErrs(bind)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 820, Col: 18}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var34))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var35 string
+		templ_7745c5c3_Var35, templ_7745c5c3_Err = templ.JoinStringErrs(toneOptionsExpr(bind, all))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 821, Col: 42}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var35))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"warm\">Warm</option></select>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func CandidatesField(suffix string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var36 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var36 == nil {
+			templ_7745c5c3_Var36 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "<div class=\"mb-6\"><label for=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var37 string
+		templ_7745c5c3_Var37, templ_7745c5c3_Err = templ.JoinStringErrs("candidates-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 830, Col: 37}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var37))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Candidates</label> <select id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var38 string
+		templ_7745c5c3_Var38, templ_7745c5c3_Err = templ.JoinStringErrs("candidates-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 834, Col: 30}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var38))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "\" name=\"candidates\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for n := 1; n <= types.MaxCandidates; n++ {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "<option value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var39 string
+			templ_7745c5c3_Var39, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(n))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 839, Col: 33}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var39))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if n == 1 {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "1 note")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			} else {
+				var templ_7745c5c3_Var40 string
+				templ_7745c5c3_Var40, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(n))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 843, Col: 21}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var40))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, " notes, ranked")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, "</option>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, "</select><p class=\"text-xs text-[var(--muted)] mt-2\">More than one generates notes in parallel and ranks them by tone, length and language. The result is not streamed.</p></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// PersonalizationFields renders the optional recipient/sender inputs shared by the structured forms.
+// suffix keeps element ids unique across tabs; the field names match types.WelcomeNoteInput.
+func PersonalizationFields(suffix string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var41 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var41 == nil {
+			templ_7745c5c3_Var41 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Safe Flow: With Content Moderation</h2><p class=\"text-[var(--muted)] mb-6\">Includes automatic content safety checking and sanitization. Try requesting toxic or inappropriate content to see moderation in action.</p><form data-on:submit=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, "<details class=\"mb-6 rounded-xl border border-[var(--border)] bg-[var(--surface-soft)] p-4\"><summary class=\"cursor-pointer text-sm font-semibold text-[var(--bg-contrast)]\">Personalize (optional)</summary><p class=\"text-xs text-[var(--muted)] mt-2 mb-4\">Names and details are only used when provided. The model is told not to invent any.</p><div class=\"grid grid-cols-1 md:grid-cols-2 gap-4\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = personalizationInput("recipients", suffix, "Recipients", "e.g., Priya, Raj").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = personalizationInput("sender", suffix, "Sender", "e.g., Anna").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = personalizationInput("relationship", suffix, "Relationship", "e.g., manager, teammate").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = personalizationInput("organization", suffix, "Organization", "e.g., Platform team at Acme").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, "<div class=\"md:col-span-2\"><label for=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var42 string
+		templ_7745c5c3_Var42, templ_7745c5c3_Err = templ.JoinStringErrs("signature-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 870, Col: 38}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var42))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 80, "\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Signature</label> <textarea id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var43 string
+		templ_7745c5c3_Var43, templ_7745c5c3_Err = templ.JoinStringErrs("signature-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 874, Col: 31}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var43))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 81, "\" name=\"signature\" rows=\"2\" placeholder=\"e.g., Warm regards, Anna\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"></textarea></div></div></details>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func personalizationInput(name, suffix, label, placeholder string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var44 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var44 == nil {
+			templ_7745c5c3_Var44 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 82, "<div><label for=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var45 string
+		templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.JoinStringErrs(name + "-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 887, Col: 34}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var45))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 83, "\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var46 string
+		templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(label)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 888, Col: 10}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 84, "</label> <input type=\"text\" id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var47 string
+		templ_7745c5c3_Var47, templ_7745c5c3_Err = templ.JoinStringErrs(name + "-" + suffix)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 892, Col: 27}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 85, "\" name=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var48 string
+		templ_7745c5c3_Var48, templ_7745c5c3_Err = templ.JoinStringErrs(name)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 893, Col: 14}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var48))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 86, "\" placeholder=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var24 string
-		templ_7745c5c3_Var24, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/safe/generate", csrfToken))
+		var templ_7745c5c3_Var49 string
+		templ_7745c5c3_Var49, templ_7745c5c3_Err = templ.JoinStringErrs(placeholder)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 587, Col: 68}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 894, Col: 28}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var24))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var49))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\" data-indicator=\"loading\"><div class=\"grid grid-cols-1 md:grid-cols-2 gap-6 mb-6\"><div class=\"md:col-span-2\"><label for=\"occasion-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Occasion *</label> <input type=\"text\" id=\"occasion-safe\" name=\"occasion\" data-bind=\"occasionSafe\" placeholder=\"e.g., meetup introduction\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></div><div><label for=\"language-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Language</label> <select id=\"language-safe\" name=\"language\" data-bind=\"languageSafe\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"English\">English</option> <option value=\"English\">Telugu</option> <option value=\"English\">Hindi</option> <option value=\"Spanish\">Spanish</option> <option value=\"French\">French</option></select></div><div><label for=\"length-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Length</label> <select id=\"length-safe\" name=\"length\" data-bind=\"lengthSafe\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"short\" selected>Short</option> <option value=\"medium\">Medium</option> <option value=\"long\">Long</option></select></div><div class=\"md:col-span-2\"><label for=\"tone-safe\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Tone (Try \"insulting\" or \"sarcastic\" to test moderation)</label> <select id=\"tone-safe\" name=\"tone\" data-bind=\"toneSafe\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"><option value=\"warm\">Warm</option> <option value=\"formal\">Formal</option> <option value=\"casual\">Casual</option> <option value=\"humorous\">Humorous</option> <option value=\"insulting\">Insulting</option> <option value=\"sarcastic\">Sarcastic</option></select></div></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $occasionSafe === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Safe Note</span></button></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 87, "\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -547,25 +1313,25 @@ func FormSmart(csrfToken string) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var25 == nil {
-			templ_7745c5c3_Var25 = templ.NopComponent
+		templ_7745c5c3_Var50 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var50 == nil {
+			templ_7745c5c3_Var50 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Smart Flow: Natural Language Input</h2><p class=\"text-[var(--muted)] mb-6\">Just describe what you want in plain English. The AI will interpret your request, generate the note, and moderate it.</p><form data-on:submit=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 88, "<div><h2 class=\"text-2xl font-semibold mb-2 text-[var(--bg-contrast)]\">Smart Flow: Natural Language Input</h2><p class=\"text-[var(--muted)] mb-6\">Just describe what you want in plain English. The AI will interpret your request, generate the note, and moderate it.</p><form data-on:submit=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var26 string
-		templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/smart/generate", csrfToken))
+		var templ_7745c5c3_Var51 string
+		templ_7745c5c3_Var51, templ_7745c5c3_Err = templ.JoinStringErrs(buildFormAction("/api/smart/generate", csrfToken))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 665, Col: 69}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/index.templ`, Line: 905, Col: 69}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var51))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\" data-indicator=\"loading\"><div class=\"mb-6\"><label for=\"description-smart\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Describe what you need</label> <textarea id=\"description-smart\" name=\"description\" data-bind=\"descriptionSmart\" rows=\"4\" placeholder=\"Example: 'Write a warm and professional welcome message for our new software engineer joining next Monday. Keep it friendly but not too casual.'\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></textarea><p class=\"mt-2 text-sm text-[var(--muted)]\">The AI will automatically extract the occasion, tone, length, and language from your description.</p></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $descriptionSmart === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Smart Note</span></button></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 89, "\" data-indicator=\"loading\"><div class=\"mb-6\"><label for=\"description-smart\" class=\"block text-sm font-semibold text-[var(--bg-contrast)] mb-2\">Describe what you need</label> <textarea id=\"description-smart\" name=\"description\" data-bind=\"descriptionSmart\" rows=\"4\" placeholder=\"Example: 'Write a warm and professional welcome message for our new software engineer joining next Monday. Keep it friendly but not too casual.'\" class=\"w-full px-4 py-3 border border-[var(--border)] rounded-xl focus:ring-2 focus:ring-[var(--accent)] focus:border-transparent bg-white\" required></textarea><p class=\"mt-2 text-sm text-[var(--muted)]\">The AI will automatically extract the occasion, tone, length, and language from your description.</p></div><!-- Conversation: follow-ups amend the previous interpretation --><input type=\"hidden\" name=\"sessionId\" data-attr:value=\"$smartTab.sessionId\"><div class=\"mb-6 flex items-center justify-between gap-4 rounded-xl border border-sky-200 bg-sky-50/80 p-4 text-sm\" data-show=\"$smartTab.sessionId\"><p class=\"text-sky-800\"><i class=\"fas fa-comments mr-2\"></i> Continuing a conversation of <span class=\"font-semibold\" data-text=\"Math.ceil($smartTab.history.length / 2)\"></span> turn(s). Follow-ups like \"same but in Spanish\" or \"more formal\" amend the last note.</p><button type=\"button\" class=\"shrink-0 px-3 py-1.5 rounded-lg border border-sky-400 text-xs font-semibold text-sky-700 hover:bg-sky-500 hover:text-white transition-colors\" data-on:click=\"$smartTab.sessionId = ''; $smartTab.history = []; $smartTab.result = ''; $smartTab.steps = []\">New conversation</button></div><button type=\"submit\" class=\"w-full bg-[var(--accent)] text-white py-3 px-6 rounded-xl font-semibold hover:bg-[var(--accent-strong)] transition-colors shadow-sm disabled:opacity-50 disabled:cursor-not-allowed\" data-attr:disabled=\"$loading  || $descriptionSmart === ''\"><i class=\"fas fa-circle-notch fa-spin mr-2\" data-show=\"$loading\"></i> <span>Generate Smart Note</span></button></form></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -8,6 +8,203 @@ package templates
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vnaveen-mh/welcome-note-generator/internal/types"
+)
+
+// PipelineStep identifies one genkit.Run step of a multi-step flow in the UI.
+type PipelineStep struct {
+	ID       string // step name reported by the flow
+	Label    string
+	Optional bool // only shown once the flow reports it
+}
+
+// stepExpr returns a Datastar expression reading a field of the named step's progress
+func stepExpr(tabName, stepID, field string) string {
+	return fmt.Sprintf("$%s.steps?.find(s => s.step === '%s')?.%s", tabName, stepID, field)
+}
+
+// candidateExpr returns a Datastar expression reading a field of the i-th ranked candidate
+func candidateExpr(tabName string, i int, field string) string {
+	return fmt.Sprintf("$%s.result.candidates?.[%d]?.%s", tabName, i, field)
+}
+
+// pickCandidateExpr shows the i-th candidate as the tab's note. Moderated tabs also
+// take over the candidate's safety info.
+func pickCandidateExpr(tabName string, i int, moderated bool) string {
+	expr := fmt.Sprintf("$%s.result.note = %s; $%s.result.metadata = %s; ",
+		tabName, candidateExpr(tabName, i, "note"), tabName, candidateExpr(tabName, i, "metadata"))
+	if moderated {
+		for _, field := range []string{"blocked", "moderationNote", "originalNote", "decision", "decisionReason"} {
+			expr += fmt.Sprintf("$%s.result.%s = %s ?? ''; ", tabName, field, candidateExpr(tabName, i, field))
+		}
+		for _, field := range []string{"categories", "changedSpans", "votes"} {
+			expr += fmt.Sprintf("$%s.result.%s = %s ?? []; ", tabName, field, candidateExpr(tabName, i, field))
+		}
+		expr += fmt.Sprintf("$%s.result.pivot = %s ?? null; ", tabName, candidateExpr(tabName, i, "pivot"))
+	}
+	return expr + fmt.Sprintf("$%s.selectedCandidate = %d", tabName, i)
+}
+
+// candidateDisabledExpr disables picking the selected candidate, and blocked ones on moderated tabs
+func candidateDisabledExpr(tabName string, i int, moderated bool) string {
+	expr := fmt.Sprintf("$%s.selectedCandidate === %d", tabName, i)
+	if moderated {
+		expr += " || " + candidateExpr(tabName, i, "blocked")
+	}
+	return expr
+}
+
+// refineHandoffExpr sends the tab's note to the Refine tab, along with the inputs it was
+// generated from. From the Refine tab itself only the note is carried over.
+func refineHandoffExpr(tabName string) string {
+	expr := fmt.Sprintf("$refineNote = $%s.result.note; ", tabName)
+	if tabName != "refineTab" {
+		for _, field := range []string{"Occasion", "Length"} {
+			expr += fmt.Sprintf("$refine%s = $%s.result.%s || $refine%s; ", field, tabName, strings.ToLower(field), field)
+		}
+		expr += fmt.Sprintf("$refineTone = ($%s.result.tone || '').toLowerCase() || $refineTone; ", tabName)
+		// the dropdown holds locale tags, not the language name the model reports
+		expr += fmt.Sprintf("$refineLanguage = $%s.result.metadata?.locale || $refineLanguage; ", tabName)
+	}
+	return expr + "$refineInstruction = ''; $refineTab.result = ''; $refineTab.steps = []; $activeTab = 'refine'; window.scrollTo({top: document.getElementById('demo').offsetTop, behavior: 'smooth'})"
+}
+
+// diffEffectExpr renders a refine diff into the element as ins/del/span nodes
+const diffEffectExpr = `el.replaceChildren(...($refineTab.result.diff || []).map(s => {
+	const node = document.createElement(s.op === 'insert' ? 'ins' : s.op === 'delete' ? 'del' : 'span');
+	node.textContent = s.text;
+	node.className = s.op === 'insert' ? 'bg-emerald-100 text-emerald-800 no-underline' : s.op === 'delete' ? 'bg-red-100 text-red-700' : '';
+	return node;
+}))`
+
+// historyEffectExpr renders the Smart conversation history into the element
+const historyEffectExpr = `el.replaceChildren(...($smartTab.history || []).map(t => {
+	const node = document.createElement('div');
+	node.className = t.role === 'user' ? 'rounded-lg p-3 bg-sky-50 border border-sky-200 text-sky-900' : 'rounded-lg p-3 bg-white border border-[var(--border)] text-[var(--bg-contrast)] whitespace-pre-line';
+	const who = document.createElement('div');
+	who.className = 'text-xs font-semibold uppercase mb-1 opacity-70';
+	who.textContent = t.role === 'user' ? 'You' : 'Note';
+	const text = document.createElement('div');
+	text.textContent = t.text;
+	node.append(who, text);
+	return node;
+}))`
+
+// moderationCategoriesEffectExpr renders the tab's moderation categories as severity bars
+func moderationCategoriesEffectExpr(tabName string) string {
+	return fmt.Sprintf(`el.replaceChildren(...($%s.result.categories || []).map(c => {
+	const row = document.createElement('div');
+	row.className = 'flex items-center gap-3';
+	const name = document.createElement('span');
+	name.className = 'w-24 text-xs font-semibold uppercase opacity-70';
+	name.textContent = c.category.replace('_', '-');
+	const bar = document.createElement('div');
+	bar.className = 'flex-1 h-2 rounded-full bg-slate-100 overflow-hidden';
+	const fill = document.createElement('div');
+	fill.className = 'h-full rounded-full ' + (c.severity >= 0.7 ? 'bg-red-500' : c.severity >= 0.3 ? 'bg-amber-500' : 'bg-emerald-500');
+	fill.style.width = Math.round(c.severity * 100) + '%%';
+	bar.append(fill);
+	const value = document.createElement('span');
+	value.className = 'w-10 text-right text-xs tabular-nums';
+	value.textContent = c.severity.toFixed(2);
+	row.append(name, bar, value);
+	return row;
+}))`, tabName)
+}
+
+// remediationEffectExpr lists the tab's remediation attempts with their moderation decisions
+func remediationEffectExpr(tabName string) string {
+	return fmt.Sprintf(`el.replaceChildren(...($%s.result.remediation || []).map(a => {
+	const item = document.createElement('li');
+	const reason = a.moderationNote || a.decisionReason;
+	item.textContent = 'Attempt ' + a.attempt + ': ' + a.decision + (reason ? ' (' + reason + ')' : '');
+	return item;
+}))`, tabName)
+}
+
+// votesEffectExpr lists each ensemble moderator's vote on the tab's note
+func votesEffectExpr(tabName string) string {
+	return fmt.Sprintf(`el.replaceChildren(...($%s.result.votes || []).map(v => {
+	const item = document.createElement('li');
+	const verdict = document.createElement('span');
+	verdict.className = 'font-semibold ' + (v.error ? 'text-gray-500' : v.blocked ? 'text-red-700' : 'text-emerald-700');
+	verdict.textContent = v.error ? 'abstained' : v.blocked ? 'block' : 'allow';
+	const detail = document.createElement('span');
+	detail.className = 'text-xs opacity-70';
+	detail.textContent = ' weight ' + v.weight + (v.durationMs ? ', ' + v.durationMs + ' ms' : '') + ((v.error || v.moderationNote) ? ' (' + (v.error || v.moderationNote) + ')' : '');
+	item.append(v.moderator + ': ', verdict, detail);
+	return item;
+}))`, tabName)
+}
+
+// changedSpansEffectExpr lists the parts of the tab's original note that moderation replaced
+func changedSpansEffectExpr(tabName string) string {
+	return fmt.Sprintf(`el.replaceChildren(...($%s.result.changedSpans || []).map(s => {
+	const item = document.createElement('li');
+	const before = document.createElement('del');
+	before.className = 'bg-red-100 text-red-700';
+	before.textContent = s.original;
+	const after = document.createElement('ins');
+	after.className = 'bg-emerald-100 text-emerald-800 no-underline';
+	after.textContent = s.replacement || '(removed)';
+	const at = document.createElement('span');
+	at.className = 'text-xs opacity-60';
+	at.textContent = ' chars ' + s.start + '–' + s.end;
+	item.append(before, ' → ', after, at);
+	return item;
+}))`, tabName)
+}
+
+// measuredLengthExpr shows the locally counted sentences and words of the tab's note
+func measuredLengthExpr(tabName string) string {
+	m := fmt.Sprintf("$%s.result.metadata?.measuredLength", tabName)
+	return fmt.Sprintf("%s?.sentences + ' sentences, ' + %s?.words + ' words'", m, m)
+}
+
+// measuredRangeExpr shows whether the note is within its length range and how many attempts it took
+func measuredRangeExpr(tabName string) string {
+	m := fmt.Sprintf("$%s.result.metadata?.measuredLength", tabName)
+	return fmt.Sprintf("(%s?.inRange ? 'within ' : 'outside ') + %s?.minSentences + (%s?.maxSentences ? '–' + %s?.maxSentences : '+') + ' sentences' + (%s?.attempts > 1 ? ', ' + %s?.attempts + ' attempts' : '')", m, m, m, m, m, m)
+}
+
+// languageCheckExpr shows the language detected in the tab's note
+func languageCheckExpr(tabName string) string {
+	c := fmt.Sprintf("$%s.result.metadata?.languageCheck", tabName)
+	return fmt.Sprintf("(%s?.detected || 'undetermined') + (%s?.detected ? ' (' + Math.round(%s?.confidence * 100) + '%%)' : '')", c, c, c)
+}
+
+// toneBlendExpr shows the tone mix the tab's note was requested in, such as "70% professional, 30% humorous"
+func toneBlendExpr(tabName string) string {
+	return fmt.Sprintf("'aimed for ' + ($%s.result.metadata?.toneBlend || []).map(w => Math.round(w.weight * 100) + '%% ' + w.tone).join(', ')", tabName)
+}
+
+// inputGuardExpr shows what the input guard did with the request, e.g. "neutralized (score 0.6)"
+func inputGuardExpr(tabName string) string {
+	v := fmt.Sprintf("$%s.result.metadata?.inputGuard", tabName)
+	return fmt.Sprintf("({allow: 'passed', neutralize: 'neutralized', reject: 'rejected'})[%s?.action] + ' (score ' + %s?.score + ')'", v, v)
+}
+
+// inputGuardSignalsExpr lists the distinct signals the input guard found, or the classifier's reason
+func inputGuardSignalsExpr(tabName string) string {
+	v := fmt.Sprintf("$%s.result.metadata?.inputGuard", tabName)
+	return fmt.Sprintf("[...new Set((%s?.findings || []).map(f => f.field + ': ' + f.signal))].join(', ') || %s?.classifier?.reason || ''", v, v)
+}
+
+// pseudonymizedExpr lists the personal data kept from the model, such as "2 person, 1 email"
+func pseudonymizedExpr(tabName string) string {
+	return fmt.Sprintf("Object.entries($%s.result.metadata?.pseudonymized || {}).map(([kind, n]) => n + ' ' + kind.replace('_', ' ')).join(', ')", tabName)
+}
+
+// languageStatusExpr shows whether the detected language matches the requested one and how many attempts it took
+func languageStatusExpr(tabName string) string {
+	c := fmt.Sprintf("$%s.result.metadata?.languageCheck", tabName)
+	return fmt.Sprintf("({match: 'matches ', mismatch: 'does not match ', unverified: 'could not verify '})[%s?.status] + %s?.requested + (%s?.attempts > 1 ? ', ' + %s?.attempts + ' attempts' : '')", c, c, c, c)
+}
+
 func ResultDisplayV1() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -29,7 +226,15 @@ func ResultDisplayV1() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div data-show=\"$v1Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v1Tab.result.note || $v1Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v1Tab.copied\" data-class:border-sky-500=\"!$v1Tab.copied\" data-class:text-sky-600=\"!$v1Tab.copied\" data-class:hover:bg-sky-500=\"!$v1Tab.copied\" data-class:hover:text-white=\"!$v1Tab.copied\" data-class:bg-emerald-50=\"$v1Tab.copied\" data-class:border-emerald-300=\"$v1Tab.copied\" data-class:text-emerald-600=\"$v1Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v1Tab.result.note || $v1Tab.result.Note); $v1Tab.copied = true; setTimeout(() => $v1Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v1Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v1Tab.copied\"></i></button></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div data-show=\"$v1Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v1Tab.result.note || $v1Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v1Tab.copied\" data-class:border-sky-500=\"!$v1Tab.copied\" data-class:text-sky-600=\"!$v1Tab.copied\" data-class:hover:bg-sky-500=\"!$v1Tab.copied\" data-class:hover:text-white=\"!$v1Tab.copied\" data-class:bg-emerald-50=\"$v1Tab.copied\" data-class:border-emerald-300=\"$v1Tab.copied\" data-class:text-emerald-600=\"$v1Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v1Tab.result.note || $v1Tab.result.Note); $v1Tab.copied = true; setTimeout(() => $v1Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v1Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v1Tab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("v1Tab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -58,7 +263,15 @@ func ResultDisplayV2() templ.Component {
 			templ_7745c5c3_Var2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div data-show=\"$v2Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v2Tab.result.note || $v2Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v2Tab.copied\" data-class:border-sky-500=\"!$v2Tab.copied\" data-class:text-sky-600=\"!$v2Tab.copied\" data-class:hover:bg-sky-500=\"!$v2Tab.copied\" data-class:hover:text-white=\"!$v2Tab.copied\" data-class:bg-emerald-50=\"$v2Tab.copied\" data-class:border-emerald-300=\"$v2Tab.copied\" data-class:text-emerald-600=\"$v2Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v2Tab.result.note || $v2Tab.result.Note); $v2Tab.copied = true; setTimeout(() => $v2Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v2Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v2Tab.copied\"></i></button></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div data-show=\"$v2Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note <span class=\"ml-3 inline-flex items-center gap-2 px-3 py-1 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\" data-show=\"$v2Tab.streaming\"><i class=\"fas fa-circle-notch fa-spin\"></i> Streaming…</span></h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v2Tab.result.note || $v2Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v2Tab.copied\" data-class:border-sky-500=\"!$v2Tab.copied\" data-class:text-sky-600=\"!$v2Tab.copied\" data-class:hover:bg-sky-500=\"!$v2Tab.copied\" data-class:hover:text-white=\"!$v2Tab.copied\" data-class:bg-emerald-50=\"$v2Tab.copied\" data-class:border-emerald-300=\"$v2Tab.copied\" data-class:text-emerald-600=\"$v2Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v2Tab.result.note || $v2Tab.result.Note); $v2Tab.copied = true; setTimeout(() => $v2Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v2Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v2Tab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("v2Tab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -87,7 +300,127 @@ func ResultDisplayV3() templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div data-show=\"$v3Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v3Tab.result.note || $v3Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v3Tab.copied\" data-class:border-sky-500=\"!$v3Tab.copied\" data-class:text-sky-600=\"!$v3Tab.copied\" data-class:hover:bg-sky-500=\"!$v3Tab.copied\" data-class:hover:text-white=\"!$v3Tab.copied\" data-class:bg-emerald-50=\"$v3Tab.copied\" data-class:border-emerald-300=\"$v3Tab.copied\" data-class:text-emerald-600=\"$v3Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v3Tab.result.note || $v3Tab.result.Note); $v3Tab.copied = true; setTimeout(() => $v3Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v3Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v3Tab.copied\"></i></button></div></div><!-- Generation Details + Metadata --><div data-show=\"$v3Tab.result\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.tone\"></div></div></div><!-- Model metadata from structured output --><div class=\"mt-2\" data-show=\"$v3Tab.result.metadata || $v3Tab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.interpretedOccasion || $v3Tab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveLanguage || $v3Tab.result.Metadata?.EffectiveLanguage\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveLength || $v3Tab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveTone || $v3Tab.result.Metadata?.EffectiveTone\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.sentiment || $v3Tab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.safety || $v3Tab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.comments || $v3Tab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.comments || $v3Tab.result.Metadata?.Comments\"></div></div></div></div><!-- Raw JSON for developers --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$v3Tab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$v3Tab.resultJson\"></pre></details></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div data-show=\"$v3Tab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note <span class=\"ml-3 inline-flex items-center gap-2 px-3 py-1 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\" data-show=\"$v3Tab.streaming\"><i class=\"fas fa-circle-notch fa-spin\"></i> Streaming…</span></h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$v3Tab.result.note || $v3Tab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$v3Tab.copied\" data-class:border-sky-500=\"!$v3Tab.copied\" data-class:text-sky-600=\"!$v3Tab.copied\" data-class:hover:bg-sky-500=\"!$v3Tab.copied\" data-class:hover:text-white=\"!$v3Tab.copied\" data-class:bg-emerald-50=\"$v3Tab.copied\" data-class:border-emerald-300=\"$v3Tab.copied\" data-class:text-emerald-600=\"$v3Tab.copied\" data-on:click=\"navigator.clipboard.writeText($v3Tab.result.note || $v3Tab.result.Note); $v3Tab.copied = true; setTimeout(() => $v3Tab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$v3Tab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$v3Tab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("v3Tab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = RefineButton("v3Tab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = CandidatesView("v3Tab", false).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<!-- Generation Details + Metadata --><div data-show=\"$v3Tab.result && !$v3Tab.streaming\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.tone\"></div></div></div><!-- Model metadata from structured output --><div class=\"mt-2\" data-show=\"$v3Tab.result.metadata || $v3Tab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.interpretedOccasion || $v3Tab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveLanguage || $v3Tab.result.Metadata?.EffectiveLanguage\"></div><div class=\"text-xs mt-1 text-violet-700\" data-show=\"$v3Tab.result.metadata?.locale\" data-text=\"'requested as ' + $v3Tab.result.metadata?.locale\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveLength || $v3Tab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.effectiveTone || $v3Tab.result.Metadata?.EffectiveTone\"></div><div class=\"text-xs mt-1 text-violet-700\" data-show=\"$v3Tab.result.metadata?.toneBlend?.length\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(toneBlendExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 397, Col: 42}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.sentiment || $v3Tab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.safety || $v3Tab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.comments || $v3Tab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$v3Tab.result.metadata?.comments || $v3Tab.result.Metadata?.Comments\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.measuredLength\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Measured length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(measuredLengthExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 429, Col: 97}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$v3Tab.result.metadata?.measuredLength?.inRange\" data-class:text-amber-700=\"!$v3Tab.result.metadata?.measuredLength?.inRange\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(measuredRangeExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 434, Col: 46}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.languageCheck\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Detected language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var7 string
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(languageCheckExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 442, Col: 96}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$v3Tab.result.metadata?.languageCheck?.status === 'match'\" data-class:text-red-700=\"$v3Tab.result.metadata?.languageCheck?.status === 'mismatch'\" data-class:text-amber-700=\"$v3Tab.result.metadata?.languageCheck?.status === 'unverified'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var8 string
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(languageStatusExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 448, Col: 47}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.inputGuard\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Input guard</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var9 string
+		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 456, Col: 93}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\"></div><div class=\"text-xs mt-1 text-amber-700\" data-show=\"$v3Tab.result.metadata?.inputGuard?.action !== 'allow'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var10 string
+		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardSignalsExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 460, Col: 50}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$$v3Tab.result.metadata?.pseudonymized\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Kept from the model</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var11 string
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(pseudonymizedExpr("v3Tab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 468, Col: 96}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\"></div><div class=\"text-xs mt-1 text-slate-600\">Sent as placeholders, restored in the note</div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.personalizationUsed?.length\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Personalization used</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"($v3Tab.result.metadata?.personalizationUsed || []).join(', ')\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$v3Tab.result.metadata?.promptVersions\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Prompt versions</div><div class=\"font-mono text-sm text-[var(--bg-contrast)]\" data-text=\"Object.entries($v3Tab.result.metadata?.promptVersions || {}).map(([name, version]) => name + '@' + version).join(', ')\"></div></div></div></div><!-- Raw JSON for developers --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$v3Tab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$v3Tab.resultJson\"></pre></details></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -111,12 +444,148 @@ func ResultDisplaySafe() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var4 == nil {
-			templ_7745c5c3_Var4 = templ.NopComponent
+		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var12 == nil {
+			templ_7745c5c3_Var12 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<div data-show=\"$safeTab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$safeTab.result.note || $safeTab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$safeTab.copied\" data-class:border-sky-500=\"!$safeTab.copied\" data-class:text-sky-600=\"!$safeTab.copied\" data-class:hover:bg-sky-500=\"!$safeTab.copied\" data-class:hover:text-white=\"!$safeTab.copied\" data-class:bg-emerald-50=\"$safeTab.copied\" data-class:border-emerald-300=\"$safeTab.copied\" data-class:text-emerald-600=\"$safeTab.copied\" data-on:click=\"navigator.clipboard.writeText($safeTab.result.note || $safeTab.result.Note); $safeTab.copied = true; setTimeout(() => $safeTab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$safeTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$safeTab.copied\"></i></button></div></div><!-- Generation Details + Metadata + JSON --><div data-show=\"$safeTab.result\"><div data-show=\"$safeTab.result.occasion || $safeTab.result.Occasion\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.occasion || $safeTab.result.Occasion\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.occasion || $safeTab.result.Occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.language || $safeTab.result.Language\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.language || $safeTab.result.Language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.length || $safeTab.result.Length\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.length || $safeTab.result.Length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.tone || $safeTab.result.Tone\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.tone || $safeTab.result.Tone\"></div></div></div></div><!-- Optional model metadata if provided by flow --><div class=\"mt-2\" data-show=\"$safeTab.result.metadata || $safeTab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.interpretedOccasion || $safeTab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveLanguage || $safeTab.result.Metadata?.EffectiveLanguage\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveLength || $safeTab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveTone || $safeTab.result.Metadata?.EffectiveTone\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.sentiment || $safeTab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.safety || $safeTab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.comments || $safeTab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.comments || $safeTab.result.Metadata?.Comments\"></div></div></div></div><!-- Raw JSON for developers --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$safeTab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$safeTab.resultJson\"></pre></details></div><!-- Moderation Info (Safe Flow) --><div data-show=\"$safeTab.result && ($safeTab.result.moderationNote || $safeTab.result.originalNote || $safeTab.result.blocked)\"><!-- Sanitized case: originalNote exists (note was modified) --><div data-show=\"$safeTab.result.originalNote\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\">Content was sanitized</h5><p class=\"text-sm text-amber-700 mt-1\" data-text=\"$safeTab.result.moderationNote || 'Some parts of the original note were adjusted for safety.'\"></p><!-- Original note (before sanitization) --><details class=\"bg-white border border-amber-200 rounded-lg p-3 group mt-3\"><summary class=\"flex items-center justify-between cursor-pointer\"><span class=\"flex items-center gap-2 text-sm font-semibold text-amber-800\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v8m-4-4h8\"></path></svg> View original note (flagged)</span> <svg class=\"w-4 h-4 text-amber-700 group-open:rotate-90 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></summary><p class=\"text-xs text-amber-700 mt-2\">Original text before sanitization:</p><div class=\"mt-2 bg-amber-50 border border-amber-200 rounded-lg p-3 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$safeTab.result.originalNote\"></div></details></div></div></div><!-- Hard-blocked case: no originalNote and blocked == true --><div data-show=\"!$safeTab.result.originalNote && $safeTab.result.blocked\" class=\"bg-red-50 border border-red-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-red-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm-1-5h2v2H9v-2zm0-6h2v4H9V7z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-red-800\">Content blocked</h5><p class=\"text-sm text-red-700 mt-1\" data-text=\"$safeTab.result.moderationNote || 'This note was blocked by the safety filter.'\"></p><p class=\"text-xs text-red-700 mt-1\">No safe version could be generated from the original text.</p></div></div></div><!-- Passed case: no originalNote and blocked == false --><div data-show=\"!$safeTab.result.originalNote && !$safeTab.result.blocked\" class=\"bg-emerald-50 border border-emerald-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-emerald-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-emerald-800\">Content Safety Check Passed</h5><p class=\"text-sm text-emerald-700 mt-1\">This note passed content safety filters. No changes were required.</p><p class=\"text-xs text-emerald-700 mt-1\" data-show=\"$safeTab.result.moderationNote\" data-text=\"$safeTab.result.moderationNote\"></p></div></div></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<div data-show=\"$safeTab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$safeTab.result.note || $safeTab.result.Note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$safeTab.copied\" data-class:border-sky-500=\"!$safeTab.copied\" data-class:text-sky-600=\"!$safeTab.copied\" data-class:hover:bg-sky-500=\"!$safeTab.copied\" data-class:hover:text-white=\"!$safeTab.copied\" data-class:bg-emerald-50=\"$safeTab.copied\" data-class:border-emerald-300=\"$safeTab.copied\" data-class:text-emerald-600=\"$safeTab.copied\" data-on:click=\"navigator.clipboard.writeText($safeTab.result.note || $safeTab.result.Note); $safeTab.copied = true; setTimeout(() => $safeTab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$safeTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$safeTab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("safeTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = RefineButton("safeTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = CandidatesView("safeTab", true).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<!-- Generation Details + Metadata + JSON --><div data-show=\"$safeTab.result\"><div data-show=\"$safeTab.result.occasion || $safeTab.result.Occasion\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.occasion || $safeTab.result.Occasion\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.occasion || $safeTab.result.Occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.language || $safeTab.result.Language\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.language || $safeTab.result.Language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.length || $safeTab.result.Length\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.length || $safeTab.result.Length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$safeTab.result.tone || $safeTab.result.Tone\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.tone || $safeTab.result.Tone\"></div></div></div></div><!-- Optional model metadata if provided by flow --><div class=\"mt-2\" data-show=\"$safeTab.result.metadata || $safeTab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.interpretedOccasion || $safeTab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveLanguage || $safeTab.result.Metadata?.EffectiveLanguage\"></div><div class=\"text-xs mt-1 text-violet-700\" data-show=\"$safeTab.result.metadata?.locale\" data-text=\"'requested as ' + $safeTab.result.metadata?.locale\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveLength || $safeTab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.effectiveTone || $safeTab.result.Metadata?.EffectiveTone\"></div><div class=\"text-xs mt-1 text-violet-700\" data-show=\"$safeTab.result.metadata?.toneBlend?.length\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var13 string
+		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(toneBlendExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 615, Col: 44}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.sentiment || $safeTab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.safety || $safeTab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.comments || $safeTab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$safeTab.result.metadata?.comments || $safeTab.result.Metadata?.Comments\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.measuredLength\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Measured length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var14 string
+		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(measuredLengthExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 647, Col: 99}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$safeTab.result.metadata?.measuredLength?.inRange\" data-class:text-amber-700=\"!$safeTab.result.metadata?.measuredLength?.inRange\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var15 string
+		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(measuredRangeExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 652, Col: 48}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.languageCheck\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Detected language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var16 string
+		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(languageCheckExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 660, Col: 98}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$safeTab.result.metadata?.languageCheck?.status === 'match'\" data-class:text-red-700=\"$safeTab.result.metadata?.languageCheck?.status === 'mismatch'\" data-class:text-amber-700=\"$safeTab.result.metadata?.languageCheck?.status === 'unverified'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var17 string
+		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(languageStatusExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 666, Col: 49}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.inputGuard\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Input guard</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var18 string
+		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 674, Col: 95}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "\"></div><div class=\"text-xs mt-1 text-amber-700\" data-show=\"$safeTab.result.metadata?.inputGuard?.action !== 'allow'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var19 string
+		templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardSignalsExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 678, Col: 52}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$$safeTab.result.metadata?.pseudonymized\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Kept from the model</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var20 string
+		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(pseudonymizedExpr("safeTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 686, Col: 98}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "\"></div><div class=\"text-xs mt-1 text-slate-600\">Sent as placeholders, restored in the note</div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.personalizationUsed?.length\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Personalization used</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"($safeTab.result.metadata?.personalizationUsed || []).join(', ')\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$safeTab.result.metadata?.promptVersions\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Prompt versions</div><div class=\"font-mono text-sm text-[var(--bg-contrast)]\" data-text=\"Object.entries($safeTab.result.metadata?.promptVersions || {}).map(([name, version]) => name + '@' + version).join(', ')\"></div></div></div></div><!-- Raw JSON for developers --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$safeTab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$safeTab.resultJson\"></pre></details></div><!-- Moderation Info (Safe Flow) --><div data-show=\"$safeTab.result && ($safeTab.result.moderationNote || $safeTab.result.originalNote || $safeTab.result.blocked)\"><!-- Sanitized case: originalNote exists (note was modified) --><div data-show=\"$safeTab.result.originalNote\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\">Content was sanitized</h5><p class=\"text-sm text-amber-700 mt-1\" data-text=\"$safeTab.result.moderationNote || 'Some parts of the original note were adjusted for safety.'\"></p><!-- Original note (before sanitization) --><details class=\"bg-white border border-amber-200 rounded-lg p-3 group mt-3\"><summary class=\"flex items-center justify-between cursor-pointer\"><span class=\"flex items-center gap-2 text-sm font-semibold text-amber-800\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v8m-4-4h8\"></path></svg> View original note (flagged)</span> <svg class=\"w-4 h-4 text-amber-700 group-open:rotate-90 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></summary><p class=\"text-xs text-amber-700 mt-2\">Original text before sanitization:</p><div class=\"mt-2 bg-amber-50 border border-amber-200 rounded-lg p-3 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$safeTab.result.originalNote\"></div></details></div></div></div><!-- Hard-blocked case: no originalNote and blocked == true --><div data-show=\"!$safeTab.result.originalNote && $safeTab.result.blocked\" class=\"bg-red-50 border border-red-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-red-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm-1-5h2v2H9v-2zm0-6h2v4H9V7z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-red-800\">Content blocked</h5><p class=\"text-sm text-red-700 mt-1\" data-text=\"$safeTab.result.moderationNote || 'This note was blocked by the safety filter.'\"></p><p class=\"text-xs text-red-700 mt-1\">No safe version could be generated from the original text.</p></div></div></div><!-- Passed case: no originalNote and blocked == false --><div data-show=\"!$safeTab.result.originalNote && !$safeTab.result.blocked && $safeTab.result.decision !== 'review' && !$safeTab.result.fallback\" class=\"bg-emerald-50 border border-emerald-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-emerald-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-emerald-800\">Content Safety Check Passed</h5><p class=\"text-sm text-emerald-700 mt-1\">This note passed content safety filters. No changes were required.</p><p class=\"text-xs text-emerald-700 mt-1\" data-show=\"$safeTab.result.moderationNote\" data-text=\"$safeTab.result.moderationNote\"></p></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationRemediation("safeTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationReview("safeTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationDetails("safeTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -140,12 +609,1384 @@ func ResultDisplaySmart() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var5 == nil {
-			templ_7745c5c3_Var5 = templ.NopComponent
+		templ_7745c5c3_Var21 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var21 == nil {
+			templ_7745c5c3_Var21 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div data-show=\"$smartTab.result\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note (Smart Flow)</h3><!-- Main Note (sanitized or original if safe) --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$smartTab.result.note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$smartTab.copied\" data-class:border-sky-500=\"!$smartTab.copied\" data-class:text-sky-600=\"!$smartTab.copied\" data-class:hover:bg-sky-500=\"!$smartTab.copied\" data-class:hover:text-white=\"!$smartTab.copied\" data-class:bg-emerald-50=\"$smartTab.copied\" data-class:border-emerald-300=\"$smartTab.copied\" data-class:text-emerald-600=\"$smartTab.copied\" data-on:click=\"navigator.clipboard.writeText($smartTab.result.note); $smartTab.copied = true; setTimeout(() => $smartTab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$smartTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$smartTab.copied\"></i></button></div></div><!-- Interpretation: raw description + parsed input --><div class=\"mb-6\" data-show=\"$smartTab.result.rawDescription || $smartTab.result.parsedInput\"><h4 class=\"font-semibold text-[var(--accent)] mb-2\">How the AI interpreted your description</h4><div class=\"grid grid-cols-1 md:grid-cols-3 gap-4\"><!-- Raw description --><div class=\"rounded-xl p-4 border border-sky-200 bg-sky-50/80 shadow-sm md:col-span-1\"><div class=\"text-xs font-semibold uppercase text-sky-700 mb-1\">Original description</div><p class=\"text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.rawDescription\"></p></div><!-- Parsed / structured interpretation --><div class=\"rounded-xl p-4 border border-emerald-200 bg-emerald-50/80 shadow-sm md:col-span-2\"><div class=\"text-xs font-semibold uppercase text-emerald-700 mb-2\">Interpreted as</div><div class=\"grid grid-cols-2 md:grid-cols-4 gap-3 text-sm\"><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.occasion || $smartTab.result.occasion\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.language || $smartTab.result.language\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.length || $smartTab.result.length\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.tone || $smartTab.result.tone\"></div></div></div></div></div></div><!-- Generation Details + Metadata + JSON --><div data-show=\"$smartTab.result\"><div data-show=\"$smartTab.result.occasion\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.occasion\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.language\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.length\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.tone\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.tone\"></div></div></div></div><!-- Optional model metadata --><div class=\"mt-2\" data-show=\"$smartTab.result.metadata || $smartTab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.interpretedOccasion || $smartTab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveLanguage || $smartTab.result.Metadata?.EffectiveLanguage\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveLength || $smartTab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveTone || $smartTab.result.Metadata?.EffectiveTone\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.sentiment || $smartTab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.safety || $smartTab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.comments || $smartTab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.comments || $smartTab.result.Metadata?.Comments\"></div></div></div></div><!-- Raw JSON --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$smartTab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$smartTab.resultJson\"></pre></details></div><!-- Moderation Info (reusing Safe Flow semantics) --><div data-show=\"$smartTab.result && ($smartTab.result.moderationNote || $smartTab.result.originalNote || $smartTab.result.blocked)\"><!-- Sanitized case: originalNote exists --><div data-show=\"$smartTab.result.originalNote\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\">Content was sanitized</h5><p class=\"text-sm text-amber-700 mt-1\" data-text=\"$smartTab.result.moderationNote || 'Some parts of the original note were adjusted for safety.'\"></p><details class=\"bg-white border border-amber-200 rounded-lg p-3 group mt-3\"><summary class=\"flex items-center justify-between cursor-pointer\"><span class=\"flex items-center gap-2 text-sm font-semibold text-amber-800\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v8m-4-4h8\"></path></svg> View original note (flagged)</span> <svg class=\"w-4 h-4 text-amber-700 group-open:rotate-90 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></summary><p class=\"text-xs text-amber-700 mt-2\">Original text before sanitization:</p><div class=\"mt-2 bg-amber-50 border border-amber-200 rounded-lg p-3 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.originalNote\"></div></details></div></div></div><!-- Hard-blocked case --><div data-show=\"!$smartTab.result.originalNote && $smartTab.result.blocked\" class=\"bg-red-50 border border-red-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-red-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm-1-5h2v2H9v-2zm0-6h2v4H9V7z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-red-800\">Content blocked</h5><p class=\"text-sm text-red-700 mt-1\" data-text=\"$smartTab.result.moderationNote || 'This note was blocked by the safety filter.'\"></p><p class=\"text-xs text-red-700 mt-1\">No safe version could be generated from the original text.</p></div></div></div><!-- Passed case --><div data-show=\"!$smartTab.result.originalNote && !$smartTab.result.blocked\" class=\"bg-emerald-50 border border-emerald-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-emerald-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-emerald-800\">Content Safety Check Passed</h5><p class=\"text-sm text-emerald-700 mt-1\">This note passed content safety filters. No changes were required.</p><p class=\"text-xs text-emerald-700 mt-1\" data-show=\"$smartTab.result.moderationNote\" data-text=\"$smartTab.result.moderationNote\"></p></div></div></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<div data-show=\"$smartTab.result\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Welcome Note (Smart Flow)</h3><!-- Main Note (sanitized or original if safe) --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$smartTab.result.note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$smartTab.copied\" data-class:border-sky-500=\"!$smartTab.copied\" data-class:text-sky-600=\"!$smartTab.copied\" data-class:hover:bg-sky-500=\"!$smartTab.copied\" data-class:hover:text-white=\"!$smartTab.copied\" data-class:bg-emerald-50=\"$smartTab.copied\" data-class:border-emerald-300=\"$smartTab.copied\" data-class:text-emerald-600=\"$smartTab.copied\" data-on:click=\"navigator.clipboard.writeText($smartTab.result.note); $smartTab.copied = true; setTimeout(() => $smartTab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$smartTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$smartTab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("smartTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = RefineButton("smartTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<!-- Conversation history (sessions) --><details class=\"mb-6 rounded-xl border border-sky-200 bg-sky-50/40 p-4\" data-show=\"$smartTab.history?.length > 2\"><summary class=\"text-sm font-semibold cursor-pointer text-sky-800\">Conversation so far</summary><div class=\"mt-3 space-y-2 text-sm\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var22 string
+		templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(historyEffectExpr)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 887, Col: 71}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\"></div></details><!-- Interpretation: raw description + parsed input --><div class=\"mb-6\" data-show=\"$smartTab.result.rawDescription || $smartTab.result.parsedInput\"><h4 class=\"font-semibold text-[var(--accent)] mb-2\">How the AI interpreted your description <span class=\"ml-2 inline-flex items-center px-2 py-0.5 rounded-full bg-sky-100 text-sky-700 text-xs font-semibold\" data-show=\"$smartTab.result.amended\">Amended previous turn</span></h4><div class=\"grid grid-cols-1 md:grid-cols-3 gap-4\"><!-- Raw description --><div class=\"rounded-xl p-4 border border-sky-200 bg-sky-50/80 shadow-sm md:col-span-1\"><div class=\"text-xs font-semibold uppercase text-sky-700 mb-1\">Original description</div><p class=\"text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.rawDescription\"></p></div><!-- Parsed / structured interpretation --><div class=\"rounded-xl p-4 border border-emerald-200 bg-emerald-50/80 shadow-sm md:col-span-2\"><div class=\"text-xs font-semibold uppercase text-emerald-700 mb-2\">Interpreted as</div><div class=\"grid grid-cols-2 md:grid-cols-4 gap-3 text-sm\"><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.occasion || $smartTab.result.occasion\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"($smartTab.result.parsedInput?.language || $smartTab.result.language) + ($smartTab.result.parsedInput?.locale ? ' (' + $smartTab.result.parsedInput.locale + ')' : '')\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.length || $smartTab.result.length\"></div></div><div><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.parsedInput?.tone || $smartTab.result.tone\"></div></div><div data-show=\"$smartTab.result.parsedInput?.recipients\"><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Recipients</div><div class=\"font-medium text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.parsedInput?.recipients\"></div></div><div data-show=\"$smartTab.result.parsedInput?.sender\"><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Sender</div><div class=\"font-medium text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.parsedInput?.sender\"></div></div><div data-show=\"$smartTab.result.parsedInput?.relationship\"><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Relationship</div><div class=\"font-medium text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.parsedInput?.relationship\"></div></div><div data-show=\"$smartTab.result.parsedInput?.organization\"><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Organization</div><div class=\"font-medium text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.parsedInput?.organization\"></div></div><div data-show=\"$smartTab.result.parsedInput?.signature\"><div class=\"text-emerald-700 text-xs font-semibold uppercase mb-1\">Signature</div><div class=\"font-medium text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.parsedInput?.signature\"></div></div></div></div></div></div><!-- Generation Details + Metadata + JSON --><div data-show=\"$smartTab.result\"><div data-show=\"$smartTab.result.occasion\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Generation Details</h4><div class=\"grid grid-cols-2 md:grid-cols-4 gap-4 mb-6\"><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.occasion\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.occasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.language\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.language\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.length\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.length\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-[var(--accent)] bg-[var(--surface-soft)]\" data-show=\"$smartTab.result.tone\"><div class=\"text-xs font-semibold text-[var(--accent)] mb-1 uppercase\">Tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.tone\"></div></div></div></div><!-- Optional model metadata --><div class=\"mt-2\" data-show=\"$smartTab.result.metadata || $smartTab.result.Metadata\"><h4 class=\"font-semibold text-violet-800 mb-3\">Model Metadata</h4><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4 mb-4\"><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Interpreted occasion</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.interpretedOccasion || $smartTab.result.Metadata?.InterpretedOccasion\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveLanguage || $smartTab.result.Metadata?.EffectiveLanguage\"></div><div class=\"text-xs mt-1 text-violet-700\" data-show=\"$smartTab.result.metadata?.locale\" data-text=\"'requested as ' + $smartTab.result.metadata?.locale\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveLength || $smartTab.result.Metadata?.EffectiveLength\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Effective tone</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.effectiveTone || $smartTab.result.Metadata?.EffectiveTone\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Sentiment</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.sentiment || $smartTab.result.Metadata?.Sentiment\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Safety</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.safety || $smartTab.result.Metadata?.Safety\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.comments || $smartTab.result.Metadata?.Comments\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Comments</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"$smartTab.result.metadata?.comments || $smartTab.result.Metadata?.Comments\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.measuredLength\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Measured length</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var23 string
+		templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs(measuredLengthExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1081, Col: 100}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$smartTab.result.metadata?.measuredLength?.inRange\" data-class:text-amber-700=\"!$smartTab.result.metadata?.measuredLength?.inRange\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var24 string
+		templ_7745c5c3_Var24, templ_7745c5c3_Err = templ.JoinStringErrs(measuredRangeExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1086, Col: 49}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var24))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.languageCheck\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Detected language</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var25 string
+		templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinStringErrs(languageCheckExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1094, Col: 99}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\"></div><div class=\"text-xs mt-1\" data-class:text-emerald-700=\"$smartTab.result.metadata?.languageCheck?.status === 'match'\" data-class:text-red-700=\"$smartTab.result.metadata?.languageCheck?.status === 'mismatch'\" data-class:text-amber-700=\"$smartTab.result.metadata?.languageCheck?.status === 'unverified'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var26 string
+		templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(languageStatusExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1100, Col: 50}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.inputGuard\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Input guard</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var27 string
+		templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1108, Col: 96}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "\"></div><div class=\"text-xs mt-1 text-amber-700\" data-show=\"$smartTab.result.metadata?.inputGuard?.action !== 'allow'\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var28 string
+		templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(inputGuardSignalsExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1112, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$$smartTab.result.metadata?.pseudonymized\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Kept from the model</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var29 string
+		templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(pseudonymizedExpr("smartTab"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1120, Col: 99}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "\"></div><div class=\"text-xs mt-1 text-slate-600\">Sent as placeholders, restored in the note</div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.personalizationUsed?.length\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Personalization used</div><div class=\"font-medium text-[var(--bg-contrast)]\" data-text=\"($smartTab.result.metadata?.personalizationUsed || []).join(', ')\"></div></div><div class=\"rounded-xl p-4 shadow-sm border border-violet-200 bg-violet-50/80\" data-show=\"$smartTab.result.metadata?.promptVersions\"><div class=\"text-xs font-semibold text-violet-700 mb-1 uppercase\">Prompt versions</div><div class=\"font-mono text-sm text-[var(--bg-contrast)]\" data-text=\"Object.entries($smartTab.result.metadata?.promptVersions || {}).map(([name, version]) => name + '@' + version).join(', ')\"></div></div></div></div><!-- Raw JSON --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$smartTab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$smartTab.resultJson\"></pre></details></div><!-- Moderation Info (reusing Safe Flow semantics) --><div data-show=\"$smartTab.result && ($smartTab.result.moderationNote || $smartTab.result.originalNote || $smartTab.result.blocked)\"><!-- Sanitized case: originalNote exists --><div data-show=\"$smartTab.result.originalNote\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\">Content was sanitized</h5><p class=\"text-sm text-amber-700 mt-1\" data-text=\"$smartTab.result.moderationNote || 'Some parts of the original note were adjusted for safety.'\"></p><details class=\"bg-white border border-amber-200 rounded-lg p-3 group mt-3\"><summary class=\"flex items-center justify-between cursor-pointer\"><span class=\"flex items-center gap-2 text-sm font-semibold text-amber-800\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v8m-4-4h8\"></path></svg> View original note (flagged)</span> <svg class=\"w-4 h-4 text-amber-700 group-open:rotate-90 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></summary><p class=\"text-xs text-amber-700 mt-2\">Original text before sanitization:</p><div class=\"mt-2 bg-amber-50 border border-amber-200 rounded-lg p-3 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$smartTab.result.originalNote\"></div></details></div></div></div><!-- Hard-blocked case --><div data-show=\"!$smartTab.result.originalNote && $smartTab.result.blocked\" class=\"bg-red-50 border border-red-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-red-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm-1-5h2v2H9v-2zm0-6h2v4H9V7z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-red-800\">Content blocked</h5><p class=\"text-sm text-red-700 mt-1\" data-text=\"$smartTab.result.moderationNote || 'This note was blocked by the safety filter.'\"></p><p class=\"text-xs text-red-700 mt-1\">No safe version could be generated from the original text.</p></div></div></div><!-- Passed case --><div data-show=\"!$smartTab.result.originalNote && !$smartTab.result.blocked && $smartTab.result.decision !== 'review'\" class=\"bg-emerald-50 border border-emerald-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-emerald-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-emerald-800\">Content Safety Check Passed</h5><p class=\"text-sm text-emerald-700 mt-1\">This note passed content safety filters. No changes were required.</p><p class=\"text-xs text-emerald-700 mt-1\" data-show=\"$smartTab.result.moderationNote\" data-text=\"$smartTab.result.moderationNote\"></p></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationReview("smartTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationDetails("smartTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// RefineButton sends the tab's note to the Refine tab
+func RefineButton(tabName string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var30 == nil {
+			templ_7745c5c3_Var30 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<div class=\"flex justify-end -mt-4 mb-6\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var31 string
+		templ_7745c5c3_Var31, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$refineEnabled && $%s.result.note && !$%s.streaming", tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1276, Col: 138}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var31))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\"><button type=\"button\" class=\"inline-flex items-center gap-2 px-4 py-2 rounded-lg border border-[var(--accent)] text-sm font-semibold text-[var(--accent)] hover:bg-[var(--accent)] hover:text-white transition-colors\" data-on:click=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var32 string
+		templ_7745c5c3_Var32, templ_7745c5c3_Err = templ.JoinStringErrs(refineHandoffExpr(tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1280, Col: 45}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var32))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "\"><i class=\"fas fa-pen-to-square\"></i> ")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if tabName == "refineTab" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "Refine again")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "Refine this note")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "</button></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func ResultDisplayRefine() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var33 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var33 == nil {
+			templ_7745c5c3_Var33 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "<div data-show=\"$refineTab.result !== ''\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-8 card\"><div class=\"h-1 w-16 rounded-full bg-[var(--accent)] mb-6\"></div><h3 class=\"text-2xl font-semibold text-[var(--bg-contrast)] mb-6 flex items-center\"><svg class=\"w-8 h-8 mr-3 text-[var(--accent)]\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Your Revised Note</h3><!-- Main Note --><div class=\"bg-white rounded-xl p-6 shadow-sm border border-[var(--border)] mb-6 note-card\"><div class=\"flex items-start justify-between gap-2\"><div class=\"prose prose-lg max-w-none text-[var(--bg-contrast)] note-text flex-1 min-w-0\" data-text=\"$refineTab.result.note\"></div><button type=\"button\" class=\"inline-flex items-center justify-center p-2.5 rounded-lg border font-semibold transition-all duration-200 shrink-0\" data-class:bg-sky-50=\"!$refineTab.copied\" data-class:border-sky-500=\"!$refineTab.copied\" data-class:text-sky-600=\"!$refineTab.copied\" data-class:hover:bg-sky-500=\"!$refineTab.copied\" data-class:hover:text-white=\"!$refineTab.copied\" data-class:bg-emerald-50=\"$refineTab.copied\" data-class:border-emerald-300=\"$refineTab.copied\" data-class:text-emerald-600=\"$refineTab.copied\" data-on:click=\"navigator.clipboard.writeText($refineTab.result.note); $refineTab.copied = true; setTimeout(() => $refineTab.copied = false, 1000)\" title=\"Copy note\"><i class=\"fas fa-copy text-md\" data-show=\"!$refineTab.copied\"></i> <i class=\"fas fa-check text-md\" data-show=\"$refineTab.copied\"></i></button></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModelsUsed("refineTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = RefineButton("refineTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "<!-- Changes + Diff --><div class=\"mb-6\" data-show=\"$refineTab.result.diff\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Changes</h4><div class=\"rounded-xl p-4 border border-sky-200 bg-sky-50/80 shadow-sm mb-4\" data-show=\"$refineTab.result.changes\"><div class=\"text-xs font-semibold uppercase text-sky-700 mb-1\">Summary</div><p class=\"text-sm text-[var(--bg-contrast)]\" data-text=\"$refineTab.result.changes\"></p></div><div class=\"rounded-xl p-4 border border-[var(--border)] bg-white shadow-sm\"><div class=\"text-xs font-semibold uppercase text-[var(--muted)] mb-2\">Diff against the previous version</div><div class=\"text-sm leading-relaxed text-[var(--bg-contrast)] whitespace-pre-line\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var34 string
+		templ_7745c5c3_Var34, templ_7745c5c3_Err = templ.JoinStringErrs(diffEffectExpr)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1339, Col: 116}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var34))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "\"></div></div></div><!-- Moderation Info --><div data-show=\"$refineTab.result && ($refineTab.result.moderationNote || $refineTab.result.originalNote || $refineTab.result.blocked)\"><!-- Sanitized case: originalNote exists (note was modified) --><div data-show=\"$refineTab.result.originalNote\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M8.257 3.099c.765-1.36 2.722-1.36 3.486 0l5.58 9.92c.75 1.334-.213 2.98-1.742 2.98H4.42c-1.53 0-2.493-1.646-1.743-2.98l5.58-9.92zM11 13a1 1 0 11-2 0 1 1 0 012 0zm-1-8a1 1 0 00-1 1v3a1 1 0 002 0V6a1 1 0 00-1-1z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\">Content was sanitized</h5><p class=\"text-sm text-amber-700 mt-1\" data-text=\"$refineTab.result.moderationNote || 'Some parts of the original note were adjusted for safety.'\"></p><!-- Original note (before sanitization) --><details class=\"bg-white border border-amber-200 rounded-lg p-3 group mt-3\"><summary class=\"flex items-center justify-between cursor-pointer\"><span class=\"flex items-center gap-2 text-sm font-semibold text-amber-800\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v8m-4-4h8\"></path></svg> View original note (flagged)</span> <svg class=\"w-4 h-4 text-amber-700 group-open:rotate-90 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></summary><p class=\"text-xs text-amber-700 mt-2\">Original text before sanitization:</p><div class=\"mt-2 bg-amber-50 border border-amber-200 rounded-lg p-3 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-text=\"$refineTab.result.originalNote\"></div></details></div></div></div><!-- Hard-blocked case: no originalNote and blocked == true --><div data-show=\"!$refineTab.result.originalNote && $refineTab.result.blocked\" class=\"bg-red-50 border border-red-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-red-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm-1-5h2v2H9v-2zm0-6h2v4H9V7z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-red-800\">Content blocked</h5><p class=\"text-sm text-red-700 mt-1\" data-text=\"$refineTab.result.moderationNote || 'This note was blocked by the safety filter.'\"></p><p class=\"text-xs text-red-700 mt-1\">No safe version could be generated from the original text.</p></div></div></div><!-- Passed case: no originalNote and blocked == false --><div data-show=\"!$refineTab.result.originalNote && !$refineTab.result.blocked && $refineTab.result.decision !== 'review'\" class=\"bg-emerald-50 border border-emerald-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-emerald-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-emerald-800\">Content Safety Check Passed</h5><p class=\"text-sm text-emerald-700 mt-1\">This note passed content safety filters. No changes were required.</p><p class=\"text-xs text-emerald-700 mt-1\" data-show=\"$refineTab.result.moderationNote\" data-text=\"$refineTab.result.moderationNote\"></p></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationReview("refineTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = ModerationDetails("refineTab").Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "</div><!-- Raw JSON for developers --><details class=\"mt-4 p-4 rounded-xl border border-[var(--accent)] bg-[var(--surface-soft)] shadow-sm\" data-show=\"$refineTab.resultJson\"><summary class=\"text-sm font-semibold cursor-pointer text-[var(--accent)]\">View Raw JSON Response</summary><pre class=\"mt-3 p-4 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"$refineTab.resultJson\"></pre></details></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// CandidatesView lists ranked candidates with their scores and lets the user pick one.
+// Datastar can't loop over signals, so a slot is rendered for each possible candidate.
+func CandidatesView(tabName string, moderated bool) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var35 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var35 == nil {
+			templ_7745c5c3_Var35 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "<div class=\"mb-6\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var36 string
+		templ_7745c5c3_Var36, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.candidates?.length > 1", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1471, Col: 88}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var36))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "\"><h4 class=\"font-semibold text-[var(--accent)] mb-3\">Candidates</h4><div class=\"space-y-3\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for i := range types.MaxCandidates {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "<div class=\"rounded-xl p-4 border border-[var(--border)] bg-white shadow-sm ring-[var(--accent)] transition-shadow\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var37 string
+			templ_7745c5c3_Var37, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "note") + " !== undefined")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1477, Col: 69}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var37))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "\" data-class:ring-2=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var38 string
+			templ_7745c5c3_Var38, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.selectedCandidate === %d", tabName, i))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1478, Col: 80}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var38))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "\"><div class=\"flex items-center justify-between gap-4 mb-2\"><div class=\"flex items-center gap-3\"><span class=\"inline-flex items-center justify-center w-7 h-7 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var39 string
+			templ_7745c5c3_Var39, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(i + 1))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1483, Col: 27}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var39))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "</span> <span class=\"text-sm font-semibold text-[var(--bg-contrast)]\">Score <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var40 string
+			templ_7745c5c3_Var40, templ_7745c5c3_Err = templ.JoinStringErrs("(" + candidateExpr(tabName, i, "score") + " ?? 0).toFixed(2)")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1486, Col: 94}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var40))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "\"></span></span> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if moderated {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "<span class=\"inline-flex items-center gap-1 px-3 py-1 rounded-full text-xs font-semibold bg-red-50 text-red-700\" data-show=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var41 string
+				templ_7745c5c3_Var41, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "blocked"))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1489, Col: 169}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var41))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "\">Blocked</span>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "</div><button type=\"button\" class=\"px-3 py-1.5 rounded-lg border border-[var(--accent)] text-xs font-semibold text-[var(--accent)] hover:bg-[var(--accent)] hover:text-white transition-colors disabled:opacity-50 disabled:cursor-not-allowed\" data-on:click=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var42 string
+			templ_7745c5c3_Var42, templ_7745c5c3_Err = templ.JoinStringErrs(pickCandidateExpr(tabName, i, moderated))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1497, Col: 63}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var42))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "\" data-attr:disabled=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var43 string
+			templ_7745c5c3_Var43, templ_7745c5c3_Err = templ.JoinStringErrs(candidateDisabledExpr(tabName, i, moderated))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1498, Col: 72}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var43))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "\"><span data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var44 string
+			templ_7745c5c3_Var44, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.selectedCandidate === %d", tabName, i))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1500, Col: 80}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var44))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "\">Selected</span> <span data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var45 string
+			templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.selectedCandidate !== %d", tabName, i))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1501, Col: 80}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var45))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "\">Use this note</span></button></div><p class=\"text-sm text-[var(--bg-contrast)] whitespace-pre-line line-clamp-4\" data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var46 string
+			templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "note"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1504, Col: 128}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "\"></p><div class=\"flex flex-wrap gap-2 mt-3 text-xs\"><span class=\"px-2 py-1 rounded-full bg-[var(--surface-soft)] text-[var(--muted)]\">Tone <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var47 string
+			templ_7745c5c3_Var47, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "scores?.tone"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1507, Col: 71}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "\"></span></span> <span class=\"px-2 py-1 rounded-full bg-[var(--surface-soft)] text-[var(--muted)]\">Length <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var48 string
+			templ_7745c5c3_Var48, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "scores?.length"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1510, Col: 75}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var48))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "\"></span></span> <span class=\"px-2 py-1 rounded-full bg-[var(--surface-soft)] text-[var(--muted)]\">Language <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var49 string
+			templ_7745c5c3_Var49, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "scores?.language"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1513, Col: 79}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var49))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "\"></span></span> <span class=\"px-2 py-1 rounded-full bg-violet-50 text-violet-700\">Judge <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var50 string
+			templ_7745c5c3_Var50, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "scores?.judge"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1516, Col: 73}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var50))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "\"></span></span> <span class=\"px-2 py-1 rounded-full bg-violet-50 text-violet-700\">Heuristics <span data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var51 string
+			templ_7745c5c3_Var51, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "scores?.heuristic"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1519, Col: 82}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var51))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "\"></span></span></div><p class=\"text-xs text-[var(--muted)] mt-2 italic\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var52 string
+			templ_7745c5c3_Var52, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "judgeComments"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1522, Col: 110}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var52))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "\" data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var53 string
+			templ_7745c5c3_Var53, templ_7745c5c3_Err = templ.JoinStringErrs(candidateExpr(tabName, i, "judgeComments"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1522, Col: 167}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var53))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "\"></p></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func PipelineView(tabName string, steps []PipelineStep) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var54 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var54 == nil {
+			templ_7745c5c3_Var54 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "<div data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var55 string
+		templ_7745c5c3_Var55, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.steps && $%s.steps.length > 0", tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1530, Col: 84}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var55))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "\" class=\"mt-8 animate-fade-in\"><div class=\"rounded-2xl p-6 card\"><h4 class=\"font-semibold text-[var(--accent)] mb-4 flex items-center\"><i class=\"fa-solid fa-diagram-project mr-2\"></i> Pipeline</h4><ol class=\"space-y-3\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for i, step := range steps {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "<li class=\"rounded-xl p-4 border border-[var(--border)] bg-white shadow-sm\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if step.Optional {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, " data-show=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var56 string
+				templ_7745c5c3_Var56, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "status"))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1541, Col: 55}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var56))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, "><div class=\"flex items-center justify-between gap-4\"><div class=\"flex items-center gap-3\"><span class=\"inline-flex items-center justify-center w-7 h-7 rounded-full bg-[var(--accent-soft)] text-[var(--accent-strong)] text-xs font-semibold\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var57 string
+			templ_7745c5c3_Var57, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(i + 1))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1547, Col: 28}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var57))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, "</span><div><div class=\"font-medium text-[var(--bg-contrast)]\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var58 string
+			templ_7745c5c3_Var58, templ_7745c5c3_Err = templ.JoinStringErrs(step.Label)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1550, Col: 72}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var58))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, "</div><div class=\"text-xs text-[var(--muted)] font-mono\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var59 string
+			templ_7745c5c3_Var59, templ_7745c5c3_Err = templ.JoinStringErrs(step.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1551, Col: 69}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var59))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, "</div></div></div><div class=\"flex items-center gap-3 text-sm\"><span class=\"text-[var(--muted)]\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var60 string
+			templ_7745c5c3_Var60, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "status") + " && " + stepExpr(tabName, step.ID, "status") + " !== 'running'")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1557, Col: 124}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var60))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, "\" data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var61 string
+			templ_7745c5c3_Var61, templ_7745c5c3_Err = templ.JoinStringErrs("(" + stepExpr(tabName, step.ID, "durationMs") + " ?? 0) + ' ms'")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1558, Col: 86}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var61))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 80, "\"></span> <span class=\"inline-flex items-center gap-1 px-3 py-1 rounded-full text-xs font-semibold bg-gray-100 text-gray-600\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var62 string
+			templ_7745c5c3_Var62, templ_7745c5c3_Err = templ.JoinStringErrs("!" + stepExpr(tabName, step.ID, "status"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1560, Col: 178}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var62))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 81, "\">Pending</span> <span class=\"inline-flex items-center gap-1 px-3 py-1 rounded-full text-xs font-semibold bg-sky-50 text-sky-700\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var63 string
+			templ_7745c5c3_Var63, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "status") + " === 'running'")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1563, Col: 188}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var63))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 82, "\"><i class=\"fas fa-circle-notch fa-spin\"></i> Running</span> <span class=\"inline-flex items-center gap-1 px-3 py-1 rounded-full text-xs font-semibold bg-emerald-50 text-emerald-700\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var64 string
+			templ_7745c5c3_Var64, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "status") + " === 'done'")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1567, Col: 193}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var64))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 83, "\"><i class=\"fas fa-check\"></i> Done</span> <span class=\"inline-flex items-center gap-1 px-3 py-1 rounded-full text-xs font-semibold bg-red-50 text-red-700\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var65 string
+			templ_7745c5c3_Var65, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "status") + " === 'failed'")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1571, Col: 187}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var65))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 84, "\"><i class=\"fas fa-xmark\"></i> Failed</span></div></div><p class=\"text-xs text-red-700 mt-2\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var66 string
+			templ_7745c5c3_Var66, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "error"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1579, Col: 54}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var66))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 85, "\" data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var67 string
+			templ_7745c5c3_Var67, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "error"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1580, Col: 54}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var67))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 86, "\"></p><details class=\"mt-2\" data-show=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var68 string
+			templ_7745c5c3_Var68, templ_7745c5c3_Err = templ.JoinStringErrs(stepExpr(tabName, step.ID, "output"))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1582, Col: 76}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var68))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 87, "\"><summary class=\"text-xs font-semibold cursor-pointer text-[var(--accent)]\">Step output</summary><pre class=\"mt-2 p-3 text-xs rounded-lg overflow-x-auto bg-[#0d1117] text-[#e6edf3] shadow-inner border border-[#30363d]\" data-text=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var69 string
+			templ_7745c5c3_Var69, templ_7745c5c3_Err = templ.JoinStringErrs("JSON.stringify(" + stepExpr(tabName, step.ID, "output") + ", null, 2)")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1586, Col: 91}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var69))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 88, "\"></pre></details></li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 89, "</ol></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// ModerationRemediation lists the attempts made after a note was blocked, and says when
+// the note shown is the template used because every attempt was blocked
+func ModerationRemediation(tabName string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var70 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var70 == nil {
+			templ_7745c5c3_Var70 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 90, "<div data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var71 string
+		templ_7745c5c3_Var71, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.remediation?.length", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1600, Col: 68}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var71))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 91, "\" class=\"bg-sky-50 border border-sky-200 rounded-xl p-4 mb-4\"><div class=\"flex items-start\"><i class=\"fas fa-rotate text-sky-600 mt-1 mr-3\"></i><div><h5 class=\"font-semibold text-sky-800\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var72 string
+		templ_7745c5c3_Var72, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.fallback ? 'Template note' : 'Regenerated after a blocked note'", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1608, Col: 115}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var72))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 92, "\"></h5><p class=\"text-sm text-sky-700 mt-1\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var73 string
+		templ_7745c5c3_Var73, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.fallback", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1610, Col: 96}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var73))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 93, "\">Every generated note was blocked, so a short template note was used instead.</p><ol class=\"text-xs text-sky-700 mt-2 space-y-1\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var74 string
+		templ_7745c5c3_Var74, templ_7745c5c3_Err = templ.JoinStringErrs(remediationEffectExpr(tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1613, Col: 96}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var74))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 94, "\"></ol></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// ModelsUsed names the model that wrote the note and the models each step called
+func ModelsUsed(tabName string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var75 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var75 == nil {
+			templ_7745c5c3_Var75 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 95, "<div class=\"text-xs text-[var(--muted)] -mt-3 mb-6\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var76 string
+		templ_7745c5c3_Var76, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.model", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1621, Col: 105}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var76))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 96, "\"><i class=\"fas fa-microchip mr-1\"></i> Written by <span class=\"font-mono\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var77 string
+		templ_7745c5c3_Var77, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.model", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1623, Col: 89}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var77))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 97, "\"></span><div class=\"font-mono mt-1 opacity-80\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var78 string
+		templ_7745c5c3_Var78, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("Object.entries($%s.result.models || {}).map(([step, model]) => step + ': ' + model).join(' · ')", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1626, Col: 135}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var78))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 98, "\"></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// ModerationReview flags a note held for a human to review and, once the note is in the
+// review queue, waits for the reviewer's decision
+func ModerationReview(tabName string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var79 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var79 == nil {
+			templ_7745c5c3_Var79 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 99, "<div data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var80 string
+		templ_7745c5c3_Var80, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.decision === 'review' || $%s.result.reviewId", tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1635, Col: 102}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var80))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 100, "\" class=\"bg-amber-50 border border-amber-200 rounded-xl p-4 mb-4\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var81 string
+		templ_7745c5c3_Var81, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.reviewId && @get('/api/review/' + $%s.result.reviewId + '?tab=%s')", tabName, tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1637, Col: 135}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var81))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 101, "\"><div class=\"flex items-start\"><svg class=\"w-5 h-5 text-amber-600 mt-0.5 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm1-12a1 1 0 10-2 0v4a1 1 0 00.293.707l2.828 2.829a1 1 0 101.415-1.415L11 9.586V6z\" clip-rule=\"evenodd\"></path></svg><div><h5 class=\"font-semibold text-amber-800\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var82 string
+		templ_7745c5c3_Var82, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.approval ? 'Waiting for approval' : 'Held for review'", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1648, Col: 145}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var82))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 102, "\"></h5><p class=\"text-sm text-amber-700 mt-1\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var83 string
+		templ_7745c5c3_Var83, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.approval", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1649, Col: 98}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var83))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 103, "\">This deployment releases a note only once an approver signs off on it. It is withheld until then.</p><p class=\"text-sm text-amber-700 mt-1\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var84 string
+		templ_7745c5c3_Var84, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.decision === 'review'", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1652, Col: 111}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var84))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 104, "\">This deployment's moderation policy wants a person to check this note before it is used.</p><p class=\"text-sm text-amber-700 mt-1\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var85 string
+		templ_7745c5c3_Var85, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.metadata?.safety === 'needs_review'", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1655, Col: 125}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var85))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 105, "\">The generator marked this note for a person to check before it is used.</p><p class=\"text-xs text-amber-700 mt-1\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var86 string
+		templ_7745c5c3_Var86, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.decisionReason", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1658, Col: 104}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var86))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 106, "\"></p><p class=\"text-sm text-amber-800 mt-2\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var87 string
+		templ_7745c5c3_Var87, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.reviewId && !$%s.review?.reviewedAt && !$%s.review?.error", tabName, tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1659, Col: 165}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var87))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 107, "\"><i class=\"fas fa-hourglass-half mr-1\"></i> Waiting for a reviewer in the review queue. This page updates when they decide.</p><p class=\"text-sm text-red-700 mt-2\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var88 string
+		templ_7745c5c3_Var88, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.review?.error", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1663, Col: 94}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var88))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 108, "\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var89 string
+		templ_7745c5c3_Var89, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.review?.error", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1663, Col: 150}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var89))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 109, "\"></p><div class=\"mt-3\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var90 string
+		templ_7745c5c3_Var90, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.review?.reviewedAt", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1664, Col: 80}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var90))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 110, "\"><p class=\"text-sm font-semibold text-amber-900\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var91 string
+		templ_7745c5c3_Var91, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("($%s.result.approval ? 'Approver ' : 'Reviewer ') + $%s.review?.reviewer + ' ' + $%s.review?.status + ' this note' + ($%s.review?.comment ? ': ' + $%s.review.comment : '')", tabName, tabName, tabName, tabName, tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1665, Col: 296}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var91))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 111, "\"></p><div class=\"mt-2 bg-white rounded-lg p-3 border border-amber-200 text-sm text-[var(--bg-contrast)] whitespace-pre-line\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var92 string
+		templ_7745c5c3_Var92, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.review?.finalNote", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1666, Col: 182}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var92))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 112, "\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var93 string
+		templ_7745c5c3_Var93, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.review?.finalNote", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1666, Col: 242}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var93))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 113, "\"></div></div></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// ModerationDetails shows the severity of each moderation category and the spans
+// sanitization changed, for the moderated tabs
+func ModerationDetails(tabName string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var94 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var94 == nil {
+			templ_7745c5c3_Var94 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 114, "<div class=\"rounded-xl p-4 mb-4 border border-[var(--border)] bg-white\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var95 string
+		templ_7745c5c3_Var95, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.categories?.length", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1678, Col: 67}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var95))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 115, "\"><h5 class=\"font-semibold text-[var(--bg-contrast)] mb-3\">Moderation categories</h5><p class=\"text-sm text-[var(--bg-contrast)] mb-3\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var96 string
+		templ_7745c5c3_Var96, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.decision", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1681, Col: 107}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var96))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 116, "\"><span class=\"font-semibold\">Decision:</span> <span class=\"uppercase\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var97 string
+		templ_7745c5c3_Var97, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.decision", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1683, Col: 82}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var97))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 117, "\"></span> <span class=\"opacity-70\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var98 string
+		templ_7745c5c3_Var98, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("'(' + $%s.result.decisionReason + ')'", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1684, Col: 101}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var98))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 118, "\"></span></p><div class=\"space-y-2 text-[var(--bg-contrast)]\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var99 string
+		templ_7745c5c3_Var99, templ_7745c5c3_Err = templ.JoinStringErrs(moderationCategoriesEffectExpr(tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1686, Col: 104}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var99))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 119, "\"></div><div class=\"mt-4\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var100 string
+		templ_7745c5c3_Var100, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.votes?.length", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1687, Col: 80}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var100))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 120, "\"><div class=\"text-xs font-semibold uppercase text-[var(--bg-contrast)] opacity-70 mb-2\">Moderator votes</div><ul class=\"space-y-1 text-sm text-[var(--bg-contrast)]\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var101 string
+		templ_7745c5c3_Var101, templ_7745c5c3_Err = templ.JoinStringErrs(votesEffectExpr(tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1689, Col: 97}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var101))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 121, "\"></ul></div><div class=\"mt-4\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var102 string
+		templ_7745c5c3_Var102, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.pivot", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1691, Col: 72}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var102))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 122, "\"><div class=\"text-xs font-semibold uppercase text-[var(--bg-contrast)] opacity-70 mb-2\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var103 string
+		templ_7745c5c3_Var103, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("'Also moderated in English, translated from ' + $%s.result.pivot?.language", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1694, Col: 114}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var103))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 123, "\"></div><p class=\"text-sm italic text-[var(--bg-contrast)]\" data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var104 string
+		templ_7745c5c3_Var104, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.pivot?.translation", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1696, Col: 120}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var104))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 124, "\"></p><p class=\"text-sm text-[var(--bg-contrast)] mt-1\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var105 string
+		templ_7745c5c3_Var105, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.pivot?.sanitizedTranslation", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1697, Col: 127}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var105))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 125, "\"><span class=\"font-semibold\">Rewritten as:</span> <span data-text=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var106 string
+		templ_7745c5c3_Var106, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.pivot?.sanitizedTranslation", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1699, Col: 84}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var106))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 126, "\"></span></p></div><div class=\"mt-4\" data-show=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var107 string
+		templ_7745c5c3_Var107, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("$%s.result.changedSpans?.length", tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1702, Col: 87}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var107))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 127, "\"><div class=\"text-xs font-semibold uppercase text-[var(--bg-contrast)] opacity-70 mb-2\">Changed spans</div><ul class=\"space-y-1 text-sm text-[var(--bg-contrast)]\" data-effect=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var108 string
+		templ_7745c5c3_Var108, templ_7745c5c3_Err = templ.JoinStringErrs(changedSpansEffectExpr(tabName))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/results.templ`, Line: 1704, Col: 104}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var108))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 128, "\"></ul></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
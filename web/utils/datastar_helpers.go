@@ -22,9 +22,19 @@ func SendSignalUpdateWithError(c *gin.Context, tabName string, errorMessage stri
 		status = statusCode[0]
 	}
 
+	SendSignalUpdateWithErrorCode(c, tabName, "", errorMessage, status)
+}
+
+// SendSignalUpdateWithErrorCode sends an error with a machine-readable code, such as
+// "tone_not_allowed". JSON clients get it as "code", Datastar as the tab's errorCode signal.
+func SendSignalUpdateWithErrorCode(c *gin.Context, tabName string, code string, errorMessage string, status int) {
 	isDatastar := c.GetHeader("Datastar-Request") == "true"
 	if !isDatastar {
-		c.JSON(status, gin.H{"error": errorMessage})
+		body := gin.H{"error": errorMessage}
+		if code != "" {
+			body["code"] = code
+		}
+		c.JSON(status, body)
 		return
 	}
 
@@ -32,8 +42,9 @@ func SendSignalUpdateWithError(c *gin.Context, tabName string, errorMessage stri
 	c.Status(status)
 	signals := map[string]interface{}{
 		tabName: map[string]interface{}{
-			"error":  errorMessage,
-			"result": "",
+			"error":     errorMessage,
+			"errorCode": code,
+			"result":    "",
 		}}
 	SendSignalUpdate(c, signals)
 }
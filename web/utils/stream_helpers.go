@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+const (
+	StreamFormatSSE    = "sse"
+	StreamFormatNDJSON = "ndjson"
+)
+
+// StreamFormat returns the streaming format a non-Datastar client asked for
+// through its Accept header, or "" if it wants a single JSON response
+func StreamFormat(c *gin.Context) string {
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, "text/event-stream"):
+		return StreamFormatSSE
+	case strings.Contains(accept, "application/x-ndjson"):
+		return StreamFormatNDJSON
+	}
+	return ""
+}
+
+// FlowStream forwards the partial results of a streaming flow to the client.
+// Datastar clients receive signal patches on a single SSE connection, JSON
+// clients receive "chunk", "result" and "error" events as text/event-stream
+// or NDJSON. The stream is opened lazily on the first chunk so that errors
+// raised before any output still get a regular HTTP status code.
+type FlowStream struct {
+	c          *gin.Context
+	tabName    string
+	isDatastar bool
+	format     string
+	sse        *datastar.ServerSentEventGenerator
+	started    bool
+}
+
+// NewFlowStream creates a FlowStream for the given tab signal
+func NewFlowStream(c *gin.Context, tabName string) *FlowStream {
+	return &FlowStream{
+		c:          c,
+		tabName:    tabName,
+		isDatastar: IsDatastarRequest(c),
+		format:     StreamFormat(c),
+	}
+}
+
+// Streaming reports whether the client receives chunks before the final result
+func (s *FlowStream) Streaming() bool {
+	return s.isDatastar || s.format != ""
+}
+
+// Chunk sends a partial result. Datastar clients get tabSignals patched under
+// the tab name, JSON clients get chunk as the event payload.
+func (s *FlowStream) Chunk(tabSignals map[string]interface{}, chunk any) error {
+	if !s.Streaming() {
+		return nil
+	}
+	s.start()
+	if s.isDatastar {
+		return s.sse.MarshalAndPatchSignals(map[string]interface{}{s.tabName: tabSignals})
+	}
+	return s.writeEvent("chunk", chunk)
+}
+
+// Result sends the final result signals and ends the stream
+func (s *FlowStream) Result(signals map[string]interface{}) {
+	if s.isDatastar {
+		s.start()
+		s.sse.MarshalAndPatchSignals(signals)
+		return
+	}
+	if s.format == "" {
+		s.c.JSON(http.StatusOK, signals[s.tabName])
+		return
+	}
+	s.start()
+	s.writeEvent("result", signals[s.tabName])
+}
+
+// Error reports a failure. Before the stream has started this behaves like
+// SendSignalUpdateWithError; afterwards the error is sent on the open stream.
+func (s *FlowStream) Error(errorMessage string) {
+	if !s.started {
+		SendSignalUpdateWithError(s.c, s.tabName, errorMessage)
+		return
+	}
+	if errorMessage == "" {
+		errorMessage = "error while generating note"
+	}
+	if s.isDatastar {
+		s.sse.MarshalAndPatchSignals(map[string]interface{}{
+			s.tabName: map[string]interface{}{
+				"error":     errorMessage,
+				"result":    "",
+				"streaming": false,
+			},
+		})
+		return
+	}
+	s.writeEvent("error", gin.H{"error": errorMessage})
+}
+
+func (s *FlowStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	if s.isDatastar {
+		s.sse = datastar.NewSSE(s.c.Writer, s.c.Request)
+		return
+	}
+
+	header := s.c.Writer.Header()
+	header.Set("Cache-Control", "no-cache")
+	if s.format == StreamFormatSSE {
+		header.Set("Content-Type", "text/event-stream")
+	} else {
+		header.Set("Content-Type", "application/x-ndjson")
+	}
+	s.c.Status(http.StatusOK)
+	s.c.Writer.Flush()
+}
+
+type ndjsonEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func (s *FlowStream) writeEvent(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if s.format == StreamFormatSSE {
+		_, err = fmt.Fprintf(s.c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+	} else {
+		line, _ := json.Marshal(ndjsonEvent{Event: event, Data: payload})
+		_, err = fmt.Fprintf(s.c.Writer, "%s\n", line)
+	}
+	if err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}